@@ -0,0 +1,16 @@
+package cache
+
+import (
+	"context"
+	"time"
+)
+
+//IBackend stores encoded values by key until their ttl runs out, it may drop any entry earlier.
+//Implementations must be safe for concurrent use, a shared backend such as redis fits behind it
+//as long as DeletePrefix can be answered
+type IBackend interface {
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	Delete(ctx context.Context, keys ...string) error
+	DeletePrefix(ctx context.Context, prefix string) error
+}
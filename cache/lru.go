@@ -0,0 +1,116 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"strings"
+	"sync"
+	"time"
+)
+
+//LRU is an in-process backend holding at most capacity entries, the least recently used entry is evicted
+//to make room and expired entries are dropped when read
+type LRU struct {
+	mutex     sync.Mutex
+	capacity  int
+	entries   map[string]*list.Element
+	order     *list.List
+	evictions uint64
+	now       func() time.Time
+}
+
+type lruEntry struct {
+	key       string
+	value     []byte
+	expiresAt time.Time
+}
+
+//InitLRU initializes an empty LRU holding at most capacity entries, capacity must be positive
+func InitLRU(capacity int) *LRU {
+	lru := new(LRU)
+	lru.capacity = capacity
+	lru.entries = map[string]*list.Element{}
+	lru.order = list.New()
+	lru.now = time.Now
+	return lru
+}
+
+//Get returns the value of key unless it is missing or expired, a hit makes the entry the most recently used
+func (l *LRU) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	element, found := l.entries[key]
+	if !found {
+		return nil, false, nil
+	}
+	entry := element.Value.(*lruEntry)
+	if !l.now().Before(entry.expiresAt) {
+		l.remove(element)
+		return nil, false, nil
+	}
+	l.order.MoveToFront(element)
+	return entry.value, true, nil
+}
+
+//Set stores the value of key for ttl, evicting the least recently used entry when the LRU is full
+func (l *LRU) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	expiresAt := l.now().Add(ttl)
+	if element, found := l.entries[key]; found {
+		entry := element.Value.(*lruEntry)
+		entry.value = value
+		entry.expiresAt = expiresAt
+		l.order.MoveToFront(element)
+		return nil
+	}
+	for l.order.Len() >= l.capacity && l.order.Len() > 0 {
+		l.remove(l.order.Back())
+		l.evictions++
+	}
+	l.entries[key] = l.order.PushFront(&lruEntry{key: key, value: value, expiresAt: expiresAt})
+	return nil
+}
+
+//Delete removes the entries of keys, missing keys are ignored
+func (l *LRU) Delete(ctx context.Context, keys ...string) error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	for _, key := range keys {
+		if element, found := l.entries[key]; found {
+			l.remove(element)
+		}
+	}
+	return nil
+}
+
+//DeletePrefix removes every entry whose key starts with prefix
+func (l *LRU) DeletePrefix(ctx context.Context, prefix string) error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	for key, element := range l.entries {
+		if strings.HasPrefix(key, prefix) {
+			l.remove(element)
+		}
+	}
+	return nil
+}
+
+//Len returns the number of entries held, expired entries included until they are read or evicted
+func (l *LRU) Len() int {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	return l.order.Len()
+}
+
+//Evictions returns how many entries were evicted to make room since the LRU was initialized
+func (l *LRU) Evictions() uint64 {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	return l.evictions
+}
+
+func (l *LRU) remove(element *list.Element) {
+	l.order.Remove(element)
+	delete(l.entries, element.Value.(*lruEntry).key)
+}
@@ -0,0 +1,73 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func initTestLRU(capacity int, now *time.Time) *LRU {
+	lru := InitLRU(capacity)
+	lru.now = func() time.Time { return *now }
+	return lru
+}
+
+func TestLRUGetShouldReturnStoredValue(t *testing.T) {
+	now := time.Date(2026, time.October, 19, 0, 0, 0, 0, time.UTC)
+	lru := initTestLRU(2, &now)
+	ctx := context.Background()
+	assert.Nil(t, lru.Set(ctx, "news:detail:1", []byte("bitcoin"), time.Minute))
+	value, found, err := lru.Get(ctx, "news:detail:1")
+	assert.Nil(t, err)
+	assert.True(t, found)
+	assert.Equal(t, []byte("bitcoin"), value)
+	_, found, _ = lru.Get(ctx, "news:detail:2")
+	assert.False(t, found)
+}
+
+func TestLRUExpiredEntryShouldBeMissing(t *testing.T) {
+	now := time.Date(2026, time.October, 19, 0, 0, 0, 0, time.UTC)
+	lru := initTestLRU(2, &now)
+	ctx := context.Background()
+	lru.Set(ctx, "news:detail:1", []byte("bitcoin"), time.Minute)
+	now = now.Add(time.Minute)
+	_, found, _ := lru.Get(ctx, "news:detail:1")
+	assert.False(t, found, "the entry should expire after its ttl")
+	assert.Equal(t, 0, lru.Len(), "the expired entry should be dropped")
+}
+
+func TestLRUFullShouldEvictLeastRecentlyUsed(t *testing.T) {
+	now := time.Date(2026, time.October, 19, 0, 0, 0, 0, time.UTC)
+	lru := initTestLRU(2, &now)
+	ctx := context.Background()
+	lru.Set(ctx, "a", []byte("a"), time.Minute)
+	lru.Set(ctx, "b", []byte("b"), time.Minute)
+	lru.Get(ctx, "a")
+	lru.Set(ctx, "c", []byte("c"), time.Minute)
+	_, found, _ := lru.Get(ctx, "b")
+	assert.False(t, found, "b was used least recently")
+	_, found, _ = lru.Get(ctx, "a")
+	assert.True(t, found)
+	assert.Equal(t, 2, lru.Len())
+	assert.Equal(t, uint64(1), lru.Evictions())
+
+	lru.Set(ctx, "a", []byte("A"), time.Minute)
+	assert.Equal(t, uint64(1), lru.Evictions(), "replacing a value should not evict")
+}
+
+func TestLRUDeleteShouldRemoveKeysAndPrefixes(t *testing.T) {
+	now := time.Date(2026, time.October, 19, 0, 0, 0, 0, time.UTC)
+	lru := initTestLRU(10, &now)
+	ctx := context.Background()
+	lru.Set(ctx, "news:detail:1", []byte("1"), time.Minute)
+	lru.Set(ctx, "news:detail:2", []byte("2"), time.Minute)
+	lru.Set(ctx, "news:list:", []byte("[]"), time.Minute)
+	lru.Set(ctx, "news:list:status=published", []byte("[]"), time.Minute)
+	assert.Nil(t, lru.Delete(ctx, "news:detail:1", "missing"))
+	assert.Nil(t, lru.DeletePrefix(ctx, "news:list:"))
+	assert.Equal(t, 1, lru.Len())
+	_, found, _ := lru.Get(ctx, "news:detail:2")
+	assert.True(t, found)
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"news-topic-api/config"
+	"news-topic-api/infrastructures"
+	"news-topic-api/models"
+	"os"
+
+	"gorm.io/gorm"
+)
+
+//Exit codes shared by every command
+const (
+	exitOK      = 0
+	exitFailure = 1
+	exitUsage   = 2
+)
+
+//cliActor is recorded as the actor of the changes made from the command line
+const cliActor = "cli"
+
+type command struct {
+	name    string
+	summary string
+	run     func(args []string) int
+}
+
+func commands() []command {
+	return []command{
+		{name: "serve", summary: "run the http api, the default when no command is given", run: runServe},
+		{name: "migrate", summary: "apply, revert, list or create schema migrations", run: runMigrate},
+		{name: "seed", summary: "fill the database with sample topics, tags and news", run: runSeed},
+		{name: "export", summary: "write news as json", run: runExport},
+		{name: "reindex", summary: "rebuild the indexes of the application tables", run: runReindex},
+		{name: "tag", summary: "manage tags, e.g. tag merge", run: runTag},
+	}
+}
+
+func printUsage() {
+	fmt.Fprint(os.Stderr, "Usage: news-topic-api [command] [flags]\n\nCommands:\n")
+	for _, cmd := range commands() {
+		fmt.Fprintf(os.Stderr, "  %-10s %s\n", cmd.name, cmd.summary)
+	}
+	fmt.Fprint(os.Stderr, "\nRun news-topic-api <command> --help for the flags of a command.\n")
+}
+
+//newFlagSet returns a flag set printing usage followed by its flags on --help or bad flags
+func newFlagSet(name string, usage string) *flag.FlagSet {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprint(os.Stderr, usage)
+		flags.PrintDefaults()
+	}
+	return flags
+}
+
+//parseFlags parses args, ok is false when the command must stop with the returned exit code
+func parseFlags(flags *flag.FlagSet, args []string) (exitCode int, ok bool) {
+	if err := flags.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return exitOK, false
+		}
+		return exitUsage, false
+	}
+	return exitOK, true
+}
+
+//openDatabase loads the configuration and opens the database pool the way the server does,
+//callers close it with infrastructures.CloseDB
+func openDatabase() (config.Config, *gorm.DB, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return config.Config{}, nil, err
+	}
+	db, err := infrastructures.InitDB(cfg.Database)
+	if err != nil {
+		return config.Config{}, nil, fmt.Errorf("unable to open the database: %w", err)
+	}
+	return cfg, db, nil
+}
+
+//openStorage loads the configuration and opens the database, unless the memory backend is configured and needs none
+func openStorage() (config.Config, *gorm.DB, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return config.Config{}, nil, err
+	}
+	if cfg.Storage.Backend == config.StorageMemory {
+		return cfg, nil, nil
+	}
+	db, err := infrastructures.InitDB(cfg.Database)
+	if err != nil {
+		return config.Config{}, nil, fmt.Errorf("unable to open the database: %w", err)
+	}
+	return cfg, db, nil
+}
+
+func cliAuditMeta() models.AuditMeta {
+	return models.AuditMeta{Actor: cliActor}
+}
+
+func fail(err error) int {
+	fmt.Fprintln(os.Stderr, err)
+	return exitFailure
+}
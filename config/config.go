@@ -0,0 +1,454 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/magiconair/properties"
+)
+
+//DefaultFile is the configuration file read when CONFIG_FILE is not set, it may be missing
+const DefaultFile = "config.properties"
+
+//RouteGroups are the route groups that have their own rate limit and request timeout
+var RouteGroups = []string{"news", "tag", "author", "apikey", "audit"}
+
+var defaultRateLimits = map[string]RateLimitGroupConfig{
+	"news":   {Rate: 5, Burst: 20},
+	"tag":    {Rate: 10, Burst: 30},
+	"author": {Rate: 10, Burst: 30},
+	"apikey": {Rate: 1, Burst: 5},
+	"audit":  {Rate: 2, Burst: 10},
+}
+
+//legacyEnv maps the environment variables used before the configuration file to their keys
+var legacyEnv = map[string]string{
+	"PORT":              "server.port",
+	"DATABASE_URL":      "database.url",
+	"postgres_username": "database.username",
+	"postgres_password": "database.password",
+	"postgres_dbname":   "database.name",
+	"postgres_host":     "database.host",
+}
+
+//Config is the typed configuration of the service
+type Config struct {
+	Server         ServerConfig
+	CORS           CORSConfig
+	Database       DatabaseConfig
+	JWT            JWTConfig
+	RateLimit      RateLimitConfig
+	RequestTimeout RequestTimeoutConfig
+	Unversioned    UnversionedConfig
+	Storage        StorageConfig
+	NewsCache      NewsCacheConfig
+}
+
+//ServerConfig holds the http server settings
+type ServerConfig struct {
+	Port               int
+	TrustProxy         bool
+	ReadHeaderTimeout  time.Duration
+	ReadTimeout        time.Duration
+	WriteTimeout       time.Duration
+	IdleTimeout        time.Duration
+	ReadinessTimeout   time.Duration
+	ShutdownDrainDelay time.Duration
+	ShutdownTimeout    time.Duration
+}
+
+//CORSConfig holds the cross origin settings
+type CORSConfig struct {
+	AllowedOrigins []string
+}
+
+//DatabaseConfig holds the postgres connection and pool settings
+type DatabaseConfig struct {
+	URL             string
+	Host            string
+	Port            int
+	Username        string
+	Password        string
+	Name            string
+	SSLMode         string
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+	ConnMaxIdleTime time.Duration
+	AutoMigrate     bool
+}
+
+//DSN returns the connection string, an explicit url takes precedence over the separate settings
+func (d DatabaseConfig) DSN() string {
+	if d.URL != "" {
+		return d.URL
+	}
+	return fmt.Sprintf("user=%s password=%s dbname=%s sslmode=%s host=%s port=%d", d.Username, d.Password, d.Name, d.SSLMode, d.Host, d.Port)
+}
+
+//JWTConfig holds the rules used to validate bearer tokens
+type JWTConfig struct {
+	Secret    string
+	Issuer    string
+	Audience  string
+	ClockSkew time.Duration
+	MaxTTL    time.Duration
+}
+
+//RateLimitConfig holds the token bucket settings of every route group, and of the per client ip bucket
+//every request goes through before its credentials are checked
+type RateLimitConfig struct {
+	IdleTTL time.Duration
+	IP      RateLimitGroupConfig
+	Groups  map[string]RateLimitGroupConfig
+}
+
+//RequestTimeoutConfig holds how long the handlers of every route group may run, a timeout of 0 disables it
+type RequestTimeoutConfig struct {
+	Default time.Duration
+	Groups  map[string]time.Duration
+}
+
+//UnversionedConfig holds how the routes served without a version prefix are retired, they stay enabled
+//as deprecated aliases of /v1 until their sunset
+type UnversionedConfig struct {
+	Enabled      bool
+	DeprecatedAt time.Time
+	Sunset       time.Time
+}
+
+//Storage backends of the api data
+const (
+	StoragePostgres = "postgres"
+	StorageMemory   = "memory"
+)
+
+//StorageConfig holds where the data of the api is kept, the memory backend needs no database and loses it on restart
+type StorageConfig struct {
+	Backend string
+}
+
+//NewsCacheConfig holds the in-process cache of news details and lists, a capacity of 0 disables it
+type NewsCacheConfig struct {
+	Capacity int
+	TTL      time.Duration
+}
+
+//RateLimitGroupConfig holds the token bucket settings of one route group, a rate of 0 disables it
+type RateLimitGroupConfig struct {
+	Rate  float64
+	Burst int
+}
+
+//Load reads the configuration file named by CONFIG_FILE, or config.properties when present,
+//applies environment overrides and validates the result
+func Load() (Config, error) {
+	path, explicit := os.LookupEnv("CONFIG_FILE")
+	if !explicit {
+		path = DefaultFile
+	}
+	p := properties.NewProperties()
+	if _, err := os.Stat(path); err == nil || explicit {
+		loaded, err := properties.LoadFile(path, properties.UTF8)
+		if err != nil {
+			return Config{}, fmt.Errorf("unable to read configuration file: %w", err)
+		}
+		p = loaded
+	}
+	return FromProperties(p, os.Environ())
+}
+
+//FromProperties builds the configuration from p overridden by environ, a list of KEY=value pairs.
+//A key such as database.max_open_conns is overridden by DATABASE_MAX_OPEN_CONNS.
+func FromProperties(p *properties.Properties, environ []string) (Config, error) {
+	overridden := p.FilterPrefix("")
+	overridden.DisableExpansion = true
+	for _, pair := range environ {
+		parts := strings.SplitN(pair, "=", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		if key, found := legacyEnv[parts[0]]; found {
+			overridden.Set(key, parts[1])
+		}
+	}
+	for _, pair := range environ {
+		parts := strings.SplitN(pair, "=", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		if key, found := knownKeys()[parts[0]]; found {
+			overridden.Set(key, parts[1])
+		}
+	}
+	r := &reader{p: overridden}
+	config := Config{
+		Server: ServerConfig{
+			Port:               r.int("server.port", 8080),
+			TrustProxy:         r.bool("server.trust_proxy", false),
+			ReadHeaderTimeout:  r.duration("server.read_header_timeout", 10*time.Second),
+			ReadTimeout:        r.duration("server.read_timeout", 30*time.Second),
+			WriteTimeout:       r.duration("server.write_timeout", 30*time.Second),
+			IdleTimeout:        r.duration("server.idle_timeout", 2*time.Minute),
+			ReadinessTimeout:   r.duration("server.readiness_timeout", 2*time.Second),
+			ShutdownDrainDelay: r.duration("server.shutdown_drain_delay", 5*time.Second),
+			ShutdownTimeout:    r.duration("server.shutdown_timeout", 15*time.Second),
+		},
+		CORS: CORSConfig{
+			AllowedOrigins: r.list("cors.allowed_origins", []string{"*"}),
+		},
+		Database: DatabaseConfig{
+			URL:             r.string("database.url", ""),
+			Host:            r.string("database.host", "localhost"),
+			Port:            r.int("database.port", 5432),
+			Username:        r.string("database.username", "postgres"),
+			Password:        r.string("database.password", "postgres"),
+			Name:            r.string("database.name", "postgres"),
+			SSLMode:         r.string("database.sslmode", "disable"),
+			MaxOpenConns:    r.int("database.max_open_conns", 20),
+			MaxIdleConns:    r.int("database.max_idle_conns", 5),
+			ConnMaxLifetime: r.duration("database.conn_max_lifetime", 30*time.Minute),
+			ConnMaxIdleTime: r.duration("database.conn_max_idle_time", 5*time.Minute),
+			AutoMigrate:     r.bool("database.auto_migrate", true),
+		},
+		JWT: JWTConfig{
+			Secret:    r.string("jwt.secret", ""),
+			Issuer:    r.string("jwt.issuer", ""),
+			Audience:  r.string("jwt.audience", ""),
+			ClockSkew: r.duration("jwt.clock_skew", 30*time.Second),
+			MaxTTL:    r.duration("jwt.max_ttl", 0),
+		},
+		RateLimit: RateLimitConfig{
+			IdleTTL: r.duration("rate_limit.idle_ttl", 10*time.Minute),
+			IP: RateLimitGroupConfig{
+				Rate:  r.float("rate_limit.ip.rps", 20),
+				Burst: r.int("rate_limit.ip.burst", 60),
+			},
+			Groups: map[string]RateLimitGroupConfig{},
+		},
+	}
+	for _, group := range RouteGroups {
+		defaults := defaultRateLimits[group]
+		config.RateLimit.Groups[group] = RateLimitGroupConfig{
+			Rate:  r.float("rate_limit."+group+".rps", defaults.Rate),
+			Burst: r.int("rate_limit."+group+".burst", defaults.Burst),
+		}
+	}
+	config.RequestTimeout = RequestTimeoutConfig{
+		Default: r.duration("request_timeout.default", 10*time.Second),
+		Groups:  map[string]time.Duration{},
+	}
+	for _, group := range RouteGroups {
+		config.RequestTimeout.Groups[group] = r.duration("request_timeout."+group, config.RequestTimeout.Default)
+	}
+	config.Unversioned = UnversionedConfig{
+		Enabled:      r.bool("unversioned.enabled", true),
+		DeprecatedAt: r.date("unversioned.deprecated_at", time.Date(2026, time.November, 1, 0, 0, 0, 0, time.UTC)),
+		Sunset:       r.date("unversioned.sunset", time.Date(2027, time.May, 1, 0, 0, 0, 0, time.UTC)),
+	}
+	config.Storage = StorageConfig{
+		Backend: r.string("storage.backend", StoragePostgres),
+	}
+	config.NewsCache = NewsCacheConfig{
+		Capacity: r.int("news_cache.capacity", 1000),
+		TTL:      r.duration("news_cache.ttl", 30*time.Second),
+	}
+	problems := append(r.problems, config.validate()...)
+	if len(problems) > 0 {
+		return Config{}, ValidationError{Problems: problems}
+	}
+	return config, nil
+}
+
+//ValidationError lists every problem found in the configuration
+type ValidationError struct {
+	Problems []string
+}
+
+func (v ValidationError) Error() string {
+	return "invalid configuration:\n  " + strings.Join(v.Problems, "\n  ")
+}
+
+func (c Config) validate() []string {
+	var problems []string
+	check := func(ok bool, format string, args ...interface{}) {
+		if !ok {
+			problems = append(problems, fmt.Sprintf(format, args...))
+		}
+	}
+	check(c.Server.Port > 0 && c.Server.Port <= 65535, "server.port must be between 1 and 65535, got %d", c.Server.Port)
+	check(c.Server.ReadHeaderTimeout > 0, "server.read_header_timeout must be positive")
+	check(c.Server.ReadTimeout >= 0, "server.read_timeout must not be negative")
+	check(c.Server.WriteTimeout >= 0, "server.write_timeout must not be negative")
+	check(c.Server.IdleTimeout >= 0, "server.idle_timeout must not be negative")
+	check(c.Server.ReadinessTimeout > 0, "server.readiness_timeout must be positive")
+	check(c.Server.ShutdownDrainDelay >= 0, "server.shutdown_drain_delay must not be negative")
+	check(c.Server.ShutdownTimeout > 0, "server.shutdown_timeout must be positive")
+	check(len(c.CORS.AllowedOrigins) > 0, "cors.allowed_origins must list at least one origin")
+	if c.Database.URL == "" {
+		check(c.Database.Host != "", "database.host is required when database.url is not set")
+		check(c.Database.Name != "", "database.name is required when database.url is not set")
+		check(c.Database.Username != "", "database.username is required when database.url is not set")
+		check(c.Database.Port > 0 && c.Database.Port <= 65535, "database.port must be between 1 and 65535, got %d", c.Database.Port)
+	}
+	check(c.Database.MaxOpenConns >= 0, "database.max_open_conns must not be negative, 0 is unlimited")
+	check(c.Database.MaxIdleConns >= 0, "database.max_idle_conns must not be negative")
+	check(c.Database.MaxOpenConns == 0 || c.Database.MaxIdleConns <= c.Database.MaxOpenConns,
+		"database.max_idle_conns (%d) must not exceed database.max_open_conns (%d)", c.Database.MaxIdleConns, c.Database.MaxOpenConns)
+	check(c.Database.ConnMaxLifetime >= 0, "database.conn_max_lifetime must not be negative")
+	check(c.Database.ConnMaxIdleTime >= 0, "database.conn_max_idle_time must not be negative")
+	check(c.JWT.Secret == "" || len(c.JWT.Secret) >= 32, "jwt.secret must be at least 32 bytes long")
+	check(c.JWT.ClockSkew >= 0, "jwt.clock_skew must not be negative")
+	check(c.JWT.MaxTTL >= 0, "jwt.max_ttl must not be negative")
+	check(c.RateLimit.IdleTTL > 0, "rate_limit.idle_ttl must be positive")
+	check(c.RateLimit.IP.Rate >= 0, "rate_limit.ip.rps must not be negative")
+	check(c.RateLimit.IP.Rate == 0 || c.RateLimit.IP.Burst >= 1, "rate_limit.ip.burst must be at least 1")
+	for _, group := range RouteGroups {
+		limit := c.RateLimit.Groups[group]
+		check(limit.Rate >= 0, "rate_limit.%s.rps must not be negative", group)
+		check(limit.Rate == 0 || limit.Burst >= 1, "rate_limit.%s.burst must be at least 1", group)
+	}
+	check(c.RequestTimeout.Default >= 0, "request_timeout.default must not be negative, 0 disables it")
+	for _, group := range RouteGroups {
+		timeout := c.RequestTimeout.Groups[group]
+		check(timeout >= 0, "request_timeout.%s must not be negative, 0 disables it", group)
+		check(timeout < c.Server.WriteTimeout || c.Server.WriteTimeout == 0,
+			"request_timeout.%s (%s) must be shorter than server.write_timeout (%s) for the timeout to be answered", group, timeout, c.Server.WriteTimeout)
+	}
+	check(c.Storage.Backend == StoragePostgres || c.Storage.Backend == StorageMemory,
+		"storage.backend must be %s or %s, got %q", StoragePostgres, StorageMemory, c.Storage.Backend)
+	check(c.NewsCache.Capacity >= 0, "news_cache.capacity must not be negative, 0 disables the cache")
+	check(c.NewsCache.Capacity == 0 || c.NewsCache.TTL > 0, "news_cache.ttl must be positive")
+	check(c.Unversioned.Sunset.After(c.Unversioned.DeprecatedAt), "unversioned.sunset must come after unversioned.deprecated_at")
+	return problems
+}
+
+//knownKeys maps the environment variable overriding each configuration key to the key
+func knownKeys() map[string]string {
+	keys := []string{
+		"server.port", "server.trust_proxy", "server.read_header_timeout", "server.read_timeout",
+		"server.write_timeout", "server.idle_timeout", "server.readiness_timeout",
+		"server.shutdown_drain_delay", "server.shutdown_timeout",
+		"cors.allowed_origins",
+		"database.url", "database.host", "database.port", "database.username", "database.password",
+		"database.name", "database.sslmode", "database.max_open_conns", "database.max_idle_conns",
+		"database.conn_max_lifetime", "database.conn_max_idle_time", "database.auto_migrate",
+		"jwt.secret", "jwt.issuer", "jwt.audience", "jwt.clock_skew", "jwt.max_ttl",
+		"rate_limit.idle_ttl", "rate_limit.ip.rps", "rate_limit.ip.burst", "request_timeout.default",
+		"unversioned.enabled", "unversioned.deprecated_at", "unversioned.sunset",
+		"storage.backend", "news_cache.capacity", "news_cache.ttl",
+	}
+	for _, group := range RouteGroups {
+		keys = append(keys, "rate_limit."+group+".rps", "rate_limit."+group+".burst", "request_timeout."+group)
+	}
+	envKeys := map[string]string{}
+	for _, key := range keys {
+		envKeys[EnvName(key)] = key
+	}
+	return envKeys
+}
+
+//EnvName returns the environment variable overriding a configuration key
+func EnvName(key string) string {
+	return strings.ToUpper(strings.Replace(key, ".", "_", -1))
+}
+
+//reader reads typed values and collects every malformed one instead of stopping at the first
+type reader struct {
+	p        *properties.Properties
+	problems []string
+}
+
+func (r *reader) raw(key string) (string, bool) {
+	value, found := r.p.Get(key)
+	return strings.TrimSpace(value), found
+}
+
+func (r *reader) string(key string, fallback string) string {
+	if value, found := r.raw(key); found {
+		return value
+	}
+	return fallback
+}
+
+func (r *reader) int(key string, fallback int) int {
+	value, found := r.raw(key)
+	if !found {
+		return fallback
+	}
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		r.problems = append(r.problems, fmt.Sprintf("%s must be an integer, got %q", key, value))
+		return fallback
+	}
+	return parsed
+}
+
+func (r *reader) float(key string, fallback float64) float64 {
+	value, found := r.raw(key)
+	if !found {
+		return fallback
+	}
+	parsed, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		r.problems = append(r.problems, fmt.Sprintf("%s must be a number, got %q", key, value))
+		return fallback
+	}
+	return parsed
+}
+
+func (r *reader) bool(key string, fallback bool) bool {
+	value, found := r.raw(key)
+	if !found {
+		return fallback
+	}
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		r.problems = append(r.problems, fmt.Sprintf("%s must be true or false, got %q", key, value))
+		return fallback
+	}
+	return parsed
+}
+
+func (r *reader) duration(key string, fallback time.Duration) time.Duration {
+	value, found := r.raw(key)
+	if !found {
+		return fallback
+	}
+	parsed, err := time.ParseDuration(value)
+	if err != nil {
+		r.problems = append(r.problems, fmt.Sprintf("%s must be a duration such as 30s or 5m, got %q", key, value))
+		return fallback
+	}
+	return parsed
+}
+
+func (r *reader) date(key string, fallback time.Time) time.Time {
+	value, found := r.raw(key)
+	if !found {
+		return fallback
+	}
+	parsed, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		r.problems = append(r.problems, fmt.Sprintf("%s must be a date such as 2027-05-01, got %q", key, value))
+		return fallback
+	}
+	return parsed
+}
+
+func (r *reader) list(key string, fallback []string) []string {
+	value, found := r.raw(key)
+	if !found {
+		return fallback
+	}
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
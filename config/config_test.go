@@ -0,0 +1,166 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/magiconair/properties"
+	"github.com/stretchr/testify/assert"
+)
+
+func loadProperties(t *testing.T, content string) *properties.Properties {
+	p, err := properties.LoadString(content)
+	assert.Nil(t, err, "properties should parse")
+	return p
+}
+
+func TestFromPropertiesWithoutValuesShouldUseDefaults(t *testing.T) {
+	config, err := FromProperties(properties.NewProperties(), nil)
+	assert.Nil(t, err, "There should be no error")
+	assert.Equal(t, 8080, config.Server.Port)
+	assert.Equal(t, []string{"*"}, config.CORS.AllowedOrigins)
+	assert.Equal(t, 15*time.Second, config.Server.ShutdownTimeout)
+	assert.Equal(t, "user=postgres password=postgres dbname=postgres sslmode=disable host=localhost port=5432", config.Database.DSN())
+	assert.Equal(t, RateLimitGroupConfig{Rate: 5, Burst: 20}, config.RateLimit.Groups["news"])
+	assert.Equal(t, RateLimitGroupConfig{Rate: 20, Burst: 60}, config.RateLimit.IP)
+	assert.Equal(t, 10*time.Second, config.RequestTimeout.Groups["news"])
+	assert.True(t, config.Unversioned.Enabled)
+	assert.True(t, config.Unversioned.Sunset.After(config.Unversioned.DeprecatedAt))
+	assert.Equal(t, StoragePostgres, config.Storage.Backend)
+	assert.Equal(t, NewsCacheConfig{Capacity: 1000, TTL: 30 * time.Second}, config.NewsCache)
+}
+
+func TestFromPropertiesShouldReadFile(t *testing.T) {
+	p := loadProperties(t, `
+server.port = 9000
+server.trust_proxy = true
+cors.allowed_origins = https://news.example.com, https://admin.example.com
+database.max_open_conns = 50
+database.max_idle_conns = 10
+database.conn_max_lifetime = 1h
+rate_limit.news.rps = 2.5
+request_timeout.default = 5s
+request_timeout.audit = 20s
+`)
+	config, err := FromProperties(p, nil)
+	assert.Nil(t, err, "There should be no error")
+	assert.Equal(t, 9000, config.Server.Port)
+	assert.True(t, config.Server.TrustProxy)
+	assert.Equal(t, []string{"https://news.example.com", "https://admin.example.com"}, config.CORS.AllowedOrigins)
+	assert.Equal(t, 50, config.Database.MaxOpenConns)
+	assert.Equal(t, time.Hour, config.Database.ConnMaxLifetime)
+	assert.Equal(t, RateLimitGroupConfig{Rate: 2.5, Burst: 20}, config.RateLimit.Groups["news"])
+	assert.Equal(t, 5*time.Second, config.RequestTimeout.Groups["news"])
+	assert.Equal(t, 20*time.Second, config.RequestTimeout.Groups["audit"])
+}
+
+func TestFromPropertiesEnvShouldOverrideFile(t *testing.T) {
+	p := loadProperties(t, "server.port = 9000\ndatabase.url = postgres://file\n")
+	config, err := FromProperties(p, []string{"SERVER_PORT=9100", "DATABASE_URL=postgres://env", "JWT_SECRET=${not-expanded}-0123456789abcdef0123456789"})
+	assert.Nil(t, err, "There should be no error")
+	assert.Equal(t, 9100, config.Server.Port)
+	assert.Equal(t, "postgres://env", config.Database.DSN())
+	assert.Equal(t, "${not-expanded}-0123456789abcdef0123456789", config.JWT.Secret)
+}
+
+func TestFromPropertiesShouldHonorLegacyEnv(t *testing.T) {
+	config, err := FromProperties(properties.NewProperties(), []string{"PORT=5000", "postgres_host=db", "postgres_dbname=news"})
+	assert.Nil(t, err, "There should be no error")
+	assert.Equal(t, 5000, config.Server.Port)
+	assert.Equal(t, "db", config.Database.Host)
+	assert.Equal(t, "news", config.Database.Name)
+}
+
+func TestFromPropertiesInvalidValuesShouldReportEveryProblem(t *testing.T) {
+	p := loadProperties(t, `
+server.port = eighty
+server.shutdown_timeout = soon
+database.max_open_conns = 5
+database.max_idle_conns = 10
+jwt.secret = short
+rate_limit.audit.burst = 0
+request_timeout.audit = 45s
+`)
+	_, err := FromProperties(p, nil)
+	validationErr, ok := err.(ValidationError)
+	assert.True(t, ok, "error should be a validation error")
+	assert.Equal(t, []string{
+		`server.port must be an integer, got "eighty"`,
+		`server.shutdown_timeout must be a duration such as 30s or 5m, got "soon"`,
+		"database.max_idle_conns (10) must not exceed database.max_open_conns (5)",
+		"jwt.secret must be at least 32 bytes long",
+		"rate_limit.audit.burst must be at least 1",
+		"request_timeout.audit (45s) must be shorter than server.write_timeout (30s) for the timeout to be answered",
+	}, validationErr.Problems)
+}
+
+func TestFromPropertiesShouldReadUnversionedSunset(t *testing.T) {
+	p := loadProperties(t, `
+unversioned.enabled = false
+unversioned.deprecated_at = 2027-01-15
+unversioned.sunset = 2027-07-15
+`)
+	config, err := FromProperties(p, nil)
+	assert.Nil(t, err, "There should be no error")
+	assert.Equal(t, UnversionedConfig{
+		Enabled:      false,
+		DeprecatedAt: time.Date(2027, time.January, 15, 0, 0, 0, 0, time.UTC),
+		Sunset:       time.Date(2027, time.July, 15, 0, 0, 0, 0, time.UTC),
+	}, config.Unversioned)
+}
+
+func TestFromPropertiesSunsetBeforeDeprecationShouldFail(t *testing.T) {
+	p := loadProperties(t, `
+unversioned.deprecated_at = 2027-07-15
+unversioned.sunset = 15/01/2027
+`)
+	_, err := FromProperties(p, nil)
+	validationErr, ok := err.(ValidationError)
+	assert.True(t, ok, "error should be a validation error")
+	assert.Equal(t, []string{
+		`unversioned.sunset must be a date such as 2027-05-01, got "15/01/2027"`,
+		"unversioned.sunset must come after unversioned.deprecated_at",
+	}, validationErr.Problems)
+}
+
+func TestFromPropertiesStorageBackendShouldBeKnown(t *testing.T) {
+	config, err := FromProperties(properties.NewProperties(), []string{"STORAGE_BACKEND=memory"})
+	assert.Nil(t, err, "There should be no error")
+	assert.Equal(t, StorageMemory, config.Storage.Backend)
+	_, err = FromProperties(loadProperties(t, "storage.backend = redis\n"), nil)
+	validationErr, ok := err.(ValidationError)
+	assert.True(t, ok, "error should be a validation error")
+	assert.Equal(t, []string{`storage.backend must be postgres or memory, got "redis"`}, validationErr.Problems)
+}
+
+func TestFromPropertiesNewsCacheShouldBeValid(t *testing.T) {
+	config, err := FromProperties(loadProperties(t, "news_cache.capacity = 0\nnews_cache.ttl = 0s\n"), nil)
+	assert.Nil(t, err, "a disabled cache needs no ttl")
+	assert.Equal(t, 0, config.NewsCache.Capacity)
+	_, err = FromProperties(loadProperties(t, "news_cache.capacity = -1\n"), []string{"NEWS_CACHE_TTL=0s"})
+	validationErr, ok := err.(ValidationError)
+	assert.True(t, ok, "error should be a validation error")
+	assert.Equal(t, []string{
+		"news_cache.capacity must not be negative, 0 disables the cache",
+		"news_cache.ttl must be positive",
+	}, validationErr.Problems)
+}
+
+func TestLoadExplicitMissingFileShouldFail(t *testing.T) {
+	os.Setenv("CONFIG_FILE", filepath.Join(t.TempDir(), "missing.properties"))
+	defer os.Unsetenv("CONFIG_FILE")
+	_, err := Load()
+	assert.NotNil(t, err, "There should be an error")
+}
+
+func TestLoadShouldReadConfigFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "news.properties")
+	assert.Nil(t, os.WriteFile(path, []byte("server.port = 7000\n"), 0600))
+	os.Setenv("CONFIG_FILE", path)
+	defer os.Unsetenv("CONFIG_FILE")
+	config, err := Load()
+	assert.Nil(t, err, "There should be no error")
+	assert.Equal(t, 7000, config.Server.Port)
+}
@@ -0,0 +1,116 @@
+package controllers
+
+import (
+	"github.com/gorilla/mux"
+	"news-topic-api/dto"
+	"news-topic-api/helpers"
+	"news-topic-api/models"
+	"news-topic-api/policies"
+	"news-topic-api/services"
+	"net/http"
+	"strconv"
+)
+
+//APIKeyController ...
+type APIKeyController struct {
+	apiKeyService services.IAPIKeyService
+	apiKeyPolicy policies.IAPIKeyPolicy
+}
+
+//InitAPIKeyController initializes api key controller given the api key service and the policy guarding it
+func InitAPIKeyController(apiKeyService services.IAPIKeyService, apiKeyPolicy policies.IAPIKeyPolicy) APIKeyController {
+	apiKeyController := new(APIKeyController)
+	apiKeyController.apiKeyService = apiKeyService
+	apiKeyController.apiKeyPolicy = apiKeyPolicy
+	return *apiKeyController
+}
+
+//Create controller that handles mint api key request, the plaintext key is only returned here
+func (a *APIKeyController) Create(res http.ResponseWriter, req *http.Request) {
+	if err := a.apiKeyPolicy.CanManage(getPrincipal(req)); err != nil {
+		responseError(res, req, err)
+		return
+	}
+	reqBody, err := a.decodeRequest(req)
+	if err != nil {
+		responseBadRequest(res, req, err)
+		return
+	}
+	resultData, err := a.apiKeyService.Mint(req.Context(), getPrincipal(req).Subject, reqBody)
+	if err != nil {
+		responseError(res, req, err)
+		return
+	}
+	helpers.Response(res, http.StatusCreated, dto.NewAPIKeySecretResponse(resultData))
+}
+
+//Rotate controller that handles rotate api key request
+func (a *APIKeyController) Rotate(res http.ResponseWriter, req *http.Request) {
+	if err := a.apiKeyPolicy.CanManage(getPrincipal(req)); err != nil {
+		responseError(res, req, err)
+		return
+	}
+	apiKeyID, err := a.parseID(req)
+	if err != nil {
+		responseBadRequest(res, req, err)
+		return
+	}
+	resultData, err := a.apiKeyService.Rotate(req.Context(), apiKeyID)
+	if err != nil {
+		responseError(res, req, err)
+		return
+	}
+	helpers.Response(res, http.StatusOK, dto.NewAPIKeySecretResponse(resultData))
+}
+
+//Delete controller that handles revoke api key request
+func (a *APIKeyController) Delete(res http.ResponseWriter, req *http.Request) {
+	if err := a.apiKeyPolicy.CanManage(getPrincipal(req)); err != nil {
+		responseError(res, req, err)
+		return
+	}
+	apiKeyID, err := a.parseID(req)
+	if err != nil {
+		responseBadRequest(res, req, err)
+		return
+	}
+	err = a.apiKeyService.Revoke(req.Context(), apiKeyID)
+	if err != nil {
+		responseError(res, req, err)
+		return
+	}
+	helpers.Response(res, http.StatusOK, nil)
+}
+
+//List controller that handles list api key request
+func (a *APIKeyController) List(res http.ResponseWriter, req *http.Request) {
+	if err := a.apiKeyPolicy.CanManage(getPrincipal(req)); err != nil {
+		responseError(res, req, err)
+		return
+	}
+	resultData, err := a.apiKeyService.List(req.Context())
+	if err != nil {
+		responseError(res, req, err)
+		return
+	}
+	helpers.Response(res, http.StatusOK, dto.NewAPIKeysListResponse(resultData))
+}
+
+func (a *APIKeyController) decodeRequest(req *http.Request) (models.APIKey, error) {
+	reqContent := dto.APIKeyRequest{}
+	if err := decodeStrict(req, &reqContent); err != nil {
+		return models.APIKey{}, err
+	}
+	if err := validateRequest(reqContent); err != nil {
+		return models.APIKey{}, err
+	}
+	return reqContent.ToModel(), nil
+}
+
+func (a *APIKeyController) parseID(req *http.Request) (uint, error) {
+	id, err := strconv.ParseUint(mux.Vars(req)["id"], 10, 64)
+	if err != nil {
+		return uint(0), invalidParameterError{name: "id"}
+	}
+	return uint(id), nil
+}
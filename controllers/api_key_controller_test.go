@@ -0,0 +1,127 @@
+package controllers
+
+import (
+	"encoding/json"
+	"news-topic-api/models"
+	"news-topic-api/policies"
+	"news-topic-api/services"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	mockServices "news-topic-api/mocks/services"
+)
+
+//getAPIKeyRouter is a function that prepares a router to test the http routing
+func getAPIKeyRouter(apiKeyController APIKeyController) *mux.Router {
+	router := mux.NewRouter()
+	router.HandleFunc("/apikey/", apiKeyController.Create).Methods("POST")
+	router.HandleFunc("/apikey/{id}/rotate", apiKeyController.Rotate).Methods("POST")
+	router.HandleFunc("/apikey/{id}", apiKeyController.Delete).Methods("DELETE")
+	router.HandleFunc("/apikey", apiKeyController.List).Methods("GET")
+	return router
+}
+
+func getMockRequestAPIKey() map[string]interface{} {
+	apiKeyData := make(map[string]interface{})
+	apiKeyData["name"] = "ingestion job"
+	apiKeyData["scopes"] = []string{"news:read", "news:write"}
+	return apiKeyData
+}
+
+func TestCreateAPIKeySuccessShouldReturnCreatedWithPlaintext(t *testing.T) {
+	mockedAPIKeyService := new(mockServices.IAPIKeyService)
+	mockedAPIKeyService.On("Mint", mock.Anything, "1", mock.Anything).Return(models.APIKeySecret{Key: "ntk_abc_secret"}, nil)
+	apiKeyController := InitAPIKeyController(mockedAPIKeyService, policies.InitAPIKeyPolicy())
+	request := createJSONRequestTag("POST", "/apikey/", getMockRequestAPIKey())
+	response := httptest.NewRecorder()
+	getAPIKeyRouter(apiKeyController).ServeHTTP(response, request)
+	assert.Equal(t, 201, response.Code, "response code should be 201")
+	assert.Contains(t, response.Body.String(), "ntk_abc_secret")
+}
+
+func TestCreateAPIKeyFailedShouldReturnBadRequest(t *testing.T) {
+	mockedAPIKeyService := new(mockServices.IAPIKeyService)
+	mockedAPIKeyService.On("Mint", mock.Anything, mock.Anything, mock.Anything).Return(models.APIKeySecret{}, services.InvalidInputError{Reason: "scope can not be granted"})
+	apiKeyController := InitAPIKeyController(mockedAPIKeyService, policies.InitAPIKeyPolicy())
+	request := createJSONRequestTag("POST", "/apikey/", getMockRequestAPIKey())
+	response := httptest.NewRecorder()
+	getAPIKeyRouter(apiKeyController).ServeHTTP(response, request)
+	assert.Equal(t, 400, response.Code, "response code should be 400")
+}
+
+func TestRotateAPIKeySuccessShouldReturnOk(t *testing.T) {
+	mockedAPIKeyService := new(mockServices.IAPIKeyService)
+	mockedAPIKeyService.On("Rotate", mock.Anything, uint(1)).Return(models.APIKeySecret{Key: "ntk_def_secret"}, nil)
+	apiKeyController := InitAPIKeyController(mockedAPIKeyService, policies.InitAPIKeyPolicy())
+	request, _ := http.NewRequest("POST", "/apikey/1/rotate", nil)
+	request = withRole(request, "1", models.RoleAdmin)
+	response := httptest.NewRecorder()
+	getAPIKeyRouter(apiKeyController).ServeHTTP(response, request)
+	assert.Equal(t, 200, response.Code, "response code should be 200")
+	assert.Contains(t, response.Body.String(), "ntk_def_secret")
+}
+
+func TestDeleteAPIKeySuccessShouldReturnOk(t *testing.T) {
+	mockedAPIKeyService := new(mockServices.IAPIKeyService)
+	mockedAPIKeyService.On("Revoke", mock.Anything, uint(1)).Return(nil)
+	apiKeyController := InitAPIKeyController(mockedAPIKeyService, policies.InitAPIKeyPolicy())
+	request, _ := http.NewRequest("DELETE", "/apikey/1", nil)
+	request = withRole(request, "1", models.RoleAdmin)
+	response := httptest.NewRecorder()
+	getAPIKeyRouter(apiKeyController).ServeHTTP(response, request)
+	assert.Equal(t, 200, response.Code, "response code should be 200")
+}
+
+func TestListAPIKeySuccessShouldNotExposeHash(t *testing.T) {
+	mockedAPIKeyService := new(mockServices.IAPIKeyService)
+	apiKeys := models.APIKeysList{Data: []models.APIKey{{Name: "ingestion job", KeyHash: "deadbeef"}}}
+	mockedAPIKeyService.On("List", mock.Anything).Return(apiKeys, nil)
+	apiKeyController := InitAPIKeyController(mockedAPIKeyService, policies.InitAPIKeyPolicy())
+	request, _ := http.NewRequest("GET", "/apikey", nil)
+	request = withRole(request, "1", models.RoleAdmin)
+	response := httptest.NewRecorder()
+	getAPIKeyRouter(apiKeyController).ServeHTTP(response, request)
+	assert.Equal(t, 200, response.Code, "response code should be 200")
+	assert.NotContains(t, response.Body.String(), "deadbeef")
+}
+
+func TestCreateAPIKeyWithOwnerShouldReturnBadRequest(t *testing.T) {
+	mockedAPIKeyService := new(mockServices.IAPIKeyService)
+	apiKeyController := InitAPIKeyController(mockedAPIKeyService, policies.InitAPIKeyPolicy())
+	requestData := getMockRequestAPIKey()
+	requestData["owner"] = "7"
+	request := createJSONRequestTag("POST", "/apikey/", requestData)
+	response := httptest.NewRecorder()
+	getAPIKeyRouter(apiKeyController).ServeHTTP(response, request)
+	assert.Equal(t, 400, response.Code, "response code should be 400")
+	mockedAPIKeyService.AssertNotCalled(t, "Mint", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestRotateAPIKeyShouldAnswerSnakeCaseFields(t *testing.T) {
+	apiKey := models.APIKey{Name: "ingestion job", Prefix: "ntk_def"}
+	apiKey.ID = 1
+	mockedAPIKeyService := new(mockServices.IAPIKeyService)
+	mockedAPIKeyService.On("Rotate", mock.Anything, uint(1)).Return(models.APIKeySecret{APIKey: apiKey, Key: "ntk_def_secret"}, nil)
+	apiKeyController := InitAPIKeyController(mockedAPIKeyService, policies.InitAPIKeyPolicy())
+	request, _ := http.NewRequest("POST", "/apikey/1/rotate", nil)
+	request = withRole(request, "1", models.RoleAdmin)
+	response := httptest.NewRecorder()
+	getAPIKeyRouter(apiKeyController).ServeHTTP(response, request)
+	var body struct {
+		Data struct {
+			APIKey map[string]interface{} `json:"api_key"`
+			Key    string                 `json:"key"`
+		} `json:"data"`
+	}
+	assert.Nil(t, json.Unmarshal(response.Body.Bytes(), &body))
+	assert.Equal(t, "ntk_def_secret", body.Data.Key)
+	assert.Equal(t, float64(1), body.Data.APIKey["id"])
+	assert.Equal(t, []interface{}{}, body.Data.APIKey["scopes"])
+	for _, gormField := range []string{"ID", "CreatedAt", "UpdatedAt", "DeletedAt"} {
+		assert.NotContains(t, body.Data.APIKey, gormField)
+	}
+}
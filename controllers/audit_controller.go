@@ -0,0 +1,50 @@
+package controllers
+
+import (
+	"news-topic-api/dto"
+	"news-topic-api/helpers"
+	"news-topic-api/models"
+	"news-topic-api/policies"
+	"news-topic-api/services"
+	"net/http"
+)
+
+//AuditController ...
+type AuditController struct {
+	auditService services.IAuditService
+	auditPolicy policies.IAuditPolicy
+}
+
+//InitAuditController initializes audit controller given the audit service and the policy guarding it
+func InitAuditController(auditService services.IAuditService, auditPolicy policies.IAuditPolicy) AuditController {
+	auditController := new(AuditController)
+	auditController.auditService = auditService
+	auditController.auditPolicy = auditPolicy
+	return *auditController
+}
+
+//List controller that handles list audit entries request
+func (a *AuditController) List(res http.ResponseWriter, req *http.Request) {
+	if err := a.auditPolicy.CanView(getPrincipal(req)); err != nil {
+		responseError(res, req, err)
+		return
+	}
+	searchParams := a.parseParams(req)
+	var resultData models.AuditLogsList
+	resultData, err := a.auditService.List(req.Context(), searchParams)
+	if err != nil {
+		responseError(res, req, err)
+		return
+	}
+	helpers.Response(res, http.StatusOK, dto.NewAuditLogsListResponse(resultData))
+}
+
+func (a *AuditController) parseParams(req *http.Request) map[string]string {
+	searchParams := make(map[string]string)
+	for _, key := range []string{"entity_type", "entity_id", "actor", "from", "to", "limit"} {
+		if value := req.URL.Query().Get(key); value != "" {
+			searchParams[key] = value
+		}
+	}
+	return searchParams
+}
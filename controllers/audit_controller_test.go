@@ -0,0 +1,79 @@
+package controllers
+
+import (
+	"encoding/json"
+	"news-topic-api/models"
+	"news-topic-api/policies"
+	"news-topic-api/services"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	mockServices "news-topic-api/mocks/services"
+)
+
+func getAuditRouter(auditController AuditController) *mux.Router {
+	router := mux.NewRouter()
+	router.HandleFunc("/audit", auditController.List).Methods("GET")
+	return router
+}
+
+func TestListAuditSuccessShouldReturnOk(t *testing.T) {
+	mockedAuditService := new(mockServices.IAuditService)
+	searchParams := map[string]string{"entity_type": "news", "actor": "42", "from": "2021-05-01T00:00:00Z"}
+	mockedAuditService.On("List", mock.Anything, searchParams).Return(models.AuditLogsList{Data: []models.AuditLog{{Action: "delete"}}}, nil)
+	auditController := InitAuditController(mockedAuditService, policies.InitAuditPolicy())
+	request := withRole(createURLParamRequestNews("GET", "/audit", searchParams), "1", models.RoleAdmin)
+	response := httptest.NewRecorder()
+	getAuditRouter(auditController).ServeHTTP(response, request)
+	assert.Equal(t, 200, response.Code, "response code should be 200")
+	mockedAuditService.AssertExpectations(t)
+}
+
+func TestListAuditShouldAnswerSnakeCaseFields(t *testing.T) {
+	mockedAuditService := new(mockServices.IAuditService)
+	auditLog := models.AuditLog{ID: 3, Action: "update", EntityType: "tag", EntityID: 1, After: models.JSON(`{"id":1,"name":"crypto"}`)}
+	mockedAuditService.On("List", mock.Anything, map[string]string{}).Return(models.AuditLogsList{Data: []models.AuditLog{auditLog}}, nil)
+	auditController := InitAuditController(mockedAuditService, policies.InitAuditPolicy())
+	request, _ := http.NewRequest("GET", "/audit", nil)
+	request = withRole(request, "1", models.RoleAdmin)
+	response := httptest.NewRecorder()
+	getAuditRouter(auditController).ServeHTTP(response, request)
+	var body struct {
+		Data struct {
+			Data []map[string]interface{} `json:"data"`
+		} `json:"data"`
+	}
+	assert.Nil(t, json.Unmarshal(response.Body.Bytes(), &body))
+	if assert.Len(t, body.Data.Data, 1) {
+		entry := body.Data.Data[0]
+		assert.Equal(t, float64(3), entry["id"])
+		assert.Equal(t, "tag", entry["entity_type"])
+		assert.Nil(t, entry["before"])
+		assert.Equal(t, map[string]interface{}{"id": float64(1), "name": "crypto"}, entry["after"])
+	}
+}
+
+func TestListAuditInvalidFilterShouldReturnBadRequest(t *testing.T) {
+	mockedAuditService := new(mockServices.IAuditService)
+	searchParams := map[string]string{"from": "yesterday"}
+	mockedAuditService.On("List", mock.Anything, searchParams).Return(models.AuditLogsList{}, services.InvalidInputError{Reason: "invalid format for from"})
+	auditController := InitAuditController(mockedAuditService, policies.InitAuditPolicy())
+	request := withRole(createURLParamRequestNews("GET", "/audit", searchParams), "1", models.RoleAdmin)
+	response := httptest.NewRecorder()
+	getAuditRouter(auditController).ServeHTTP(response, request)
+	assert.Equal(t, 400, response.Code, "response code should be 400")
+}
+
+func TestListAuditAsEditorShouldReturnForbidden(t *testing.T) {
+	mockedAuditService := new(mockServices.IAuditService)
+	auditController := InitAuditController(mockedAuditService, policies.InitAuditPolicy())
+	request, _ := http.NewRequest("GET", "/audit", nil)
+	request = withRole(request, "42", models.RoleEditor)
+	response := httptest.NewRecorder()
+	getAuditRouter(auditController).ServeHTTP(response, request)
+	assert.Equal(t, 403, response.Code, "response code should be 403")
+}
@@ -0,0 +1,132 @@
+package controllers
+
+import (
+	"github.com/gorilla/mux"
+	"news-topic-api/dto"
+	"news-topic-api/helpers"
+	"news-topic-api/models"
+	"news-topic-api/policies"
+	"news-topic-api/services"
+	"net/http"
+	"strconv"
+)
+
+//AuthorController ...
+type AuthorController struct {
+	authorService services.IAuthorService
+	authorPolicy policies.IAuthorPolicy
+}
+
+//InitAuthorController initializes author controller given the author service and the policy guarding it
+func InitAuthorController(authorService services.IAuthorService, authorPolicy policies.IAuthorPolicy) AuthorController {
+	authorController := new(AuthorController)
+	authorController.authorService = authorService
+	authorController.authorPolicy = authorPolicy
+	return *authorController
+}
+
+//Create controller that handles create author request
+func (a *AuthorController) Create(res http.ResponseWriter, req *http.Request) {
+	if err := a.authorPolicy.CanManage(getPrincipal(req)); err != nil {
+		responseError(res, req, err)
+		return
+	}
+	reqBody, err := a.decodeRequest(req)
+	if err != nil {
+		responseBadRequest(res, req, err)
+		return
+	}
+	resultData, err := a.authorService.Create(req.Context(), reqBody)
+	if err != nil {
+		responseError(res, req, err)
+		return
+	}
+	helpers.Response(res, http.StatusCreated, dto.NewAuthorResponse(resultData))
+}
+
+//Update controller that handles update author request
+func (a *AuthorController) Update(res http.ResponseWriter, req *http.Request) {
+	if err := a.authorPolicy.CanManage(getPrincipal(req)); err != nil {
+		responseError(res, req, err)
+		return
+	}
+	reqBody, err := a.decodeRequest(req)
+	if err != nil {
+		responseBadRequest(res, req, err)
+		return
+	}
+	authorID, err := a.parseID(req)
+	if err != nil {
+		responseBadRequest(res, req, err)
+		return
+	}
+	resultData, err := a.authorService.Update(req.Context(), authorID, reqBody)
+	if err != nil {
+		responseError(res, req, err)
+		return
+	}
+	helpers.Response(res, http.StatusOK, dto.NewAuthorResponse(resultData))
+}
+
+//Delete controller that handles delete author request
+func (a *AuthorController) Delete(res http.ResponseWriter, req *http.Request) {
+	if err := a.authorPolicy.CanManage(getPrincipal(req)); err != nil {
+		responseError(res, req, err)
+		return
+	}
+	authorID, err := a.parseID(req)
+	if err != nil {
+		responseBadRequest(res, req, err)
+		return
+	}
+	err = a.authorService.Delete(req.Context(), authorID)
+	if err != nil {
+		responseError(res, req, err)
+		return
+	}
+	helpers.Response(res, http.StatusOK, nil)
+}
+
+//List controller that handles list author request
+func (a *AuthorController) List(res http.ResponseWriter, req *http.Request) {
+	resultData, err := a.authorService.List(req.Context())
+	if err != nil {
+		responseError(res, req, err)
+		return
+	}
+	helpers.Response(res, http.StatusOK, dto.NewAuthorsListResponse(resultData))
+}
+
+//GetDetail controller that handles get author by id request
+func (a *AuthorController) GetDetail(res http.ResponseWriter, req *http.Request) {
+	authorID, err := a.parseID(req)
+	if err != nil {
+		responseBadRequest(res, req, err)
+		return
+	}
+	resultData, err := a.authorService.GetDetail(req.Context(), authorID)
+	if err != nil {
+		responseError(res, req, err)
+		return
+	}
+	helpers.Response(res, http.StatusOK, dto.NewAuthorResponse(resultData))
+}
+
+func (a *AuthorController) decodeRequest(req *http.Request) (models.Author, error) {
+	reqContent := dto.AuthorRequest{}
+	if err := decodeStrict(req, &reqContent); err != nil {
+		return models.Author{}, err
+	}
+	if err := validateRequest(reqContent); err != nil {
+		return models.Author{}, err
+	}
+	return reqContent.ToModel(), nil
+}
+
+func (a *AuthorController) parseID(req *http.Request) (uint, error) {
+	id, err := strconv.ParseUint(mux.Vars(req)["id"], 10, 64)
+	if err != nil {
+		return uint(0), invalidParameterError{name: "id"}
+	}
+	return uint(id), nil
+}
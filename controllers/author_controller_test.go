@@ -0,0 +1,226 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"news-topic-api/models"
+	"news-topic-api/policies"
+	"news-topic-api/services"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	mockServices "news-topic-api/mocks/services"
+)
+
+//getAuthorRouter is a function that prepares a router to test the http routing
+func getAuthorRouter(authorController AuthorController, requestType string) *mux.Router {
+	router := mux.NewRouter()
+	var pathSuffix string
+	var method string
+	controllerFunc := authorController.Create
+	if requestType == "Create" {
+		pathSuffix = "/"
+		method = "POST"
+		controllerFunc = authorController.Create
+	} else if requestType == "Update" {
+		pathSuffix = "/{id}"
+		method = "PUT"
+		controllerFunc = authorController.Update
+	} else if requestType == "Delete" {
+		pathSuffix = "/{id}"
+		method = "DELETE"
+		controllerFunc = authorController.Delete
+	} else if requestType == "List" {
+		pathSuffix = ""
+		method = "GET"
+		controllerFunc = authorController.List
+	} else {
+		pathSuffix = "/{id}"
+		method = "GET"
+		controllerFunc = authorController.GetDetail
+	}
+	router.HandleFunc(fmt.Sprintf("/author%s", pathSuffix), controllerFunc).Methods(method)
+	return router
+}
+
+func createJSONRequestAuthor(method string, url string, data map[string]interface{}) *http.Request {
+	jsonData, _ := json.Marshal(data)
+	request, _ := http.NewRequest(method, url, bytes.NewReader(jsonData))
+	request.Header.Add("Content-Type", "application/json")
+	return withRole(request, "42", models.RoleEditor)
+}
+
+func getMockRequestAuthor() map[string]interface{} {
+	authorData := make(map[string]interface{})
+	authorData["name"] = "Budi Santoso"
+	authorData["bio"] = "Jurnalis ekonomi dan teknologi"
+	authorData["avatar"] = "google.com/avatar.png"
+	return authorData
+}
+
+func getMockAuthor() models.Author {
+	entity := models.Author{
+		Name: "Budi Santoso",
+		Bio: "Jurnalis ekonomi dan teknologi",
+		Avatar: "google.com/avatar.png",
+	}
+	return entity
+}
+
+func TestCreateAuthorFailedShouldReturnBadRequest(t *testing.T) {
+	mockedRequestData := getMockRequestAuthor()
+	mockedAuthorService := new(mockServices.IAuthorService)
+	mockedAuthorService.On("Create", mock.Anything, mock.Anything).Return(models.Author{}, services.InvalidInputError{Reason: "service can't create author"})
+	authorController := InitAuthorController(mockedAuthorService, policies.InitAuthorPolicy())
+	request := createJSONRequestAuthor("POST", "/author/", mockedRequestData)
+	response := httptest.NewRecorder()
+	router := getAuthorRouter(authorController, "Create")
+	router.ServeHTTP(response, request)
+	assert.Equal(t, 400, response.Code, "response code should be 400")
+}
+
+func TestCreateAuthorInvalidRequestShouldReturnBadRequest(t *testing.T) {
+	mockedRequestData := getMockRequestAuthor()
+	mockedRequestData["name"] = 12345
+	mockedAuthorService := new(mockServices.IAuthorService)
+	authorController := InitAuthorController(mockedAuthorService, policies.InitAuthorPolicy())
+	request := createJSONRequestAuthor("POST", "/author/", mockedRequestData)
+	response := httptest.NewRecorder()
+	router := getAuthorRouter(authorController, "Create")
+	router.ServeHTTP(response, request)
+	assert.Equal(t, 400, response.Code, "response code should be 400")
+}
+
+func TestCreateAuthorSuccessShouldReturnCreated(t *testing.T) {
+	mockedRequestData := getMockRequestAuthor()
+	mockedAuthorService := new(mockServices.IAuthorService)
+	mockedAuthorService.On("Create", mock.Anything, mock.Anything).Return(getMockAuthor(), nil)
+	authorController := InitAuthorController(mockedAuthorService, policies.InitAuthorPolicy())
+	request := createJSONRequestAuthor("POST", "/author/", mockedRequestData)
+	response := httptest.NewRecorder()
+	router := getAuthorRouter(authorController, "Create")
+	router.ServeHTTP(response, request)
+	assert.Equal(t, 201, response.Code, "response code should be 201")
+}
+
+func TestUpdateAuthorFailedShouldReturnBadRequest(t *testing.T) {
+	mockedRequestData := getMockRequestAuthor()
+	mockedAuthorService := new(mockServices.IAuthorService)
+	mockedAuthorService.On("Update", mock.Anything, uint(1), mock.Anything).Return(models.Author{}, services.InvalidInputError{Reason: "Author failed to update"})
+	authorController := InitAuthorController(mockedAuthorService, policies.InitAuthorPolicy())
+	request := createJSONRequestAuthor("PUT", "/author/1", mockedRequestData)
+	response := httptest.NewRecorder()
+	router := getAuthorRouter(authorController, "Update")
+	router.ServeHTTP(response, request)
+	assert.Equal(t, 400, response.Code, "response code should be 400")
+}
+
+func TestUpdateAuthorSuccessShouldReturnOk(t *testing.T) {
+	mockedRequestData := getMockRequestAuthor()
+	mockedAuthorService := new(mockServices.IAuthorService)
+	mockedAuthorService.On("Update", mock.Anything, uint(1), mock.Anything).Return(getMockAuthor(), nil)
+	authorController := InitAuthorController(mockedAuthorService, policies.InitAuthorPolicy())
+	request := createJSONRequestAuthor("PUT", "/author/1", mockedRequestData)
+	response := httptest.NewRecorder()
+	router := getAuthorRouter(authorController, "Update")
+	router.ServeHTTP(response, request)
+	assert.Equal(t, 200, response.Code, "response code should be 200")
+}
+
+func TestDeleteAuthorSuccessShouldReturnOk(t *testing.T) {
+	mockedAuthorService := new(mockServices.IAuthorService)
+	mockedAuthorService.On("Delete", mock.Anything, uint(1)).Return(nil)
+	authorController := InitAuthorController(mockedAuthorService, policies.InitAuthorPolicy())
+	request, _ := http.NewRequest("DELETE", "/author/1", nil)
+	request = withRole(request, "42", models.RoleEditor)
+	response := httptest.NewRecorder()
+	router := getAuthorRouter(authorController, "Delete")
+	router.ServeHTTP(response, request)
+	assert.Equal(t, 200, response.Code, "response code should be 200")
+}
+
+func TestDeleteAuthorInvalidIDOnURLShouldReturnBadRequest(t *testing.T) {
+	mockedAuthorService := new(mockServices.IAuthorService)
+	authorController := InitAuthorController(mockedAuthorService, policies.InitAuthorPolicy())
+	request, _ := http.NewRequest("DELETE", "/author/asdasdasd", nil)
+	request = withRole(request, "42", models.RoleEditor)
+	response := httptest.NewRecorder()
+	router := getAuthorRouter(authorController, "Delete")
+	router.ServeHTTP(response, request)
+	assert.Equal(t, 400, response.Code, "response code should be 400")
+}
+
+func TestListAuthorSuccessShouldReturnOk(t *testing.T) {
+	mockedAuthorService := new(mockServices.IAuthorService)
+	mockedAuthorService.On("List", mock.Anything).Return(models.AuthorsList{Data: []models.Author{getMockAuthor()}}, nil)
+	authorController := InitAuthorController(mockedAuthorService, policies.InitAuthorPolicy())
+	request, _ := http.NewRequest("GET", "/author", nil)
+	request = withRole(request, "42", models.RoleEditor)
+	response := httptest.NewRecorder()
+	router := getAuthorRouter(authorController, "List")
+	router.ServeHTTP(response, request)
+	assert.Equal(t, 200, response.Code, "response code should be 200")
+}
+
+func TestGetDetailAuthorSuccessShouldReturnOk(t *testing.T) {
+	mockedAuthorService := new(mockServices.IAuthorService)
+	mockedAuthorService.On("GetDetail", mock.Anything, uint(1)).Return(getMockAuthor(), nil)
+	authorController := InitAuthorController(mockedAuthorService, policies.InitAuthorPolicy())
+	request, _ := http.NewRequest("GET", "/author/1", nil)
+	request = withRole(request, "42", models.RoleEditor)
+	response := httptest.NewRecorder()
+	router := getAuthorRouter(authorController, "Detail")
+	router.ServeHTTP(response, request)
+	assert.Equal(t, 200, response.Code, "response code should be 200")
+}
+
+func TestGetDetailAuthorNotFoundShouldReturnNotFound(t *testing.T) {
+	mockedAuthorService := new(mockServices.IAuthorService)
+	mockedAuthorService.On("GetDetail", mock.Anything, uint(1)).Return(models.Author{}, services.NotFoundError{Entity: "author", ID: 1})
+	authorController := InitAuthorController(mockedAuthorService, policies.InitAuthorPolicy())
+	request, _ := http.NewRequest("GET", "/author/1", nil)
+	request = withRole(request, "42", models.RoleEditor)
+	response := httptest.NewRecorder()
+	router := getAuthorRouter(authorController, "Detail")
+	router.ServeHTTP(response, request)
+	assert.Equal(t, 404, response.Code, "response code should be 404")
+}
+
+func TestCreateAuthorWithStoredFieldsShouldReturnBadRequest(t *testing.T) {
+	mockedRequestData := getMockRequestAuthor()
+	mockedRequestData["ID"] = 7
+	mockedAuthorService := new(mockServices.IAuthorService)
+	authorController := InitAuthorController(mockedAuthorService, policies.InitAuthorPolicy())
+	request := createJSONRequestAuthor("POST", "/author/", mockedRequestData)
+	response := httptest.NewRecorder()
+	router := getAuthorRouter(authorController, "Create")
+	router.ServeHTTP(response, request)
+	assert.Equal(t, 400, response.Code, "response code should be 400")
+	mockedAuthorService.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestGetDetailAuthorShouldAnswerSnakeCaseFields(t *testing.T) {
+	author := getMockAuthor()
+	author.ID = 1
+	mockedAuthorService := new(mockServices.IAuthorService)
+	mockedAuthorService.On("GetDetail", mock.Anything, uint(1)).Return(author, nil)
+	authorController := InitAuthorController(mockedAuthorService, policies.InitAuthorPolicy())
+	request, _ := http.NewRequest("GET", "/author/1", nil)
+	response := httptest.NewRecorder()
+	router := getAuthorRouter(authorController, "Detail")
+	router.ServeHTTP(response, request)
+	var body struct {
+		Data map[string]interface{} `json:"data"`
+	}
+	assert.Nil(t, json.Unmarshal(response.Body.Bytes(), &body))
+	assert.Equal(t, float64(1), body.Data["id"])
+	assert.Contains(t, body.Data, "created_at")
+	for _, gormField := range []string{"ID", "CreatedAt", "UpdatedAt", "DeletedAt"} {
+		assert.NotContains(t, body.Data, gormField)
+	}
+}
@@ -0,0 +1,129 @@
+package controllers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"news-topic-api/helpers"
+	"news-topic-api/models"
+	"news-topic-api/policies"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	mockServices "news-topic-api/mocks/services"
+)
+
+//withRole attaches an interactive principal holding the given roles to the request
+func withRole(request *http.Request, subject string, roles ...string) *http.Request {
+	principal := models.Principal{Subject: subject, Roles: roles, AuthMethod: "jwt"}
+	return request.WithContext(helpers.WithPrincipal(request.Context(), principal))
+}
+
+func TestCreatePublishedNewsAsWriterShouldReturnForbidden(t *testing.T) {
+	mockedRequestData := getMockReqNews()
+	mockedRequestData["status"] = models.StatusPublished
+	mockedNewsService := new(mockServices.INewsService)
+	newsController := InitNewsController(mockedNewsService, policies.InitNewsPolicy(mockedNewsService))
+	request := withRole(createJSONRequestNews("POST", "/news/", mockedRequestData), "7", models.RoleWriter)
+	response := httptest.NewRecorder()
+	router := getNewsRouter(newsController, "Create")
+	router.ServeHTTP(response, request)
+	assert.Equal(t, 403, response.Code, "response code should be 403")
+	assert.Contains(t, response.Body.String(), "only editors")
+	mockedNewsService.AssertNotCalled(t, "Create", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestCreateInvalidNewsAsReaderShouldReturnForbidden(t *testing.T) {
+	mockedNewsService := new(mockServices.INewsService)
+	newsController := InitNewsController(mockedNewsService, policies.InitNewsPolicy(mockedNewsService))
+	request := withRole(createJSONRequestNews("POST", "/news/", map[string]interface{}{"title": 123}), "7", models.RoleReader)
+	response := httptest.NewRecorder()
+	getNewsRouter(newsController, "Create").ServeHTTP(response, request)
+	assert.Equal(t, 403, response.Code, "the policy should be checked before the body")
+	assert.NotContains(t, response.Body.String(), "errors", "validation details should not be answered")
+}
+
+func TestUpdateInvalidNewsAsReaderShouldReturnForbidden(t *testing.T) {
+	mockedNewsService := new(mockServices.INewsService)
+	newsController := InitNewsController(mockedNewsService, policies.InitNewsPolicy(mockedNewsService))
+	request := withRole(createJSONRequestNews("PUT", "/news/1", map[string]interface{}{"owner": "7"}), "7", models.RoleReader)
+	response := httptest.NewRecorder()
+	getNewsRouter(newsController, "Update").ServeHTTP(response, request)
+	assert.Equal(t, 403, response.Code, "the policy should be checked before the body")
+	mockedNewsService.AssertNotCalled(t, "GetDetail", mock.Anything, mock.Anything)
+}
+
+func TestCreateDraftAsWriterShouldRecordCreator(t *testing.T) {
+	mockedNewsService := new(mockServices.INewsService)
+	mockedNewsService.On("Create", mock.Anything, mock.MatchedBy(func(news models.News) bool {
+		return news.CreatedBy == "7"
+	}), mock.Anything).Return(getMockNews(), nil)
+	newsController := InitNewsController(mockedNewsService, policies.InitNewsPolicy(mockedNewsService))
+	request := withRole(createJSONRequestNews("POST", "/news/", getMockReqNews()), "7", models.RoleWriter)
+	response := httptest.NewRecorder()
+	router := getNewsRouter(newsController, "Create")
+	router.ServeHTTP(response, request)
+	assert.Equal(t, 201, response.Code, "response code should be 201")
+	mockedNewsService.AssertExpectations(t)
+}
+
+func TestUpdateOtherWritersDraftShouldReturnForbidden(t *testing.T) {
+	existing := getMockNews()
+	existing.CreatedBy = "8"
+	mockedNewsService := new(mockServices.INewsService)
+	mockedNewsService.On("GetDetail", mock.Anything, uint(1)).Return(existing, nil)
+	newsController := InitNewsController(mockedNewsService, policies.InitNewsPolicy(mockedNewsService))
+	request := withRole(createJSONRequestNews("PUT", "/news/1", getMockReqNews()), "7", models.RoleWriter)
+	response := httptest.NewRecorder()
+	router := getNewsRouter(newsController, "Update")
+	router.ServeHTTP(response, request)
+	assert.Equal(t, 403, response.Code, "response code should be 403")
+	mockedNewsService.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestUpdateOwnDraftAsWriterShouldReturnOk(t *testing.T) {
+	existing := getMockNews()
+	existing.CreatedBy = "7"
+	mockedNewsService := new(mockServices.INewsService)
+	mockedNewsService.On("GetDetail", mock.Anything, uint(1)).Return(existing, nil)
+	mockedNewsService.On("Update", mock.Anything, uint(1), mock.Anything, mock.Anything).Return(existing, nil)
+	newsController := InitNewsController(mockedNewsService, policies.InitNewsPolicy(mockedNewsService))
+	request := withRole(createJSONRequestNews("PUT", "/news/1", getMockReqNews()), "7", models.RoleWriter)
+	response := httptest.NewRecorder()
+	router := getNewsRouter(newsController, "Update")
+	router.ServeHTTP(response, request)
+	assert.Equal(t, 200, response.Code, "response code should be 200")
+}
+
+func TestDeleteNewsAsWriterShouldReturnForbidden(t *testing.T) {
+	mockedNewsService := new(mockServices.INewsService)
+	newsController := InitNewsController(mockedNewsService, policies.InitNewsPolicy(mockedNewsService))
+	request := withRole(createURLStandardRequestNews("DELETE", "/news/1"), "7", models.RoleWriter)
+	response := httptest.NewRecorder()
+	router := getNewsRouter(newsController, "Delete")
+	router.ServeHTTP(response, request)
+	assert.Equal(t, 403, response.Code, "response code should be 403")
+	mockedNewsService.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestRestoreNewsAsWriterShouldReturnForbidden(t *testing.T) {
+	mockedNewsService := new(mockServices.INewsService)
+	newsController := InitNewsController(mockedNewsService, policies.InitNewsPolicy(mockedNewsService))
+	request := withRole(createURLStandardRequestNews("POST", "/news/1/restore"), "7", models.RoleWriter)
+	response := httptest.NewRecorder()
+	router := getNewsRouter(newsController, "Restore")
+	router.ServeHTTP(response, request)
+	assert.Equal(t, 403, response.Code, "response code should be 403")
+	mockedNewsService.AssertNotCalled(t, "Restore", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestCreateTagAsEditorShouldReturnForbidden(t *testing.T) {
+	mockedTagService := new(mockServices.ITagService)
+	tagController := InitTagController(mockedTagService, policies.InitTagPolicy())
+	request := withRole(createJSONRequestTag("POST", "/tag/", getMockRequestTag()), "42", models.RoleEditor)
+	response := httptest.NewRecorder()
+	router := getTagRouter(tagController, "Create")
+	router.ServeHTTP(response, request)
+	assert.Equal(t, 403, response.Code, "response code should be 403")
+	assert.Contains(t, response.Body.String(), "managing tags requires the admin role")
+}
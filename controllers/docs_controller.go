@@ -0,0 +1,31 @@
+package controllers
+
+import (
+	"net/http"
+)
+
+//DocsController ...
+type DocsController struct {
+	spec []byte
+	page []byte
+}
+
+//InitDocsController initializes docs controller given the OpenAPI document and the page rendering it
+func InitDocsController(spec []byte, page []byte) DocsController {
+	docsController := new(DocsController)
+	docsController.spec = spec
+	docsController.page = page
+	return *docsController
+}
+
+//Spec controller that serves the OpenAPI document
+func (d *DocsController) Spec(res http.ResponseWriter, req *http.Request) {
+	res.Header().Set("Content-Type", "application/json")
+	res.Write(d.spec)
+}
+
+//Page controller that serves the interactive documentation
+func (d *DocsController) Page(res http.ResponseWriter, req *http.Request) {
+	res.Header().Set("Content-Type", "text/html; charset=utf-8")
+	res.Write(d.page)
+}
@@ -0,0 +1,29 @@
+package controllers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSpecShouldServeDocument(t *testing.T) {
+	docsController := InitDocsController([]byte(`{"openapi":"3.0.3"}`), []byte("<html></html>"))
+	request, _ := http.NewRequest("GET", "/openapi.json", nil)
+	response := httptest.NewRecorder()
+	docsController.Spec(response, request)
+	assert.Equal(t, 200, response.Code, "response code should be 200")
+	assert.Equal(t, "application/json", response.Header().Get("Content-Type"))
+	assert.Equal(t, `{"openapi":"3.0.3"}`, response.Body.String())
+}
+
+func TestPageShouldServeHTML(t *testing.T) {
+	docsController := InitDocsController([]byte(`{}`), []byte("<html></html>"))
+	request, _ := http.NewRequest("GET", "/docs", nil)
+	response := httptest.NewRecorder()
+	docsController.Page(response, request)
+	assert.Equal(t, 200, response.Code, "response code should be 200")
+	assert.Equal(t, "text/html; charset=utf-8", response.Header().Get("Content-Type"))
+	assert.Equal(t, "<html></html>", response.Body.String())
+}
@@ -0,0 +1,112 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"news-topic-api/helpers"
+	"news-topic-api/policies"
+	"news-topic-api/services"
+)
+
+//errInternal is shown instead of errors that have no meaning for the caller, such as raw database errors
+var errInternal = fmt.Errorf("internal server error")
+
+//errTimedOut is shown instead of the error of an interrupted query, which may quote it
+var errTimedOut = fmt.Errorf("the request timed out")
+
+//invalidParameterError is returned when a path parameter can not be parsed
+type invalidParameterError struct {
+	name string
+}
+
+func (i invalidParameterError) Error() string {
+	return fmt.Sprintf("invalid format for %s", i.name)
+}
+
+//errorStatus maps the domain errors to the status answering them, unknown errors are internal
+func errorStatus(err error) int {
+	var (
+		forbiddenError    policies.ForbiddenError
+		notFoundError     services.NotFoundError
+		conflictError     services.ConflictError
+		invalidInputError services.InvalidInputError
+		unavailableError  services.UnavailableError
+	)
+	switch {
+	case errors.As(err, &forbiddenError):
+		return http.StatusForbidden
+	case errors.As(err, &notFoundError):
+		return http.StatusNotFound
+	case errors.As(err, &conflictError):
+		return http.StatusConflict
+	case errors.As(err, &invalidInputError):
+		return http.StatusBadRequest
+	case errors.As(err, &unavailableError):
+		return http.StatusServiceUnavailable
+	}
+	return http.StatusInternalServerError
+}
+
+//responseError answers an error returned by a policy or a service with its status,
+//failures that are not the caller's are logged and answered without their details
+func responseError(res http.ResponseWriter, req *http.Request, err error) {
+	if status, ok := helpers.ContextErrorStatus(req.Context(), err); ok {
+		helpers.ResponseError(res, req, status, errTimedOut)
+		return
+	}
+	status := errorStatus(err)
+	switch status {
+	case http.StatusInternalServerError:
+		helpers.GetLogger(req.Context()).Error("request failed", err, nil)
+		helpers.ResponseError(res, req, status, errInternal)
+		return
+	case http.StatusServiceUnavailable:
+		helpers.GetLogger(req.Context()).Error("dependency unavailable", err, nil)
+	}
+	problem := helpers.NewProblem(req, status, err.Error())
+	var invalidInputError services.InvalidInputError
+	if errors.As(err, &invalidInputError) {
+		for _, field := range invalidInputError.Fields {
+			problem.Errors = append(problem.Errors, helpers.FieldProblem{Field: field.Field, Detail: field.Reason})
+		}
+	}
+	helpers.ResponseProblem(res, problem)
+}
+
+//responseBadRequest answers a request whose body or path parameters could not be parsed,
+//pointing at the field at fault when the error names it. Every such request is logged the same way
+func responseBadRequest(res http.ResponseWriter, req *http.Request, err error) {
+	helpers.GetLogger(req.Context()).Warn("invalid request", helpers.LogFields{"error": err.Error()})
+	var (
+		typeError      *json.UnmarshalTypeError
+		syntaxError    *json.SyntaxError
+		parameterError invalidParameterError
+		invalidBody    validationError
+		unknownField   unknownFieldError
+	)
+	switch {
+	case errors.As(err, &typeError) && typeError.Field != "":
+		detail := fmt.Sprintf("%s can not be a json %s", typeError.Field, typeError.Value)
+		problem := helpers.NewProblem(req, http.StatusBadRequest, detail)
+		problem.Errors = []helpers.FieldProblem{{Field: typeError.Field, Detail: detail}}
+		helpers.ResponseProblem(res, problem)
+	case errors.As(err, &syntaxError):
+		helpers.ResponseError(res, req, http.StatusBadRequest, fmt.Errorf("request body is not valid json: %s", syntaxError.Error()))
+	case errors.As(err, &parameterError):
+		problem := helpers.NewProblem(req, http.StatusBadRequest, parameterError.Error())
+		problem.Errors = []helpers.FieldProblem{{Field: parameterError.name, Detail: parameterError.Error()}}
+		helpers.ResponseProblem(res, problem)
+	case errors.As(err, &unknownField):
+		problem := helpers.NewProblem(req, http.StatusBadRequest, unknownField.Error())
+		problem.Errors = []helpers.FieldProblem{{Field: unknownField.field, Detail: "is not accepted"}}
+		helpers.ResponseProblem(res, problem)
+	case errors.As(err, &invalidBody):
+		problem := helpers.NewProblem(req, http.StatusBadRequest, invalidBody.Error())
+		problem.Errors = invalidBody.fields
+		helpers.ResponseProblem(res, problem)
+	default:
+		helpers.ResponseError(res, req, http.StatusBadRequest, err)
+	}
+}
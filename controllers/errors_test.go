@@ -0,0 +1,92 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"news-topic-api/helpers"
+	"news-topic-api/models"
+	"news-topic-api/policies"
+	"news-topic-api/services"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	mockServices "news-topic-api/mocks/services"
+)
+
+func decodeProblem(t *testing.T, response *httptest.ResponseRecorder) helpers.Problem {
+	var problem helpers.Problem
+	assert.Equal(t, helpers.ProblemContentType, response.Header().Get("Content-Type"))
+	assert.Nil(t, json.NewDecoder(response.Body).Decode(&problem), "response should be valid json")
+	return problem
+}
+
+func TestGetDetailNewsNotFoundShouldReturnProblem(t *testing.T) {
+	mockedNewsService := new(mockServices.INewsService)
+	mockedNewsService.On("GetDetail", mock.Anything, uint(1)).Return(models.News{}, services.NotFoundError{Entity: "news", ID: 1})
+	newsController := InitNewsController(mockedNewsService, policies.InitNewsPolicy(mockedNewsService))
+	request := createURLStandardRequestNews("GET", "/news/1")
+	request = request.WithContext(helpers.WithRequestID(request.Context(), "abc-123"))
+	response := httptest.NewRecorder()
+	getNewsRouter(newsController, "Detail").ServeHTTP(response, request)
+	assert.Equal(t, helpers.Problem{
+		Type:      helpers.ProblemTypeDefault,
+		Title:     "Not Found",
+		Status:    404,
+		Detail:    "news 1 not found",
+		Instance:  "/news/1",
+		RequestID: "abc-123",
+	}, decodeProblem(t, response))
+}
+
+func TestCreateNewsMissingTagShouldPointAtField(t *testing.T) {
+	mockedNewsService := new(mockServices.INewsService)
+	mockedNewsService.On("Create", mock.Anything, mock.Anything, mock.Anything).Return(models.News{}, services.InvalidInputError{
+		Reason: "tag 9 does not exist",
+		Fields: []services.FieldError{{Field: "tags", Reason: "tag 9 does not exist"}},
+	})
+	newsController := InitNewsController(mockedNewsService, policies.InitNewsPolicy(mockedNewsService))
+	request := createJSONRequestNews("POST", "/news/", getMockReqNews())
+	response := httptest.NewRecorder()
+	getNewsRouter(newsController, "Create").ServeHTTP(response, request)
+	assert.Equal(t, 400, response.Code, "response code should be 400")
+	problem := decodeProblem(t, response)
+	assert.Equal(t, []helpers.FieldProblem{{Field: "tags", Detail: "tag 9 does not exist"}}, problem.Errors)
+}
+
+func TestCreateNewsWrongTypeShouldPointAtField(t *testing.T) {
+	mockedRequestData := getMockReqNews()
+	mockedRequestData["title"] = 12345
+	mockedNewsService := new(mockServices.INewsService)
+	newsController := InitNewsController(mockedNewsService, policies.InitNewsPolicy(mockedNewsService))
+	request := createJSONRequestNews("POST", "/news/", mockedRequestData)
+	response := httptest.NewRecorder()
+	getNewsRouter(newsController, "Create").ServeHTTP(response, request)
+	problem := decodeProblem(t, response)
+	assert.Equal(t, 400, problem.Status)
+	assert.Len(t, problem.Errors, 1)
+	assert.Equal(t, "title", problem.Errors[0].Field)
+}
+
+func TestDeleteTagInvalidIDShouldPointAtParameter(t *testing.T) {
+	mockedTagService := new(mockServices.ITagService)
+	tagController := InitTagController(mockedTagService, policies.InitTagPolicy())
+	request := createURLStandardRequestTag("DELETE", "/tag/abc")
+	response := httptest.NewRecorder()
+	getTagRouter(tagController, "Delete").ServeHTTP(response, request)
+	problem := decodeProblem(t, response)
+	assert.Equal(t, []helpers.FieldProblem{{Field: "id", Detail: "invalid format for id"}}, problem.Errors)
+}
+
+func TestListTagFailedShouldHideDetail(t *testing.T) {
+	mockedTagService := new(mockServices.ITagService)
+	mockedTagService.On("List", mock.Anything).Return(models.TagsList{}, assert.AnError)
+	tagController := InitTagController(mockedTagService, policies.InitTagPolicy())
+	request := createURLStandardRequestTag("GET", "/tag")
+	response := httptest.NewRecorder()
+	getTagRouter(tagController, "List").ServeHTTP(response, request)
+	problem := decodeProblem(t, response)
+	assert.Equal(t, 500, problem.Status)
+	assert.Equal(t, "internal server error", problem.Detail)
+	assert.Empty(t, problem.Errors)
+}
@@ -0,0 +1,42 @@
+package controllers
+
+import (
+	"news-topic-api/health"
+	"news-topic-api/helpers"
+	"net/http"
+)
+
+//HealthController ...
+type HealthController struct {
+	probe *health.Probe
+}
+
+//InitHealthController initializes health controller given the probe tracking readiness
+func InitHealthController(probe *health.Probe) HealthController {
+	healthController := new(HealthController)
+	healthController.probe = probe
+	return *healthController
+}
+
+//Live controller that answers liveness probes, it only tells the process is able to serve
+func (h *HealthController) Live(res http.ResponseWriter, req *http.Request) {
+	helpers.Response(res, http.StatusOK, map[string]string{"status": health.StatusOK})
+}
+
+//Ready controller that answers readiness probes with the status of every check,
+//it fails while the process is starting or draining. Check errors are only logged
+func (h *HealthController) Ready(res http.ResponseWriter, req *http.Request) {
+	report := h.probe.Check(req.Context())
+	if !report.Ready() {
+		logger := helpers.GetLogger(req.Context())
+		for name, result := range report.Checks {
+			if result.Status != health.StatusOK {
+				logger.Warn("readiness check failed", helpers.LogFields{"check": name, "error": result.Error, "latency_ms": result.LatencyMS})
+			}
+		}
+		logger.Warn("not ready", helpers.LogFields{"state": report.State})
+		helpers.Response(res, http.StatusServiceUnavailable, report)
+		return
+	}
+	helpers.Response(res, http.StatusOK, report)
+}
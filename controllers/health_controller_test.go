@@ -0,0 +1,86 @@
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"news-topic-api/health"
+	"news-topic-api/helpers"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func getHealthRouter(healthController HealthController) *mux.Router {
+	router := mux.NewRouter()
+	router.HandleFunc("/healthz", healthController.Live).Methods("GET")
+	router.HandleFunc("/readyz", healthController.Ready).Methods("GET")
+	return router
+}
+
+func decodeHealthReport(t *testing.T, response *httptest.ResponseRecorder) health.Report {
+	var body struct {
+		Data health.Report `json:"data"`
+	}
+	assert.Nil(t, json.NewDecoder(response.Body).Decode(&body), "response should be valid json")
+	return body.Data
+}
+
+func TestLiveShouldReturnOkWhileStarting(t *testing.T) {
+	healthController := InitHealthController(health.InitProbe(time.Second))
+	request, _ := http.NewRequest("GET", "/healthz", nil)
+	response := httptest.NewRecorder()
+	getHealthRouter(healthController).ServeHTTP(response, request)
+	assert.Equal(t, 200, response.Code, "response code should be 200")
+}
+
+func TestReadyShouldReturnOkWhenChecksPass(t *testing.T) {
+	probe := health.InitProbe(time.Second)
+	probe.AddCheck("database", func(ctx context.Context) error { return nil })
+	probe.MarkReady()
+	healthController := InitHealthController(probe)
+	request, _ := http.NewRequest("GET", "/readyz", nil)
+	response := httptest.NewRecorder()
+	getHealthRouter(healthController).ServeHTTP(response, request)
+	assert.Equal(t, 200, response.Code, "response code should be 200")
+	report := decodeHealthReport(t, response)
+	assert.Equal(t, health.StatusOK, report.Checks["database"].Status)
+}
+
+func TestReadyShouldReturnServiceUnavailableWhenCheckFails(t *testing.T) {
+	probe := health.InitProbe(time.Second)
+	probe.AddCheck("database", func(ctx context.Context) error { return fmt.Errorf("connection refused") })
+	probe.MarkReady()
+	healthController := InitHealthController(probe)
+	logs := new(bytes.Buffer)
+	request, _ := http.NewRequest("GET", "/readyz", nil)
+	request = request.WithContext(helpers.WithLogger(request.Context(), helpers.NewLogger(logs)))
+	response := httptest.NewRecorder()
+	getHealthRouter(healthController).ServeHTTP(response, request)
+	assert.Equal(t, 503, response.Code, "response code should be 503")
+	assert.NotContains(t, response.Body.String(), "connection refused", "check errors should not be answered")
+	var body struct {
+		Data struct {
+			Checks map[string]map[string]interface{} `json:"checks"`
+		} `json:"data"`
+	}
+	assert.Nil(t, json.Unmarshal(response.Body.Bytes(), &body))
+	assert.Equal(t, map[string]interface{}{"status": health.StatusFail}, body.Data.Checks["database"])
+	assert.Contains(t, logs.String(), `"error":"connection refused"`, "check errors should be logged")
+}
+
+func TestReadyShouldReturnServiceUnavailableWhileDraining(t *testing.T) {
+	probe := health.InitProbe(time.Second)
+	probe.MarkDraining()
+	healthController := InitHealthController(probe)
+	request, _ := http.NewRequest("GET", "/readyz", nil)
+	response := httptest.NewRecorder()
+	getHealthRouter(healthController).ServeHTTP(response, request)
+	assert.Equal(t, 503, response.Code, "response code should be 503")
+	assert.Equal(t, health.StateDraining, decodeHealthReport(t, response).State)
+}
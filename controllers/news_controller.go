@@ -1,11 +1,11 @@
 package controllers
 
 import (
-	"encoding/json"
-	"fmt"
 	"github.com/gorilla/mux"
+	"news-topic-api/dto"
 	"news-topic-api/helpers"
 	"news-topic-api/models"
+	"news-topic-api/policies"
 	"news-topic-api/services"
 	"net/http"
 	"strconv"
@@ -14,76 +14,119 @@ import (
 //NewsController ...
 type NewsController struct {
 	newsService services.INewsService
+	newsPolicy policies.INewsPolicy
 }
 
-//InitNewsController initializes news controller given the news service
-func InitNewsController(newsService services.INewsService) NewsController {
+//InitNewsController initializes news controller given the news service and the policy guarding it
+func InitNewsController(newsService services.INewsService, newsPolicy policies.INewsPolicy) NewsController {
 	newsController := new(NewsController)
 	newsController.newsService = newsService
+	newsController.newsPolicy = newsPolicy
 	return *newsController
 }
 
 //Create controller that handles create news request
 func (n *NewsController) Create(res http.ResponseWriter, req *http.Request) {
+	principal := getPrincipal(req)
+	if err := n.newsPolicy.CanWrite(principal); err != nil {
+		responseError(res, req, err)
+		return
+	}
 	reqBody, err := n.decodeRequest(req)
 	if err != nil {
-		fmt.Println(err)
-		helpers.ResponseError(res, http.StatusBadRequest, err)
+		responseBadRequest(res, req, err)
+		return
+	}
+	if err := n.newsPolicy.CanCreate(principal, reqBody); err != nil {
+		responseError(res, req, err)
 		return
 	}
-	resultData, err := n.newsService.Create(reqBody)
+	reqBody.CreatedBy = principal.Subject
+	resultData, err := n.newsService.Create(req.Context(), reqBody, getAuditMeta(req))
 	if err != nil {
-		helpers.ResponseError(res, http.StatusBadRequest, err)
+		responseError(res, req, err)
 		return
 	}
-	helpers.Response(res, http.StatusCreated, resultData)
+	helpers.Response(res, http.StatusCreated, dto.NewNewsResponse(resultData))
 }
 
 //Update controller that handles update news request
 func (n *NewsController) Update(res http.ResponseWriter, req *http.Request) {
-	reqBody, err := n.decodeRequest(req)
+	newsID, err := n.parseID(req)
 	if err != nil {
-		helpers.ResponseError(res, http.StatusBadRequest, err)
+		responseBadRequest(res, req, err)
 		return
 	}
-	newsID, err := n.parseID(req)
+	principal := getPrincipal(req)
+	if err := n.newsPolicy.CanWrite(principal); err != nil {
+		responseError(res, req, err)
+		return
+	}
+	reqBody, err := n.decodeRequest(req)
 	if err != nil {
-		helpers.ResponseError(res, http.StatusBadRequest, err)
+		responseBadRequest(res, req, err)
 		return
 	}
-	resultData, err := n.newsService.Update(newsID, reqBody)
+	if err := n.newsPolicy.CanUpdate(req.Context(), principal, newsID, reqBody); err != nil {
+		responseError(res, req, err)
+		return
+	}
+	resultData, err := n.newsService.Update(req.Context(), newsID, reqBody, getAuditMeta(req))
 	if err != nil {
-		helpers.ResponseError(res, http.StatusBadRequest, err)
+		responseError(res, req, err)
 		return
 	}
-	helpers.Response(res, http.StatusOK, resultData)
+	helpers.Response(res, http.StatusOK, dto.NewNewsResponse(resultData))
 }
 
 //Delete controller that handles delete news request
 func (n *NewsController) Delete(res http.ResponseWriter, req *http.Request) {
 	newsID, err := n.parseID(req)
 	if err != nil {
-		helpers.ResponseError(res, http.StatusBadRequest, err)
+		responseBadRequest(res, req, err)
+		return
+	}
+	if err := n.newsPolicy.CanDelete(getPrincipal(req), newsID); err != nil {
+		responseError(res, req, err)
 		return
 	}
-	err = n.newsService.Delete(newsID)
+	err = n.newsService.Delete(req.Context(), newsID, getAuditMeta(req))
 	if err != nil {
-		helpers.ResponseError(res, http.StatusBadRequest, err)
+		responseError(res, req, err)
 		return
 	}
 	helpers.Response(res, http.StatusOK, nil)
 }
 
+//Restore controller that handles restore deleted news request
+func (n *NewsController) Restore(res http.ResponseWriter, req *http.Request) {
+	newsID, err := n.parseID(req)
+	if err != nil {
+		responseBadRequest(res, req, err)
+		return
+	}
+	if err := n.newsPolicy.CanRestore(getPrincipal(req), newsID); err != nil {
+		responseError(res, req, err)
+		return
+	}
+	resultData, err := n.newsService.Restore(req.Context(), newsID, getAuditMeta(req))
+	if err != nil {
+		responseError(res, req, err)
+		return
+	}
+	helpers.Response(res, http.StatusOK, dto.NewNewsResponse(resultData))
+}
+
 //List controller that handles list news request
 func (n *NewsController) List(res http.ResponseWriter, req *http.Request) {
 	searchParams := n.parseParams(req)
 	var resultData models.NewsList
-	resultData, err := n.newsService.List(searchParams)
+	resultData, err := n.newsService.List(req.Context(), searchParams)
 	if err != nil {
-		helpers.ResponseError(res, http.StatusBadRequest, err)
+		responseError(res, req, err)
 		return
 	}
-	helpers.Response(res, http.StatusOK, resultData)
+	helpers.Response(res, http.StatusOK, dto.NewNewsListResponse(resultData))
 }
 
 
@@ -91,30 +134,33 @@ func (n *NewsController) List(res http.ResponseWriter, req *http.Request) {
 func(n *NewsController) GetDetail(res http.ResponseWriter, req *http.Request) {
 	newsID, err := n.parseID(req)
 	if err != nil {
-		helpers.ResponseError(res, http.StatusBadRequest, err)
+		responseBadRequest(res, req, err)
 		return
 	}
-	resultData, err := n.newsService.GetDetail(newsID)
+	resultData, err := n.newsService.GetDetail(req.Context(), newsID)
 	if err != nil {
-		helpers.ResponseError(res, http.StatusBadRequest, err)
+		responseError(res, req, err)
 		return
 	}
-	helpers.Response(res, http.StatusOK, resultData)
+	helpers.Response(res, http.StatusOK, dto.NewNewsResponse(resultData))
 }
 
 
 func (n *NewsController) decodeRequest(req *http.Request) (models.News, error) {
-	reqContent := models.News{}
-	if err := json.NewDecoder(req.Body).Decode(&reqContent); err != nil {
+	reqContent := dto.NewsRequest{}
+	if err := decodeStrict(req, &reqContent); err != nil {
 		return models.News{}, err
 	}
-	return reqContent, nil
+	if err := validateRequest(reqContent); err != nil {
+		return models.News{}, err
+	}
+	return reqContent.ToModel(), nil
 }
 
 func (n *NewsController) parseID(req *http.Request) (uint, error) {
 	id, err := strconv.ParseUint(mux.Vars(req)["id"], 10, 64)
 	if err != nil {
-		return uint(0), fmt.Errorf("invalid format for id")
+		return uint(0), invalidParameterError{name: "id"}
 	}
 	return uint(id), nil
 }
@@ -123,6 +169,7 @@ func (n *NewsController) parseParams(req *http.Request) map[string]string {
 	topic := req.URL.Query().Get("topic")
 	tag := req.URL.Query().Get("tag")
 	status := req.URL.Query().Get("status")
+	author := req.URL.Query().Get("author")
 	searchParams := make(map[string]string)
 	if topic != "" {
 		searchParams["topic"] = topic
@@ -133,5 +180,8 @@ func (n *NewsController) parseParams(req *http.Request) map[string]string {
 	if status != "" {
 		searchParams["status"] = status
 	}
+	if author != "" {
+		searchParams["author"] = author
+	}
 	return searchParams
 }
\ No newline at end of file
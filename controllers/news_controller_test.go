@@ -2,11 +2,13 @@ package controllers
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"gorm.io/gorm"
 	"news-topic-api/models"
+	"news-topic-api/policies"
+	"news-topic-api/services"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -35,6 +37,10 @@ func getNewsRouter(newsController NewsController, requestType string) *mux.Route
 		pathSuffix = "/{id}"
 		method = "DELETE"
 		controllerFunc = newsController.Delete
+	} else if requestType == "Restore" {
+		pathSuffix = "/{id}/restore"
+		method = "POST"
+		controllerFunc = newsController.Restore
 	} else if requestType == "List" {
 		pathSuffix = ""
 		method = "GET"
@@ -52,7 +58,7 @@ func createJSONRequestNews(method string, url string, data map[string]interface{
 	jsonData, _ := json.Marshal(data)
 	request, _ := http.NewRequest(method, url, bytes.NewReader(jsonData))
 	request.Header.Add("Content-Type", "application/json")
-	return request
+	return withRole(request, "42", models.RoleEditor)
 }
 
 func createURLParamRequestNews(method string, url string, data map[string]string) *http.Request {
@@ -68,7 +74,7 @@ func createURLParamRequestNews(method string, url string, data map[string]string
 
 func createURLStandardRequestNews(method, url string) *http.Request {
 	request, _ := http.NewRequest(method, url, nil)
-	return request
+	return withRole(request, "42", models.RoleEditor)
 }
 func getMockReqTag() map[string]interface{} {
 	tagData := make(map[string]interface{})
@@ -78,10 +84,10 @@ func getMockReqTag() map[string]interface{} {
 func getMockReqNews() map[string]interface{} {
 	newsData := make(map[string]interface{})
 	newsData["title"] = "Harga bitcoin anjlok"
-	newsData["thumbnail"] = "google.com/thumbnail.png"
+	newsData["thumbnail"] = "https://google.com/thumbnail.png"
 	newsData["summary"] = "Harga bitcoin sempat menurun namun dogecoin justru naik"
 	newsData["content"] = "Dikarenakan cuitan Elon Musk, nilai bitcoin sempat mengalami penurunan"
-	newsData["tags"] = []map[string]interface{}{getMockReqTag()}
+	newsData["tag_ids"] = []uint{1}
 	newsData["topic"] = "bitcoin"
 	newsData["status"] = "draft"
 	return newsData
@@ -119,8 +125,8 @@ func getMockNewsList() []models.News {
 func TestCreateNewsFailedShouldReturnBadRequest(t *testing.T) {
 	mockedRequestData := getMockReqNews()
 	mockedNewsService := new(mockServices.INewsService)
-	mockedNewsService.On("Create", mock.Anything).Return(models.News{}, fmt.Errorf("service can't create news"))
-	newsController := InitNewsController(mockedNewsService)
+	mockedNewsService.On("Create", mock.Anything, mock.Anything, mock.Anything).Return(models.News{}, services.InvalidInputError{Reason: "tag 9 does not exist"})
+	newsController := InitNewsController(mockedNewsService, policies.InitNewsPolicy(mockedNewsService))
 	request := createJSONRequestNews("POST", "/news/", mockedRequestData)
 	response := httptest.NewRecorder()
 	router := getNewsRouter(newsController, "Create")
@@ -132,7 +138,7 @@ func TestCreateNewsInvalidRequestShouldReturnBadRequest(t *testing.T) {
 	mockedRequestData := getMockReqNews()
 	mockedRequestData["title"] = 12345
 	mockedNewsService := new(mockServices.INewsService)
-	newsController := InitNewsController(mockedNewsService)
+	newsController := InitNewsController(mockedNewsService, policies.InitNewsPolicy(mockedNewsService))
 	request := createJSONRequestNews("POST", "/news/", mockedRequestData)
 	response := httptest.NewRecorder()
 	router := getNewsRouter(newsController, "Create")
@@ -143,8 +149,8 @@ func TestCreateNewsInvalidRequestShouldReturnBadRequest(t *testing.T) {
 func TestCreateNewsSuccessShouldReturnCreated(t *testing.T) {
 	mockedRequestData := getMockReqNews()
 	mockedNewsService := new(mockServices.INewsService)
-	mockedNewsService.On("Create", mock.Anything).Return(getMockNews(), nil)
-	newsController := InitNewsController(mockedNewsService)
+	mockedNewsService.On("Create", mock.Anything, mock.Anything, mock.Anything).Return(getMockNews(), nil)
+	newsController := InitNewsController(mockedNewsService, policies.InitNewsPolicy(mockedNewsService))
 	request := createJSONRequestNews("POST", "/news/", mockedRequestData)
 	response := httptest.NewRecorder()
 	router := getNewsRouter(newsController, "Create")
@@ -155,8 +161,8 @@ func TestCreateNewsSuccessShouldReturnCreated(t *testing.T) {
 func TestUpdateNewsFailedShouldReturnBadRequest(t *testing.T) {
 	mockedRequestData := getMockReqNews()
 	mockedNewsService := new(mockServices.INewsService)
-	mockedNewsService.On("Update", uint(1), mock.Anything).Return(models.News{}, errors.New("News failed to update"))
-	newsController := InitNewsController(mockedNewsService)
+	mockedNewsService.On("Update", mock.Anything, uint(1), mock.Anything, mock.Anything).Return(models.News{}, services.InvalidInputError{Reason: "News failed to update"})
+	newsController := InitNewsController(mockedNewsService, policies.InitNewsPolicy(mockedNewsService))
 	request := createJSONRequestNews("PUT", "/news/1", mockedRequestData)
 	response := httptest.NewRecorder()
 	router := getNewsRouter(newsController, "Update")
@@ -168,7 +174,7 @@ func TestUpdateNewsInvalidRequestDataShouldReturnBadRequest(t *testing.T) {
 	mockedRequestData := getMockReqNews()
 	mockedRequestData["title"] = 123
 	mockedNewsService := new(mockServices.INewsService)
-	newsController := InitNewsController(mockedNewsService)
+	newsController := InitNewsController(mockedNewsService, policies.InitNewsPolicy(mockedNewsService))
 	request := createJSONRequestNews("PUT", "/news/1", mockedRequestData)
 	response := httptest.NewRecorder()
 	router := getNewsRouter(newsController, "Update")
@@ -179,7 +185,7 @@ func TestUpdateNewsInvalidRequestDataShouldReturnBadRequest(t *testing.T) {
 func TestUpdateNewslnvalidIDOnURLShouldReturnBadRequest(t *testing.T) {
 	mockedRequestData := getMockReqNews()
 	mockedNewsService := new(mockServices.INewsService)
-	newsController := InitNewsController(mockedNewsService)
+	newsController := InitNewsController(mockedNewsService, policies.InitNewsPolicy(mockedNewsService))
 	request := createJSONRequestNews("PUT", "/news/asdasdasdasdasdasdasd", mockedRequestData)
 	response := httptest.NewRecorder()
 	router := getNewsRouter(newsController, "Update")
@@ -190,8 +196,8 @@ func TestUpdateNewslnvalidIDOnURLShouldReturnBadRequest(t *testing.T) {
 func TestUpdateNewsSuccessShouldReturnOk(t *testing.T) {
 	mockedRequestData := getMockReqNews()
 	mockedNewsService := new(mockServices.INewsService)
-	mockedNewsService.On("Update", uint(1), mock.Anything).Return(getMockNews(), nil)
-	newsController := InitNewsController(mockedNewsService)
+	mockedNewsService.On("Update", mock.Anything, uint(1), mock.Anything, mock.Anything).Return(getMockNews(), nil)
+	newsController := InitNewsController(mockedNewsService, policies.InitNewsPolicy(mockedNewsService))
 	request := createJSONRequestNews("PUT", "/news/1", mockedRequestData)
 	response := httptest.NewRecorder()
 	router := getNewsRouter(newsController, "Update")
@@ -199,21 +205,21 @@ func TestUpdateNewsSuccessShouldReturnOk(t *testing.T) {
 	assert.Equal(t, 200, response.Code, "response code should be 200")
 }
 
-func TestDeleteNewsFailedShouldReturnBadRequest(t *testing.T) {
+func TestDeleteNewsNotFoundShouldReturnNotFound(t *testing.T) {
 	mockedNewsService := new(mockServices.INewsService)
-	mockedNewsService.On("Delete", uint(1)).Return(errors.New("News failed to delete"))
-	newsController := InitNewsController(mockedNewsService)
+	mockedNewsService.On("Delete", mock.Anything, uint(1), mock.Anything).Return(services.NotFoundError{Entity: "news", ID: 1})
+	newsController := InitNewsController(mockedNewsService, policies.InitNewsPolicy(mockedNewsService))
 	request := createURLStandardRequestNews("DELETE", "/news/1")
 	response := httptest.NewRecorder()
 	router := getNewsRouter(newsController, "Delete")
 	router.ServeHTTP(response, request)
-	assert.Equal(t, 400, response.Code, "response code should be 400")
+	assert.Equal(t, 404, response.Code, "response code should be 404")
 }
 
 func TestDeleteNewsSuccessShouldReturnOk(t *testing.T) {
 	mockedNewsService := new(mockServices.INewsService)
-	mockedNewsService.On("Delete", uint(1)).Return(nil)
-	newsController := InitNewsController(mockedNewsService)
+	mockedNewsService.On("Delete", mock.Anything, uint(1), mock.Anything).Return(nil)
+	newsController := InitNewsController(mockedNewsService, policies.InitNewsPolicy(mockedNewsService))
 	request := createURLStandardRequestNews("DELETE", "/news/1")
 	response := httptest.NewRecorder()
 	router := getNewsRouter(newsController, "Delete")
@@ -223,7 +229,7 @@ func TestDeleteNewsSuccessShouldReturnOk(t *testing.T) {
 
 func TestDeleteNewslnvalidIDOnURLShouldReturnBadRequest(t *testing.T) {
 	mockedNewsService := new(mockServices.INewsService)
-	newsController := InitNewsController(mockedNewsService)
+	newsController := InitNewsController(mockedNewsService, policies.InitNewsPolicy(mockedNewsService))
 	request := createURLStandardRequestNews("DELETE", "/news/asdasdasdasdasdasdasd")
 	response := httptest.NewRecorder()
 	router := getNewsRouter(newsController, "Delete")
@@ -231,12 +237,34 @@ func TestDeleteNewslnvalidIDOnURLShouldReturnBadRequest(t *testing.T) {
 	assert.Equal(t, 400, response.Code, "response code should be 400")
 }
 
+func TestRestoreNewsSuccessShouldReturnOk(t *testing.T) {
+	mockedNewsService := new(mockServices.INewsService)
+	mockedNewsService.On("Restore", mock.Anything, uint(1), mock.Anything).Return(getMockNews(), nil)
+	newsController := InitNewsController(mockedNewsService, policies.InitNewsPolicy(mockedNewsService))
+	request := createURLStandardRequestNews("POST", "/news/1/restore")
+	response := httptest.NewRecorder()
+	router := getNewsRouter(newsController, "Restore")
+	router.ServeHTTP(response, request)
+	assert.Equal(t, 200, response.Code, "response code should be 200")
+}
+
+func TestRestoreNewsNotDeletedShouldReturnNotFound(t *testing.T) {
+	mockedNewsService := new(mockServices.INewsService)
+	mockedNewsService.On("Restore", mock.Anything, uint(1), mock.Anything).Return(models.News{}, services.NotFoundError{Entity: "deleted news", ID: 1})
+	newsController := InitNewsController(mockedNewsService, policies.InitNewsPolicy(mockedNewsService))
+	request := createURLStandardRequestNews("POST", "/news/1/restore")
+	response := httptest.NewRecorder()
+	router := getNewsRouter(newsController, "Restore")
+	router.ServeHTTP(response, request)
+	assert.Equal(t, 404, response.Code, "response code should be 404")
+}
+
 func TestListNewsSuccessShouldReturnOk(t *testing.T) {
 	mockedServiceDataList := getMockNewsList()
 	mockedNewsService := new(mockServices.INewsService)
 	searchParams := getMockRequestParamsNews()
-	mockedNewsService.On("List", searchParams).Return(models.NewsList{Data: mockedServiceDataList}, nil)
-	newsController := InitNewsController(mockedNewsService)
+	mockedNewsService.On("List", mock.Anything, searchParams).Return(models.NewsList{Data: mockedServiceDataList}, nil)
+	newsController := InitNewsController(mockedNewsService, policies.InitNewsPolicy(mockedNewsService))
 	request := createURLParamRequestNews("GET", "/news", searchParams)
 	response := httptest.NewRecorder()
 	router := getNewsRouter(newsController, "List")
@@ -247,8 +275,8 @@ func TestListNewsSuccessShouldReturnOk(t *testing.T) {
 func TestListNewsFailedShouldReturnBadRequest(t *testing.T) {
 	mockedNewsService := new(mockServices.INewsService)
 	searchParams := getMockRequestParamsNews()
-	mockedNewsService.On("List", searchParams).Return(models.NewsList{}, fmt.Errorf("Data empty"))
-	newsController := InitNewsController(mockedNewsService)
+	mockedNewsService.On("List", mock.Anything, searchParams).Return(models.NewsList{}, services.InvalidInputError{Reason: "invalid format for tag"})
+	newsController := InitNewsController(mockedNewsService, policies.InitNewsPolicy(mockedNewsService))
 	request := createURLParamRequestNews("GET", "/news", searchParams)
 	response := httptest.NewRecorder()
 	router := getNewsRouter(newsController, "List")
@@ -259,8 +287,8 @@ func TestListNewsFailedShouldReturnBadRequest(t *testing.T) {
 func TestGetDetailNewsSuccesShouldReturnOk(t *testing.T) {
 	mockedNewsEntity := getMockNews()
 	mockedNewsService := new(mockServices.INewsService)
-	mockedNewsService.On("GetDetail", uint(1)).Return(mockedNewsEntity, nil)
-	newsController := InitNewsController(mockedNewsService)
+	mockedNewsService.On("GetDetail", mock.Anything, uint(1)).Return(mockedNewsEntity, nil)
+	newsController := InitNewsController(mockedNewsService, policies.InitNewsPolicy(mockedNewsService))
 	request := createURLStandardRequestNews("GET", "/news/1")
 	response := httptest.NewRecorder()
 	router := getNewsRouter(newsController, "Detail")
@@ -268,23 +296,72 @@ func TestGetDetailNewsSuccesShouldReturnOk(t *testing.T) {
 	assert.Equal(t, 200, response.Code, "response code should be 200")
 }
 
-func TestGetDetailNewsFailedShouldReturnBadRequest(t *testing.T) {
+func TestGetDetailNewsNotFoundShouldReturnNotFound(t *testing.T) {
 	mockedNewsService := new(mockServices.INewsService)
-	mockedNewsService.On("GetDetail", uint(1)).Return(models.News{}, fmt.Errorf("Data not exists"))
-	newsController := InitNewsController(mockedNewsService)
+	mockedNewsService.On("GetDetail", mock.Anything, uint(1)).Return(models.News{}, services.NotFoundError{Entity: "news", ID: 1})
+	newsController := InitNewsController(mockedNewsService, policies.InitNewsPolicy(mockedNewsService))
 	request := createURLStandardRequestNews("GET", "/news/1")
 	response := httptest.NewRecorder()
 	router := getNewsRouter(newsController, "Detail")
 	router.ServeHTTP(response, request)
-	assert.Equal(t, 400, response.Code, "response code should be 400")
+	assert.Equal(t, 404, response.Code, "response code should be 404")
 }
 
+func TestListNewsDatabaseDownShouldReturnServiceUnavailable(t *testing.T) {
+	mockedNewsService := new(mockServices.INewsService)
+	searchParams := getMockRequestParamsNews()
+	mockedNewsService.On("List", mock.Anything, searchParams).Return(models.NewsList{}, services.UnavailableError{Cause: fmt.Errorf("dial tcp 10.0.0.5:5432: connect: connection refused")})
+	newsController := InitNewsController(mockedNewsService, policies.InitNewsPolicy(mockedNewsService))
+	request := createURLParamRequestNews("GET", "/news", searchParams)
+	response := httptest.NewRecorder()
+	router := getNewsRouter(newsController, "List")
+	router.ServeHTTP(response, request)
+	assert.Equal(t, 503, response.Code, "response code should be 503")
+	assert.NotContains(t, response.Body.String(), "10.0.0.5", "database errors should not be shown")
+}
+func TestGetDetailNewsTimedOutShouldReturnGatewayTimeout(t *testing.T) {
+	mockedNewsService := new(mockServices.INewsService)
+	mockedNewsService.On("GetDetail", mock.Anything, uint(1)).Return(models.News{}, fmt.Errorf("querying news: %w", context.DeadlineExceeded))
+	newsController := InitNewsController(mockedNewsService, policies.InitNewsPolicy(mockedNewsService))
+	request := createURLStandardRequestNews("GET", "/news/1")
+	response := httptest.NewRecorder()
+	router := getNewsRouter(newsController, "Detail")
+	router.ServeHTTP(response, request)
+	assert.Equal(t, 504, response.Code, "response code should be 504")
+}
+func TestListNewsClientGoneShouldReturnClientClosedRequest(t *testing.T) {
+	mockedNewsService := new(mockServices.INewsService)
+	searchParams := getMockRequestParamsNews()
+	mockedNewsService.On("List", mock.Anything, searchParams).Return(models.NewsList{}, fmt.Errorf("canceling statement due to user request"))
+	newsController := InitNewsController(mockedNewsService, policies.InitNewsPolicy(mockedNewsService))
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	request := createURLParamRequestNews("GET", "/news", searchParams).WithContext(ctx)
+	response := httptest.NewRecorder()
+	router := getNewsRouter(newsController, "List")
+	router.ServeHTTP(response, request)
+	assert.Equal(t, 499, response.Code, "response code should be 499")
+}
 func TestGetDetailNewslnvalidIDOnURLShouldReturnBadRequest(t *testing.T) {
 	mockedNewsService := new(mockServices.INewsService)
-	newsController := InitNewsController(mockedNewsService)
+	newsController := InitNewsController(mockedNewsService, policies.InitNewsPolicy(mockedNewsService))
 	request := createURLStandardRequestNews("GET", "/news/asdadasdasdasdasdadas")
 	response := httptest.NewRecorder()
 	router := getNewsRouter(newsController, "Detail")
 	router.ServeHTTP(response, request)
 	assert.Equal(t, 400, response.Code, "response code should be 400")
 }
+
+func TestListNewsByAuthorShouldPassAuthorFilter(t *testing.T) {
+	mockedNewsService := new(mockServices.INewsService)
+	searchParams := getMockRequestParamsNews()
+	searchParams["author"] = "1"
+	mockedNewsService.On("List", mock.Anything, searchParams).Return(models.NewsList{Data: getMockNewsList()}, nil)
+	newsController := InitNewsController(mockedNewsService, policies.InitNewsPolicy(mockedNewsService))
+	request := createURLParamRequestNews("GET", "/news", searchParams)
+	response := httptest.NewRecorder()
+	router := getNewsRouter(newsController, "List")
+	router.ServeHTTP(response, request)
+	assert.Equal(t, 200, response.Code, "response code should be 200")
+	mockedNewsService.AssertExpectations(t)
+}
@@ -0,0 +1,52 @@
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"news-topic-api/helpers"
+	"news-topic-api/models"
+	"strconv"
+	"strings"
+)
+
+func getPrincipal(req *http.Request) models.Principal {
+	principal, _ := helpers.GetPrincipal(req.Context())
+	return principal
+}
+
+//getAuditMeta identifies the caller and request a mutation is recorded against
+func getAuditMeta(req *http.Request) models.AuditMeta {
+	return models.AuditMeta{
+		Actor: getPrincipal(req).Subject,
+		RequestID: helpers.GetRequestID(req.Context()),
+		IP: helpers.GetClientIP(req),
+	}
+}
+
+//decodeStrict decodes the json body of req into body, fields body does not declare are rejected
+//with an unknownFieldError instead of being silently dropped
+func decodeStrict(req *http.Request, body interface{}) error {
+	decoder := json.NewDecoder(req.Body)
+	decoder.DisallowUnknownFields()
+	err := decoder.Decode(body)
+	if err != nil && strings.HasPrefix(err.Error(), unknownFieldPrefix) {
+		field, unquoteErr := strconv.Unquote(strings.TrimPrefix(err.Error(), unknownFieldPrefix))
+		if unquoteErr == nil {
+			return unknownFieldError{field: field}
+		}
+	}
+	return err
+}
+
+//unknownFieldPrefix starts the message of the untyped error encoding/json returns for unknown fields
+const unknownFieldPrefix = "json: unknown field "
+
+//unknownFieldError is returned when a request body carries a field its dto does not declare
+type unknownFieldError struct {
+	field string
+}
+
+func (u unknownFieldError) Error() string {
+	return fmt.Sprintf("unknown field %s", u.field)
+}
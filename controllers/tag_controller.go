@@ -1,11 +1,11 @@
 package controllers
 
 import (
-	"encoding/json"
-	"fmt"
 	"github.com/gorilla/mux"
+	"news-topic-api/dto"
 	"news-topic-api/helpers"
 	"news-topic-api/models"
+	"news-topic-api/policies"
 	"news-topic-api/services"
 	"net/http"
 	"strconv"
@@ -14,89 +14,124 @@ import (
 //TagController ...
 type TagController struct {
 	tagService services.ITagService
+	tagPolicy policies.ITagPolicy
 }
 
-//InitTagController initializes tag controller given the tag service
-func InitTagController(tagService services.ITagService) TagController {
+//InitTagController initializes tag controller given the tag service and the policy guarding it
+func InitTagController(tagService services.ITagService, tagPolicy policies.ITagPolicy) TagController {
 	tagController := new(TagController)
 	tagController.tagService = tagService
+	tagController.tagPolicy = tagPolicy
 	return *tagController
 }
 
 //Create controller that handles create tag request
 func (t *TagController) Create(res http.ResponseWriter, req *http.Request) {
+	if err := t.tagPolicy.CanManage(getPrincipal(req)); err != nil {
+		responseError(res, req, err)
+		return
+	}
 	reqBody, err := t.decodeRequest(req)
 	if err != nil {
-		fmt.Println(err)
-		helpers.ResponseError(res, http.StatusBadRequest, err)
+		responseBadRequest(res, req, err)
 		return
 	}
-	resultData, err := t.tagService.Create(reqBody)
+	resultData, err := t.tagService.Create(req.Context(), reqBody, getAuditMeta(req))
 	if err != nil {
-		helpers.ResponseError(res, http.StatusBadRequest, err)
+		responseError(res, req, err)
 		return
 	}
-	helpers.Response(res, http.StatusCreated, resultData)
+	helpers.Response(res, http.StatusCreated, dto.NewTagResponse(resultData))
 }
 
 //Update controller that handles update tag request
 func (t *TagController) Update(res http.ResponseWriter, req *http.Request) {
+	if err := t.tagPolicy.CanManage(getPrincipal(req)); err != nil {
+		responseError(res, req, err)
+		return
+	}
 	reqBody, err := t.decodeRequest(req)
 	if err != nil {
-		helpers.ResponseError(res, http.StatusBadRequest, err)
+		responseBadRequest(res, req, err)
 		return
 	}
 	tagID, err := t.parseID(req)
 	if err != nil {
-		helpers.ResponseError(res, http.StatusBadRequest, err)
+		responseBadRequest(res, req, err)
 		return
 	}
-	resultData, err := t.tagService.Update(tagID, reqBody)
+	resultData, err := t.tagService.Update(req.Context(), tagID, reqBody, getAuditMeta(req))
 	if err != nil {
-		helpers.ResponseError(res, http.StatusBadRequest, err)
+		responseError(res, req, err)
 		return
 	}
-	helpers.Response(res, http.StatusOK, resultData)
+	helpers.Response(res, http.StatusOK, dto.NewTagResponse(resultData))
 }
 
 //Delete controller that handles delete tag request
 func (t *TagController) Delete(res http.ResponseWriter, req *http.Request) {
+	if err := t.tagPolicy.CanManage(getPrincipal(req)); err != nil {
+		responseError(res, req, err)
+		return
+	}
 	tagID, err := t.parseID(req)
 	if err != nil {
-		helpers.ResponseError(res, http.StatusBadRequest, err)
+		responseBadRequest(res, req, err)
 		return
 	}
-	err = t.tagService.Delete(tagID)
+	err = t.tagService.Delete(req.Context(), tagID, getAuditMeta(req))
 	if err != nil {
-		helpers.ResponseError(res, http.StatusBadRequest, err)
+		responseError(res, req, err)
 		return
 	}
 	helpers.Response(res, http.StatusOK, nil)
 }
 
+//Restore controller that handles restore deleted tag request
+func (t *TagController) Restore(res http.ResponseWriter, req *http.Request) {
+	if err := t.tagPolicy.CanManage(getPrincipal(req)); err != nil {
+		responseError(res, req, err)
+		return
+	}
+	tagID, err := t.parseID(req)
+	if err != nil {
+		responseBadRequest(res, req, err)
+		return
+	}
+	resultData, err := t.tagService.Restore(req.Context(), tagID, getAuditMeta(req))
+	if err != nil {
+		responseError(res, req, err)
+		return
+	}
+	helpers.Response(res, http.StatusOK, dto.NewTagResponse(resultData))
+}
+
 //List controller that handles list tag request
 func (t *TagController) List(res http.ResponseWriter, req *http.Request) {
 	var resultData models.TagsList
-	resultData, err := t.tagService.List()
+	resultData, err := t.tagService.List(req.Context())
 	if err != nil {
-		helpers.ResponseError(res, http.StatusBadRequest, err)
+		responseError(res, req, err)
 		return
 	}
-	helpers.Response(res, http.StatusOK, resultData)
+	helpers.Response(res, http.StatusOK, dto.NewTagsListResponse(resultData))
 }
 
 func (t *TagController) decodeRequest(req *http.Request) (models.Tag, error) {
-	reqContent := models.Tag{}
-	if err := json.NewDecoder(req.Body).Decode(&reqContent); err != nil {
+	reqContent := dto.TagRequest{}
+	if err := decodeStrict(req, &reqContent); err != nil {
+		return models.Tag{}, err
+	}
+	if err := validateRequest(reqContent); err != nil {
 		return models.Tag{}, err
 	}
-	return reqContent, nil
+	return reqContent.ToModel(), nil
 }
 
 func (t *TagController) parseID(req *http.Request) (uint, error) {
 	id, err := strconv.ParseUint(mux.Vars(req)["id"], 10, 64)
 	if err != nil {
-		return uint(0), fmt.Errorf("invalid format for id")
+		return uint(0), invalidParameterError{name: "id"}
 	}
 	return uint(id), nil
 }
\ No newline at end of file
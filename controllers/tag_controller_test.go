@@ -3,9 +3,10 @@ package controllers
 import (
 	"bytes"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"news-topic-api/models"
+	"news-topic-api/policies"
+	"news-topic-api/services"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -34,6 +35,10 @@ func getTagRouter(tagController TagController, requestType string) *mux.Router {
 		pathSuffix = "/{id}"
 		method = "DELETE"
 		controllerFunc = tagController.Delete
+	} else if requestType == "Restore" {
+		pathSuffix = "/{id}/restore"
+		method = "POST"
+		controllerFunc = tagController.Restore
 	} else {
 		pathSuffix = ""
 		method = "GET"
@@ -47,12 +52,12 @@ func createJSONRequestTag(method string, url string, data map[string]interface{}
 	jsonData, _ := json.Marshal(data)
 	request, _ := http.NewRequest(method, url, bytes.NewReader(jsonData))
 	request.Header.Add("Content-Type", "application/json")
-	return request
+	return withRole(request, "1", models.RoleAdmin)
 }
 
 func createURLStandardRequestTag(method, url string) *http.Request {
 	request, _ := http.NewRequest(method, url, nil)
-	return request
+	return withRole(request, "1", models.RoleAdmin)
 }
 func getMockRequestTag() map[string]interface{} {
 	tagData := make(map[string]interface{})
@@ -76,8 +81,8 @@ func getMockTagList() []models.Tag {
 func TestCreateTagFailedShouldReturnBadRequest(t *testing.T) {
 	mockedRequestData := getMockRequestTag()
 	mockedTagService := new(mockServices.ITagService)
-	mockedTagService.On("Create", mock.Anything).Return(models.Tag{}, fmt.Errorf("service can't create tag"))
-	tagController := InitTagController(mockedTagService)
+	mockedTagService.On("Create", mock.Anything, mock.Anything, mock.Anything).Return(models.Tag{}, services.InvalidInputError{Reason: "service can't create tag"})
+	tagController := InitTagController(mockedTagService, policies.InitTagPolicy())
 	request := createJSONRequestTag("POST", "/tag/", mockedRequestData)
 	response := httptest.NewRecorder()
 	router := getTagRouter(tagController, "Create")
@@ -89,7 +94,7 @@ func TestCreateTagInvalidRequestShouldReturnBadRequest(t *testing.T) {
 	mockedRequestData := getMockRequestTag()
 	mockedRequestData["name"] = 12345
 	mockedTagService := new(mockServices.ITagService)
-	tagController := InitTagController(mockedTagService)
+	tagController := InitTagController(mockedTagService, policies.InitTagPolicy())
 	request := createJSONRequestTag("POST", "/tag/", mockedRequestData)
 	response := httptest.NewRecorder()
 	router := getTagRouter(tagController, "Create")
@@ -100,8 +105,8 @@ func TestCreateTagInvalidRequestShouldReturnBadRequest(t *testing.T) {
 func TestCreateTagSuccessShouldReturnCreated(t *testing.T) {
 	mockedRequestData := getMockRequestTag()
 	mockedTagService := new(mockServices.ITagService)
-	mockedTagService.On("Create", mock.Anything).Return(getMockTag(), nil)
-	tagController := InitTagController(mockedTagService)
+	mockedTagService.On("Create", mock.Anything, mock.Anything, mock.Anything).Return(getMockTag(), nil)
+	tagController := InitTagController(mockedTagService, policies.InitTagPolicy())
 	request := createJSONRequestTag("POST", "/tag/", mockedRequestData)
 	response := httptest.NewRecorder()
 	router := getTagRouter(tagController, "Create")
@@ -112,8 +117,8 @@ func TestCreateTagSuccessShouldReturnCreated(t *testing.T) {
 func TestUpdateTagFailedShouldReturnBadRequest(t *testing.T) {
 	mockedRequestData := getMockRequestTag()
 	mockedTagService := new(mockServices.ITagService)
-	mockedTagService.On("Update", uint(1), mock.Anything).Return(models.Tag{}, errors.New("Tag failed to update"))
-	tagController := InitTagController(mockedTagService)
+	mockedTagService.On("Update", mock.Anything, uint(1), mock.Anything, mock.Anything).Return(models.Tag{}, services.InvalidInputError{Reason: "Tag failed to update"})
+	tagController := InitTagController(mockedTagService, policies.InitTagPolicy())
 	request := createJSONRequestTag("PUT", "/tag/1", mockedRequestData)
 	response := httptest.NewRecorder()
 	router := getTagRouter(tagController, "Update")
@@ -125,7 +130,7 @@ func TestUpdateTagInvalidRequestDataShouldReturnBadRequest(t *testing.T) {
 	mockedRequestData := getMockRequestTag()
 	mockedRequestData["name"] = 123
 	mockedTagService := new(mockServices.ITagService)
-	tagController := InitTagController(mockedTagService)
+	tagController := InitTagController(mockedTagService, policies.InitTagPolicy())
 	request := createJSONRequestTag("PUT", "/tag/1", mockedRequestData)
 	response := httptest.NewRecorder()
 	router := getTagRouter(tagController, "Update")
@@ -136,7 +141,7 @@ func TestUpdateTagInvalidRequestDataShouldReturnBadRequest(t *testing.T) {
 func TestUpdateTaglnvalidIDOnURLShouldReturnBadRequest(t *testing.T) {
 	mockedRequestData := getMockRequestTag()
 	mockedTagService := new(mockServices.ITagService)
-	tagController := InitTagController(mockedTagService)
+	tagController := InitTagController(mockedTagService, policies.InitTagPolicy())
 	request := createJSONRequestTag("PUT", "/tag/asdasdasdasdasdasdasd", mockedRequestData)
 	response := httptest.NewRecorder()
 	router := getTagRouter(tagController, "Update")
@@ -147,8 +152,8 @@ func TestUpdateTaglnvalidIDOnURLShouldReturnBadRequest(t *testing.T) {
 func TestUpdateTagSuccessShouldReturnOk(t *testing.T) {
 	mockedRequestData := getMockRequestTag()
 	mockedTagService := new(mockServices.ITagService)
-	mockedTagService.On("Update", uint(1), mock.Anything).Return(getMockTag(), nil)
-	tagController := InitTagController(mockedTagService)
+	mockedTagService.On("Update", mock.Anything, uint(1), mock.Anything, mock.Anything).Return(getMockTag(), nil)
+	tagController := InitTagController(mockedTagService, policies.InitTagPolicy())
 	request := createJSONRequestTag("PUT", "/tag/1", mockedRequestData)
 	response := httptest.NewRecorder()
 	router := getTagRouter(tagController, "Update")
@@ -156,21 +161,21 @@ func TestUpdateTagSuccessShouldReturnOk(t *testing.T) {
 	assert.Equal(t, 200, response.Code, "response code should be 200")
 }
 
-func TestDeleteTagFailedShouldReturnBadRequest(t *testing.T) {
+func TestDeleteTagNotFoundShouldReturnNotFound(t *testing.T) {
 	mockedTagService := new(mockServices.ITagService)
-	mockedTagService.On("Delete", uint(1)).Return(errors.New("Tag failed to delete"))
-	tagController := InitTagController(mockedTagService)
+	mockedTagService.On("Delete", mock.Anything, uint(1), mock.Anything).Return(services.NotFoundError{Entity: "tag", ID: 1})
+	tagController := InitTagController(mockedTagService, policies.InitTagPolicy())
 	request := createURLStandardRequestTag("DELETE", "/tag/1")
 	response := httptest.NewRecorder()
 	router := getTagRouter(tagController, "Delete")
 	router.ServeHTTP(response, request)
-	assert.Equal(t, 400, response.Code, "response code should be 400")
+	assert.Equal(t, 404, response.Code, "response code should be 404")
 }
 
 func TestDeleteTagSuccessShouldReturnOk(t *testing.T) {
 	mockedTagService := new(mockServices.ITagService)
-	mockedTagService.On("Delete", uint(1)).Return(nil)
-	tagController := InitTagController(mockedTagService)
+	mockedTagService.On("Delete", mock.Anything, uint(1), mock.Anything).Return(nil)
+	tagController := InitTagController(mockedTagService, policies.InitTagPolicy())
 	request := createURLStandardRequestTag("DELETE", "/tag/1")
 	response := httptest.NewRecorder()
 	router := getTagRouter(tagController, "Delete")
@@ -180,7 +185,7 @@ func TestDeleteTagSuccessShouldReturnOk(t *testing.T) {
 
 func TestDeleteTaglnvalidIDOnURLShouldReturnBadRequest(t *testing.T) {
 	mockedTagService := new(mockServices.ITagService)
-	tagController := InitTagController(mockedTagService)
+	tagController := InitTagController(mockedTagService, policies.InitTagPolicy())
 	request := createURLStandardRequestTag("DELETE", "/tag/asdasdasdasdasdasdasd")
 	response := httptest.NewRecorder()
 	router := getTagRouter(tagController, "Delete")
@@ -188,11 +193,22 @@ func TestDeleteTaglnvalidIDOnURLShouldReturnBadRequest(t *testing.T) {
 	assert.Equal(t, 400, response.Code, "response code should be 400")
 }
 
+func TestRestoreTagSuccessShouldReturnOk(t *testing.T) {
+	mockedTagService := new(mockServices.ITagService)
+	mockedTagService.On("Restore", mock.Anything, uint(1), mock.Anything).Return(getMockTag(), nil)
+	tagController := InitTagController(mockedTagService, policies.InitTagPolicy())
+	request := createURLStandardRequestTag("POST", "/tag/1/restore")
+	response := httptest.NewRecorder()
+	router := getTagRouter(tagController, "Restore")
+	router.ServeHTTP(response, request)
+	assert.Equal(t, 200, response.Code, "response code should be 200")
+}
+
 func TestListTagSuccessShouldReturnOk(t *testing.T) {
 	mockedServiceDataList := getMockTagList()
 	mockedTagService := new(mockServices.ITagService)
-	mockedTagService.On("List").Return(models.TagsList{Data: mockedServiceDataList}, nil)
-	tagController := InitTagController(mockedTagService)
+	mockedTagService.On("List", mock.Anything).Return(models.TagsList{Data: mockedServiceDataList}, nil)
+	tagController := InitTagController(mockedTagService, policies.InitTagPolicy())
 	request := createURLStandardRequestTag("GET", "/tag")
 	response := httptest.NewRecorder()
 	router := getTagRouter(tagController, "List")
@@ -200,13 +216,26 @@ func TestListTagSuccessShouldReturnOk(t *testing.T) {
 	assert.Equal(t, 200, response.Code, "response code should be 200")
 }
 
-func TestListTagFailedShouldReturnBadRequest(t *testing.T) {
+func TestListTagFailedShouldReturnInternalServerError(t *testing.T) {
 	mockedTagService := new(mockServices.ITagService)
-	mockedTagService.On("List").Return(models.TagsList{}, fmt.Errorf("Data empty"))
-	tagController := InitTagController(mockedTagService)
+	mockedTagService.On("List", mock.Anything).Return(models.TagsList{}, fmt.Errorf(`pq: relation "tags" does not exist`))
+	tagController := InitTagController(mockedTagService, policies.InitTagPolicy())
 	request := createURLStandardRequestTag("GET", "/tag")
 	response := httptest.NewRecorder()
 	router := getTagRouter(tagController, "List")
 	router.ServeHTTP(response, request)
-	assert.Equal(t, 400, response.Code, "response code should be 400")
+	assert.Equal(t, 500, response.Code, "response code should be 500")
+	assert.NotContains(t, response.Body.String(), "relation", "database errors should not be shown")
 }
+func TestCreateTagDuplicateNameShouldReturnConflict(t *testing.T) {
+	mockedRequestData := getMockRequestTag()
+	mockedTagService := new(mockServices.ITagService)
+	mockedTagService.On("Create", mock.Anything, mock.Anything, mock.Anything).Return(models.Tag{}, services.ConflictError{Reason: `a tag named "cryptocurrency" already exists`})
+	tagController := InitTagController(mockedTagService, policies.InitTagPolicy())
+	request := createJSONRequestTag("POST", "/tag/", mockedRequestData)
+	response := httptest.NewRecorder()
+	router := getTagRouter(tagController, "Create")
+	router.ServeHTTP(response, request)
+	assert.Equal(t, 409, response.Code, "response code should be 409")
+}
+
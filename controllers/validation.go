@@ -0,0 +1,74 @@
+package controllers
+
+import (
+	"fmt"
+	"news-topic-api/helpers"
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+//requestValidator checks request bodies against their validate struct tags, fields are reported by json name
+var requestValidator = initRequestValidator()
+
+func initRequestValidator() *validator.Validate {
+	validate := validator.New()
+	validate.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+	return validate
+}
+
+//validationError lists every field of a request body that broke its rules
+type validationError struct {
+	fields []helpers.FieldProblem
+}
+
+func (v validationError) Error() string {
+	if len(v.fields) == 1 {
+		return fmt.Sprintf("%s %s", v.fields[0].Field, v.fields[0].Detail)
+	}
+	return fmt.Sprintf("%d fields are invalid", len(v.fields))
+}
+
+//validateRequest checks the decoded body, all the violations are returned at once
+func validateRequest(body interface{}) error {
+	err := requestValidator.Struct(body)
+	violations, ok := err.(validator.ValidationErrors)
+	if !ok {
+		return err
+	}
+	fields := make([]helpers.FieldProblem, 0, len(violations))
+	for _, violation := range violations {
+		fields = append(fields, helpers.FieldProblem{Field: fieldPath(violation), Detail: violationDetail(violation)})
+	}
+	return validationError{fields: fields}
+}
+
+//fieldPath drops the struct name leading the namespace, "News.title" becomes "title"
+func fieldPath(violation validator.FieldError) string {
+	namespace := violation.Namespace()
+	if i := strings.Index(namespace, "."); i >= 0 {
+		return namespace[i+1:]
+	}
+	return namespace
+}
+
+func violationDetail(violation validator.FieldError) string {
+	switch violation.Tag() {
+	case "required":
+		return "is required"
+	case "max":
+		return fmt.Sprintf("must be at most %s characters long", violation.Param())
+	case "url":
+		return "must be an absolute url"
+	case "oneof":
+		return fmt.Sprintf("must be one of %s", strings.Join(strings.Fields(violation.Param()), ", "))
+	}
+	return fmt.Sprintf("failed the %s rule", violation.Tag())
+}
@@ -0,0 +1,90 @@
+package controllers
+
+import (
+	"net/http/httptest"
+	"news-topic-api/helpers"
+	"news-topic-api/models"
+	"news-topic-api/policies"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	mockServices "news-topic-api/mocks/services"
+)
+
+func TestCreateNewsEmptyTitleShouldReturnBadRequest(t *testing.T) {
+	mockedRequestData := getMockReqNews()
+	mockedRequestData["title"] = ""
+	mockedNewsService := new(mockServices.INewsService)
+	newsController := InitNewsController(mockedNewsService, policies.InitNewsPolicy(mockedNewsService))
+	request := createJSONRequestNews("POST", "/news/", mockedRequestData)
+	response := httptest.NewRecorder()
+	getNewsRouter(newsController, "Create").ServeHTTP(response, request)
+	assert.Equal(t, 400, response.Code, "response code should be 400")
+	assert.Equal(t, []helpers.FieldProblem{{Field: "title", Detail: "is required"}}, decodeProblem(t, response).Errors)
+	mockedNewsService.AssertNotCalled(t, "Create", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestCreateNewsInvalidFieldsShouldReportAllViolations(t *testing.T) {
+	mockedRequestData := getMockReqNews()
+	mockedRequestData["title"] = strings.Repeat("a", 256)
+	mockedRequestData["thumbnail"] = "google.com/thumbnail.png"
+	mockedRequestData["status"] = "archived"
+	delete(mockedRequestData, "content")
+	mockedNewsService := new(mockServices.INewsService)
+	newsController := InitNewsController(mockedNewsService, policies.InitNewsPolicy(mockedNewsService))
+	request := createJSONRequestNews("POST", "/news/", mockedRequestData)
+	response := httptest.NewRecorder()
+	getNewsRouter(newsController, "Create").ServeHTTP(response, request)
+	problem := decodeProblem(t, response)
+	assert.Equal(t, 400, problem.Status)
+	assert.Equal(t, "4 fields are invalid", problem.Detail)
+	assert.Equal(t, []helpers.FieldProblem{
+		{Field: "title", Detail: "must be at most 255 characters long"},
+		{Field: "thumbnail", Detail: "must be an absolute url"},
+		{Field: "content", Detail: "is required"},
+		{Field: "status", Detail: "must be one of draft, published"},
+	}, problem.Errors)
+}
+
+func TestUpdateTagEmptyNameShouldReturnBadRequest(t *testing.T) {
+	mockedRequestData := getMockReqTag()
+	mockedRequestData["name"] = ""
+	mockedTagService := new(mockServices.ITagService)
+	tagController := InitTagController(mockedTagService, policies.InitTagPolicy())
+	request := createJSONRequestTag("PUT", "/tag/1", mockedRequestData)
+	response := httptest.NewRecorder()
+	getTagRouter(tagController, "Update").ServeHTTP(response, request)
+	assert.Equal(t, 400, response.Code, "response code should be 400")
+	assert.Equal(t, []helpers.FieldProblem{{Field: "name", Detail: "is required"}}, decodeProblem(t, response).Errors)
+	mockedTagService.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestCreateNewsUnknownFieldShouldReturnBadRequest(t *testing.T) {
+	mockedRequestData := getMockReqNews()
+	mockedRequestData["ID"] = 99
+	mockedNewsService := new(mockServices.INewsService)
+	newsController := InitNewsController(mockedNewsService, policies.InitNewsPolicy(mockedNewsService))
+	request := createJSONRequestNews("POST", "/news/", mockedRequestData)
+	response := httptest.NewRecorder()
+	getNewsRouter(newsController, "Create").ServeHTTP(response, request)
+	assert.Equal(t, 400, response.Code, "response code should be 400")
+	assert.Equal(t, []helpers.FieldProblem{{Field: "ID", Detail: "is not accepted"}}, decodeProblem(t, response).Errors)
+	mockedNewsService.AssertNotCalled(t, "Create", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestCreateTagShouldAnswerSnakeCaseFields(t *testing.T) {
+	mockedTagService := new(mockServices.ITagService)
+	createdTag := models.Tag{Name: "cryptocurrency"}
+	createdTag.ID = 3
+	mockedTagService.On("Create", mock.Anything, models.Tag{Name: "cryptocurrency"}, mock.Anything).Return(createdTag, nil)
+	tagController := InitTagController(mockedTagService, policies.InitTagPolicy())
+	request := createJSONRequestTag("POST", "/tag/", getMockReqTag())
+	response := httptest.NewRecorder()
+	getTagRouter(tagController, "Create").ServeHTTP(response, request)
+	assert.Equal(t, 201, response.Code, "response code should be 201")
+	assert.Contains(t, response.Body.String(), `"id":3`)
+	assert.Contains(t, response.Body.String(), `"created_at"`)
+	assert.NotContains(t, response.Body.String(), "DeletedAt")
+}
@@ -0,0 +1,16 @@
+package docs
+
+import (
+	//embed the api description in the binary
+	_ "embed"
+)
+
+//OpenAPI is the OpenAPI 3 document describing every route registered by routes.Init
+//
+//go:embed openapi.json
+var OpenAPI []byte
+
+//Page renders OpenAPI with a pinned Swagger UI release, loaded from a CDN by the browser
+//
+//go:embed index.html
+var Page []byte
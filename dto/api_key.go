@@ -0,0 +1,74 @@
+package dto
+
+import (
+	"news-topic-api/models"
+	"time"
+)
+
+//APIKeyRequest is the body accepted to mint an api key, the owner is the caller
+type APIKeyRequest struct {
+	Name      string     `json:"name" validate:"required,max=100"`
+	Scopes    []string   `json:"scopes" validate:"required"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+//APIKeyResponse is an api key as answered to callers, without its hash
+type APIKeyResponse struct {
+	ID         uint       `json:"id"`
+	Name       string     `json:"name"`
+	Owner      string     `json:"owner"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	RevokedAt  *time.Time `json:"revoked_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+//APIKeysListResponse ...
+type APIKeysListResponse struct {
+	Data []APIKeyResponse `json:"data"`
+}
+
+//APIKeySecretResponse carries the plaintext key, answered once on mint and rotate
+type APIKeySecretResponse struct {
+	APIKey APIKeyResponse `json:"api_key"`
+	Key    string         `json:"key"`
+}
+
+//ToModel returns the api key described by the request
+func (a APIKeyRequest) ToModel() models.APIKey {
+	return models.APIKey{Name: a.Name, Scopes: a.Scopes, ExpiresAt: a.ExpiresAt}
+}
+
+//NewAPIKeyResponse maps a stored api key to its response
+func NewAPIKeyResponse(apiKey models.APIKey) APIKeyResponse {
+	scopes := append([]string{}, apiKey.Scopes...)
+	return APIKeyResponse{
+		ID:         apiKey.ID,
+		Name:       apiKey.Name,
+		Owner:      apiKey.Owner,
+		Prefix:     apiKey.Prefix,
+		Scopes:     scopes,
+		ExpiresAt:  apiKey.ExpiresAt,
+		LastUsedAt: apiKey.LastUsedAt,
+		RevokedAt:  apiKey.RevokedAt,
+		CreatedAt:  apiKey.CreatedAt,
+		UpdatedAt:  apiKey.UpdatedAt,
+	}
+}
+
+//NewAPIKeysListResponse ...
+func NewAPIKeysListResponse(list models.APIKeysList) APIKeysListResponse {
+	data := make([]APIKeyResponse, 0, len(list.Data))
+	for _, apiKey := range list.Data {
+		data = append(data, NewAPIKeyResponse(apiKey))
+	}
+	return APIKeysListResponse{Data: data}
+}
+
+//NewAPIKeySecretResponse ...
+func NewAPIKeySecretResponse(secret models.APIKeySecret) APIKeySecretResponse {
+	return APIKeySecretResponse{APIKey: NewAPIKeyResponse(secret.APIKey), Key: secret.Key}
+}
@@ -0,0 +1,51 @@
+package dto
+
+import (
+	"news-topic-api/models"
+	"time"
+)
+
+//AuditLogResponse is an audit entry as answered to callers, before and after hold the entity
+//in the shape of its own response
+type AuditLogResponse struct {
+	ID         uint        `json:"id"`
+	Timestamp  time.Time   `json:"timestamp"`
+	Actor      string      `json:"actor"`
+	Action     string      `json:"action"`
+	EntityType string      `json:"entity_type"`
+	EntityID   uint        `json:"entity_id"`
+	Before     models.JSON `json:"before"`
+	After      models.JSON `json:"after"`
+	RequestID  string      `json:"request_id"`
+	IP         string      `json:"ip"`
+}
+
+//AuditLogsListResponse ...
+type AuditLogsListResponse struct {
+	Data []AuditLogResponse `json:"data"`
+}
+
+//NewAuditLogResponse maps a stored audit entry to its response
+func NewAuditLogResponse(auditLog models.AuditLog) AuditLogResponse {
+	return AuditLogResponse{
+		ID:         auditLog.ID,
+		Timestamp:  auditLog.Timestamp,
+		Actor:      auditLog.Actor,
+		Action:     auditLog.Action,
+		EntityType: auditLog.EntityType,
+		EntityID:   auditLog.EntityID,
+		Before:     auditLog.Before,
+		After:      auditLog.After,
+		RequestID:  auditLog.RequestID,
+		IP:         auditLog.IP,
+	}
+}
+
+//NewAuditLogsListResponse maps stored audit entries to their responses, nil becomes an empty list
+func NewAuditLogsListResponse(list models.AuditLogsList) AuditLogsListResponse {
+	responses := make([]AuditLogResponse, 0, len(list.Data))
+	for _, auditLog := range list.Data {
+		responses = append(responses, NewAuditLogResponse(auditLog))
+	}
+	return AuditLogsListResponse{Data: responses}
+}
@@ -0,0 +1,70 @@
+package dto
+
+import (
+	"news-topic-api/models"
+	"time"
+)
+
+//AuthorRequest is the body accepted to create or update an author
+type AuthorRequest struct {
+	Name   string `json:"name" validate:"required,max=255"`
+	Bio    string `json:"bio" validate:"max=1000"`
+	Avatar string `json:"avatar" validate:"max=2048"`
+}
+
+//AuthorResponse is an author as answered to callers
+type AuthorResponse struct {
+	ID        uint      `json:"id"`
+	Name      string    `json:"name"`
+	Bio       string    `json:"bio"`
+	Avatar    string    `json:"avatar"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+//AuthorsListResponse ...
+type AuthorsListResponse struct {
+	Data []AuthorResponse `json:"data"`
+}
+
+//AuthorSummary is an author as listed in the news it wrote
+type AuthorSummary struct {
+	ID     uint   `json:"id"`
+	Name   string `json:"name"`
+	Avatar string `json:"avatar"`
+}
+
+//ToModel returns the author described by the request
+func (a AuthorRequest) ToModel() models.Author {
+	return models.Author{Name: a.Name, Bio: a.Bio, Avatar: a.Avatar}
+}
+
+//NewAuthorResponse maps a stored author to its response
+func NewAuthorResponse(author models.Author) AuthorResponse {
+	return AuthorResponse{
+		ID:        author.ID,
+		Name:      author.Name,
+		Bio:       author.Bio,
+		Avatar:    author.Avatar,
+		CreatedAt: author.CreatedAt,
+		UpdatedAt: author.UpdatedAt,
+	}
+}
+
+//NewAuthorsListResponse ...
+func NewAuthorsListResponse(list models.AuthorsList) AuthorsListResponse {
+	data := make([]AuthorResponse, 0, len(list.Data))
+	for _, author := range list.Data {
+		data = append(data, NewAuthorResponse(author))
+	}
+	return AuthorsListResponse{Data: data}
+}
+
+//NewAuthorSummaries maps stored authors to their summaries, nil becomes an empty list
+func NewAuthorSummaries(authors []models.Author) []AuthorSummary {
+	summaries := make([]AuthorSummary, 0, len(authors))
+	for _, author := range authors {
+		summaries = append(summaries, AuthorSummary{ID: author.ID, Name: author.Name, Avatar: author.Avatar})
+	}
+	return summaries
+}
@@ -0,0 +1,89 @@
+package dto
+
+import (
+	"news-topic-api/models"
+	"time"
+)
+
+//NewsRequest is the body accepted to create or update news, tags and authors are referenced by id
+type NewsRequest struct {
+	Title     string `json:"title" validate:"required,max=255"`
+	Thumbnail string `json:"thumbnail" validate:"required,url,max=2048"`
+	Summary   string `json:"summary" validate:"required,max=1000"`
+	Content   string `json:"content" validate:"required"`
+	Topic     string `json:"topic" validate:"required,max=100"`
+	Status    string `json:"status" validate:"required,oneof=draft published"`
+	TagIDs    []uint `json:"tag_ids"`
+	AuthorIDs []uint `json:"author_ids"`
+}
+
+//NewsResponse is news as answered to callers
+type NewsResponse struct {
+	ID        uint            `json:"id"`
+	Title     string          `json:"title"`
+	Thumbnail string          `json:"thumbnail"`
+	Summary   string          `json:"summary"`
+	Content   string          `json:"content"`
+	Topic     string          `json:"topic"`
+	Status    string          `json:"status"`
+	Tags      []TagResponse   `json:"tags"`
+	Authors   []AuthorSummary `json:"authors"`
+	CreatedBy string          `json:"created_by"`
+	CreatedAt time.Time       `json:"created_at"`
+	UpdatedAt time.Time       `json:"updated_at"`
+}
+
+//NewsListResponse ...
+type NewsListResponse struct {
+	Data []NewsResponse `json:"data"`
+}
+
+//ToModel returns the news described by the request, its tags and authors only carry their id
+func (n NewsRequest) ToModel() models.News {
+	news := models.News{
+		Title:     n.Title,
+		Thumbnail: n.Thumbnail,
+		Summary:   n.Summary,
+		Content:   n.Content,
+		Topic:     n.Topic,
+		Status:    n.Status,
+	}
+	for _, tagID := range n.TagIDs {
+		tag := models.Tag{}
+		tag.ID = tagID
+		news.Tags = append(news.Tags, tag)
+	}
+	for _, authorID := range n.AuthorIDs {
+		author := models.Author{}
+		author.ID = authorID
+		news.Authors = append(news.Authors, author)
+	}
+	return news
+}
+
+//NewNewsResponse maps stored news to its response
+func NewNewsResponse(news models.News) NewsResponse {
+	return NewsResponse{
+		ID:        news.ID,
+		Title:     news.Title,
+		Thumbnail: news.Thumbnail,
+		Summary:   news.Summary,
+		Content:   news.Content,
+		Topic:     news.Topic,
+		Status:    news.Status,
+		Tags:      NewTagResponses(news.Tags),
+		Authors:   NewAuthorSummaries(news.Authors),
+		CreatedBy: news.CreatedBy,
+		CreatedAt: news.CreatedAt,
+		UpdatedAt: news.UpdatedAt,
+	}
+}
+
+//NewNewsListResponse ...
+func NewNewsListResponse(list models.NewsList) NewsListResponse {
+	data := make([]NewsResponse, 0, len(list.Data))
+	for _, news := range list.Data {
+		data = append(data, NewNewsResponse(news))
+	}
+	return NewsListResponse{Data: data}
+}
@@ -0,0 +1,43 @@
+package dto
+
+import (
+	"encoding/json"
+	"news-topic-api/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewsRequestToModelReferencesTagsAndAuthorsByID(t *testing.T) {
+	request := NewsRequest{Title: "Harga bitcoin anjlok", Status: models.StatusDraft, TagIDs: []uint{1, 2}, AuthorIDs: []uint{3}}
+	news := request.ToModel()
+	assert.Equal(t, "Harga bitcoin anjlok", news.Title)
+	assert.Equal(t, uint(0), news.ID)
+	assert.Len(t, news.Tags, 2)
+	assert.Equal(t, uint(2), news.Tags[1].ID)
+	assert.Len(t, news.Authors, 1)
+	assert.Equal(t, uint(3), news.Authors[0].ID)
+}
+
+func TestNewNewsResponseUsesSnakeCaseFields(t *testing.T) {
+	news := models.News{Title: "Harga bitcoin anjlok", CreatedBy: "42"}
+	news.ID = 7
+	news.CreatedAt = time.Date(2021, 5, 1, 10, 0, 0, 0, time.UTC)
+	encoded, _ := json.Marshal(NewNewsResponse(news))
+	var fields map[string]interface{}
+	assert.Nil(t, json.Unmarshal(encoded, &fields))
+	assert.Equal(t, float64(7), fields["id"])
+	assert.Equal(t, "2021-05-01T10:00:00Z", fields["created_at"])
+	assert.Equal(t, []interface{}{}, fields["tags"], "missing tags should be an empty list")
+	for _, gormField := range []string{"ID", "CreatedAt", "UpdatedAt", "DeletedAt", "deleted_at"} {
+		assert.NotContains(t, fields, gormField)
+	}
+}
+
+func TestNewNewsListResponseKeepsOrder(t *testing.T) {
+	first, second := models.News{Title: "first"}, models.News{Title: "second"}
+	response := NewNewsListResponse(models.NewsList{Data: []models.News{first, second}})
+	assert.Equal(t, []string{"first", "second"}, []string{response.Data[0].Title, response.Data[1].Title})
+	assert.Equal(t, []NewsResponse{}, NewNewsListResponse(models.NewsList{}).Data)
+}
@@ -0,0 +1,53 @@
+package dto
+
+import (
+	"news-topic-api/models"
+	"time"
+)
+
+//TagRequest is the body accepted to create or update a tag
+type TagRequest struct {
+	Name string `json:"name" validate:"required,max=100"`
+}
+
+//TagResponse is a tag as answered to callers
+type TagResponse struct {
+	ID        uint      `json:"id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+//TagsListResponse ...
+type TagsListResponse struct {
+	Data []TagResponse `json:"data"`
+}
+
+//ToModel returns the tag described by the request
+func (t TagRequest) ToModel() models.Tag {
+	return models.Tag{Name: t.Name}
+}
+
+//NewTagResponse maps a stored tag to its response
+func NewTagResponse(tag models.Tag) TagResponse {
+	return TagResponse{
+		ID:        tag.ID,
+		Name:      tag.Name,
+		CreatedAt: tag.CreatedAt,
+		UpdatedAt: tag.UpdatedAt,
+	}
+}
+
+//NewTagResponses maps stored tags to their responses, nil becomes an empty list
+func NewTagResponses(tags []models.Tag) []TagResponse {
+	responses := make([]TagResponse, 0, len(tags))
+	for _, tag := range tags {
+		responses = append(responses, NewTagResponse(tag))
+	}
+	return responses
+}
+
+//NewTagsListResponse ...
+func NewTagsListResponse(list models.TagsList) TagsListResponse {
+	return TagsListResponse{Data: NewTagResponses(list.Data)}
+}
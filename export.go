@@ -0,0 +1,63 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"news-topic-api/infrastructures"
+	"news-topic-api/models"
+	"news-topic-api/repositories"
+	"news-topic-api/services"
+	"os"
+)
+
+const exportUsage = `Usage: news-topic-api export [flags]
+
+Writes the news matching the filters as json, newest first.
+`
+
+//runExport writes the news matching the filters to stdout or -out
+func runExport(args []string) int {
+	flags := newFlagSet("export", exportUsage)
+	status := flags.String("status", "", "only export news with this status")
+	topic := flags.String("topic", "", "only export news of this topic")
+	tag := flags.String("tag", "", "only export news with this tag id")
+	out := flags.String("out", "", "file to write, stdout when empty")
+	if exitCode, ok := parseFlags(flags, args); !ok {
+		return exitCode
+	}
+	_, db, err := openDatabase()
+	if err != nil {
+		return fail(err)
+	}
+	defer infrastructures.CloseDB(db)
+	newsService := services.InitNewsService(repositories.InitNewsRepository(db), repositories.InitTransactionManager(db))
+	newsList, err := newsService.List(context.Background(), map[string]string{"status": *status, "topic": *topic, "tag": *tag})
+	if err != nil {
+		return fail(err)
+	}
+	if *out == "" {
+		if err := writeExport(os.Stdout, newsList); err != nil {
+			return fail(err)
+		}
+		return exitOK
+	}
+	file, err := os.Create(*out)
+	if err != nil {
+		return fail(err)
+	}
+	err = writeExport(file, newsList)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fail(err)
+	}
+	return exitOK
+}
+
+func writeExport(w io.Writer, newsList models.NewsList) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(newsList)
+}
@@ -0,0 +1,135 @@
+package health
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+//Lifecycle states of the process as seen by the readiness probe
+const (
+	StateStarting = "starting"
+	StateReady    = "ready"
+	StateDraining = "draining"
+)
+
+//Check statuses reported by the readiness probe
+const (
+	StatusOK   = "ok"
+	StatusFail = "fail"
+)
+
+//CheckFunc verifies one dependency, it must give up once ctx is done
+type CheckFunc func(ctx context.Context) error
+
+type check struct {
+	name string
+	run  CheckFunc
+}
+
+//CheckResult is the outcome of one readiness check, only its status is answered to callers
+//since the error may name hosts, drivers or schemas
+type CheckResult struct {
+	Status    string  `json:"status"`
+	LatencyMS float64 `json:"-"`
+	Error     string  `json:"-"`
+}
+
+//Report is the outcome of a readiness probe
+type Report struct {
+	Status string                 `json:"status"`
+	State  string                 `json:"state"`
+	Checks map[string]CheckResult `json:"checks"`
+}
+
+//Ready reports whether the process is ready and every check passed
+func (r Report) Ready() bool {
+	return r.Status == StatusOK
+}
+
+//Probe tracks the lifecycle state of the process and the checks deciding its readiness
+type Probe struct {
+	state   atomic.Value
+	timeout time.Duration
+	checks  []check
+	now     func() time.Time
+}
+
+//InitProbe initializes a probe in the starting state, each check is given timeout to complete
+func InitProbe(timeout time.Duration) *Probe {
+	probe := new(Probe)
+	probe.state.Store(StateStarting)
+	probe.timeout = timeout
+	probe.now = time.Now
+	return probe
+}
+
+//AddCheck registers a readiness check, checks must be added before the probe is served
+func (p *Probe) AddCheck(name string, run CheckFunc) {
+	p.checks = append(p.checks, check{name: name, run: run})
+}
+
+//MarkReady flips the probe to ready once the process finished starting
+func (p *Probe) MarkReady() {
+	p.state.Store(StateReady)
+}
+
+//MarkDraining flips the probe to failing so traffic is routed away before shutdown
+func (p *Probe) MarkDraining() {
+	p.state.Store(StateDraining)
+}
+
+//State returns the current lifecycle state
+func (p *Probe) State() string {
+	return p.state.Load().(string)
+}
+
+//Check runs every check concurrently within the probe timeout
+func (p *Probe) Check(ctx context.Context) Report {
+	ctx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+	report := Report{Status: StatusOK, State: p.State(), Checks: map[string]CheckResult{}}
+	var mutex sync.Mutex
+	var wait sync.WaitGroup
+	for _, current := range p.checks {
+		wait.Add(1)
+		go func(current check) {
+			defer wait.Done()
+			result := p.run(ctx, current)
+			mutex.Lock()
+			defer mutex.Unlock()
+			report.Checks[current.name] = result
+		}(current)
+	}
+	wait.Wait()
+	if report.State != StateReady {
+		report.Status = StatusFail
+	}
+	for _, result := range report.Checks {
+		if result.Status != StatusOK {
+			report.Status = StatusFail
+		}
+	}
+	return report
+}
+
+func (p *Probe) run(ctx context.Context, current check) CheckResult {
+	start := p.now()
+	done := make(chan error, 1)
+	go func() {
+		done <- current.run(ctx)
+	}()
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+	result := CheckResult{Status: StatusOK, LatencyMS: float64(p.now().Sub(start).Microseconds()) / 1000}
+	if err != nil {
+		result.Status = StatusFail
+		result.Error = err.Error()
+	}
+	return result
+}
@@ -0,0 +1,71 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func passingCheck(ctx context.Context) error {
+	return nil
+}
+
+func TestCheckWhileStartingShouldFail(t *testing.T) {
+	probe := InitProbe(time.Second)
+	probe.AddCheck("database", passingCheck)
+	report := probe.Check(context.Background())
+	assert.False(t, report.Ready(), "a starting probe should not be ready")
+	assert.Equal(t, StateStarting, report.State)
+	assert.Equal(t, StatusOK, report.Checks["database"].Status)
+}
+
+func TestCheckReadyWithPassingChecksShouldSucceed(t *testing.T) {
+	probe := InitProbe(time.Second)
+	probe.AddCheck("database", passingCheck)
+	probe.AddCheck("migrations", passingCheck)
+	probe.MarkReady()
+	report := probe.Check(context.Background())
+	assert.True(t, report.Ready(), "probe should be ready")
+	assert.Len(t, report.Checks, 2)
+}
+
+func TestCheckWithFailingCheckShouldReportError(t *testing.T) {
+	probe := InitProbe(time.Second)
+	probe.AddCheck("database", passingCheck)
+	probe.AddCheck("migrations", func(ctx context.Context) error {
+		return fmt.Errorf("table news is missing")
+	})
+	probe.MarkReady()
+	report := probe.Check(context.Background())
+	assert.False(t, report.Ready(), "probe should not be ready")
+	assert.Equal(t, StatusFail, report.Checks["migrations"].Status)
+	assert.Equal(t, "table news is missing", report.Checks["migrations"].Error)
+	assert.Equal(t, StatusOK, report.Checks["database"].Status)
+}
+
+func TestCheckHangingCheckShouldTimeOut(t *testing.T) {
+	probe := InitProbe(10 * time.Millisecond)
+	release := make(chan struct{})
+	defer close(release)
+	probe.AddCheck("database", func(ctx context.Context) error {
+		<-release
+		return nil
+	})
+	probe.MarkReady()
+	report := probe.Check(context.Background())
+	assert.False(t, report.Ready(), "probe should not be ready")
+	assert.Equal(t, context.DeadlineExceeded.Error(), report.Checks["database"].Error)
+}
+
+func TestCheckWhileDrainingShouldFail(t *testing.T) {
+	probe := InitProbe(time.Second)
+	probe.AddCheck("database", passingCheck)
+	probe.MarkReady()
+	probe.MarkDraining()
+	report := probe.Check(context.Background())
+	assert.False(t, report.Ready(), "a draining probe should not be ready")
+	assert.Equal(t, StateDraining, report.State)
+}
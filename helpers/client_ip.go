@@ -0,0 +1,41 @@
+package helpers
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strings"
+)
+
+const clientIPKey contextKey = "client_ip"
+
+//ClientIP resolves the address of the caller. The X-Forwarded-For header is only
+//honored behind a trusted proxy, using the entry appended by the proxy itself.
+func ClientIP(req *http.Request, trustProxy bool) string {
+	if trustProxy {
+		forwardedFor := req.Header.Get("X-Forwarded-For")
+		if forwardedFor != "" {
+			hops := strings.Split(forwardedFor, ",")
+			return strings.TrimSpace(hops[len(hops)-1])
+		}
+	}
+	host, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		return req.RemoteAddr
+	}
+	return host
+}
+
+//GetClientIP returns the caller address resolved for the request,
+//falling back to the connection address when it was not resolved yet
+func GetClientIP(req *http.Request) string {
+	if clientIP, ok := req.Context().Value(clientIPKey).(string); ok {
+		return clientIP
+	}
+	return ClientIP(req, false)
+}
+
+//WithClientIP returns a copy of req carrying the resolved caller address
+func WithClientIP(req *http.Request, clientIP string) *http.Request {
+	return req.WithContext(context.WithValue(req.Context(), clientIPKey, clientIP))
+}
@@ -0,0 +1,49 @@
+package helpers
+
+import (
+	"context"
+	"news-topic-api/models"
+)
+
+type contextKey string
+
+const principalKey contextKey = "principal"
+
+//WithPrincipal returns a copy of ctx carrying the authenticated principal
+func WithPrincipal(ctx context.Context, principal models.Principal) context.Context {
+	return context.WithValue(ctx, principalKey, principal)
+}
+
+//GetPrincipal returns the principal stored in ctx, if any
+func GetPrincipal(ctx context.Context) (models.Principal, bool) {
+	principal, ok := ctx.Value(principalKey).(models.Principal)
+	return principal, ok
+}
+
+const requestIDKey contextKey = "request_id"
+
+const loggerKey contextKey = "logger"
+
+//WithRequestID returns a copy of ctx carrying the id of the request being served
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey, requestID)
+}
+
+//GetRequestID returns the request id stored in ctx, or an empty string outside of a request
+func GetRequestID(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey).(string)
+	return requestID
+}
+
+//WithLogger returns a copy of ctx carrying a logger bound to the request
+func WithLogger(ctx context.Context, logger Logger) context.Context {
+	return context.WithValue(ctx, loggerKey, logger)
+}
+
+//GetLogger returns the logger stored in ctx, falling back to the default logger
+func GetLogger(ctx context.Context) Logger {
+	if logger, ok := ctx.Value(loggerKey).(Logger); ok {
+		return logger
+	}
+	return DefaultLogger()
+}
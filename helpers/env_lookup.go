@@ -1,12 +0,0 @@
-package helpers
-
-import "os"
-
-// GetEnv ...
-func GetEnv(key, fallback string) string {
-	value, exists := os.LookupEnv(key)
-	if !exists {
-		value = fallback
-	}
-	return value
-}
@@ -0,0 +1,87 @@
+package helpers
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+//LogFields are the structured fields attached to a log line
+type LogFields map[string]interface{}
+
+//Logger writes one JSON object per line, carrying the fields it was derived with
+type Logger struct {
+	out    io.Writer
+	mutex  *sync.Mutex
+	fields LogFields
+	now    func() time.Time
+}
+
+var defaultLogger = NewLogger(os.Stdout)
+
+//NewLogger initializes a logger writing to out
+func NewLogger(out io.Writer) Logger {
+	return Logger{out: out, mutex: &sync.Mutex{}, fields: LogFields{}, now: time.Now}
+}
+
+//DefaultLogger returns the process wide logger writing to stdout
+func DefaultLogger() Logger {
+	return defaultLogger
+}
+
+//With returns a copy of the logger that adds fields to every line
+func (l Logger) With(fields LogFields) Logger {
+	merged := LogFields{}
+	for key, value := range l.fields {
+		merged[key] = value
+	}
+	for key, value := range fields {
+		merged[key] = value
+	}
+	l.fields = merged
+	return l
+}
+
+//Info logs a routine event
+func (l Logger) Info(msg string, fields LogFields) {
+	l.write("info", msg, fields)
+}
+
+//Warn logs an event worth looking at, such as a rejected request
+func (l Logger) Warn(msg string, fields LogFields) {
+	l.write("warn", msg, fields)
+}
+
+//Error logs a failure together with its cause
+func (l Logger) Error(msg string, err error, fields LogFields) {
+	line := LogFields{}
+	for key, value := range fields {
+		line[key] = value
+	}
+	if err != nil {
+		line["error"] = err.Error()
+	}
+	l.write("error", msg, line)
+}
+
+func (l Logger) write(level string, msg string, fields LogFields) {
+	line := LogFields{}
+	for key, value := range l.fields {
+		line[key] = value
+	}
+	for key, value := range fields {
+		line[key] = value
+	}
+	line["time"] = l.now().UTC().Format(time.RFC3339Nano)
+	line["level"] = level
+	line["msg"] = msg
+	encoded, err := json.Marshal(line)
+	if err != nil {
+		encoded, _ = json.Marshal(LogFields{"time": line["time"], "level": "error", "msg": "unable to encode log line", "error": err.Error()})
+	}
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.out.Write(append(encoded, '\n'))
+}
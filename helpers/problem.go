@@ -0,0 +1,54 @@
+package helpers
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+//ProblemContentType is the media type of error bodies, as defined by RFC 7807
+const ProblemContentType = "application/problem+json"
+
+//ProblemTypeDefault is the problem type of errors that are fully described by their status
+const ProblemTypeDefault = "about:blank"
+
+//Problem is the RFC 7807 body answering a failed request
+type Problem struct {
+	Type      string         `json:"type"`
+	Title     string         `json:"title"`
+	Status    int            `json:"status"`
+	Detail    string         `json:"detail,omitempty"`
+	Instance  string         `json:"instance,omitempty"`
+	RequestID string         `json:"request_id,omitempty"`
+	Errors    []FieldProblem `json:"errors,omitempty"`
+}
+
+//FieldProblem points at one invalid input, field is the json name of a body field or the name of a path
+//or query parameter
+type FieldProblem struct {
+	Field  string `json:"field"`
+	Detail string `json:"detail"`
+}
+
+//NewProblem returns the problem answering req with status, detail describes this occurrence of the problem
+func NewProblem(req *http.Request, httpStatus int, detail string) Problem {
+	return Problem{
+		Type:      ProblemTypeDefault,
+		Title:     http.StatusText(httpStatus),
+		Status:    httpStatus,
+		Detail:    detail,
+		Instance:  req.URL.Path,
+		RequestID: GetRequestID(req.Context()),
+	}
+}
+
+//ResponseProblem writes the problem as an application/problem+json body
+func ResponseProblem(w http.ResponseWriter, problem Problem) {
+	w.Header().Set("Content-Type", ProblemContentType)
+	w.WriteHeader(problem.Status)
+	json.NewEncoder(w).Encode(problem)
+}
+
+//ResponseError answers req with a problem whose detail is the message of err
+func ResponseError(w http.ResponseWriter, req *http.Request, httpStatus int, err error) {
+	ResponseProblem(w, NewProblem(req, httpStatus, err.Error()))
+}
@@ -1,23 +1,21 @@
 package helpers
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
-
 )
 
+//StatusClientClosedRequest is the non standard status recorded when the caller went away before being answered
+const StatusClientClosedRequest = 499
+
 // APIResponse ...
 type APIResponse struct {
 	Status int         `json:"status"`
 	Data   interface{} `json:"data"`
 }
 
-// APIResponseError ...
-type APIResponseError struct {
-	Error  string `json:"error"`
-	Status int    `json:"status"`
-}
-
 // Response handler
 func Response(w http.ResponseWriter, httpStatus int, data interface{}) {
 	apiResponse := new(APIResponse)
@@ -29,13 +27,20 @@ func Response(w http.ResponseWriter, httpStatus int, data interface{}) {
 	json.NewEncoder(w).Encode(apiResponse)
 }
 
-// ResponseError handler
-func ResponseError(w http.ResponseWriter, httpStatus int, err error) {
-	apiResponse := new(APIResponseError)
-	apiResponse.Error = err.Error()
-	apiResponse.Status = httpStatus
-
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(httpStatus)
-	json.NewEncoder(w).Encode(apiResponse)
+//ContextErrorStatus returns the status answering a request whose context ended before err was returned,
+//504 when its timeout expired and 499 when its caller went away
+func ContextErrorStatus(ctx context.Context, err error) (int, bool) {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return http.StatusGatewayTimeout, true
+	}
+	if errors.Is(err, context.Canceled) {
+		return StatusClientClosedRequest, true
+	}
+	switch ctx.Err() {
+	case context.DeadlineExceeded:
+		return http.StatusGatewayTimeout, true
+	case context.Canceled:
+		return StatusClientClosedRequest, true
+	}
+	return 0, false
 }
@@ -1,49 +1,39 @@
 package infrastructures
 
 import (
-	"fmt"
-	"news-topic-api/helpers"
-	"news-topic-api/models"
+	"news-topic-api/config"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
 
-var db *gorm.DB
-
-//InitDB ...
-func InitDB() {
-	conn, err := dbSetup()
+//InitDB opens the connection pool described by the database configuration
+func InitDB(config config.DatabaseConfig) (*gorm.DB, error) {
+	conn, err := gorm.Open(postgres.New(postgres.Config{
+		DSN:                  config.DSN(), // data source name, refer https://github.com/jackc/pgx
+		PreferSimpleProtocol: true,         // disables implicit prepared statement usage. By default pgx automatically uses the extended protocol
+	}), &gorm.Config{Logger: newDBLogger()})
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
-	db = conn
-	doMigration()
-}
-
-//GetDB ...
-func GetDB() *gorm.DB {
-	return db
-}
-
-//SetDB ...
-func SetDB(newDB *gorm.DB) {
-	db = newDB
-}
-
-func doMigration() {
-	db.AutoMigrate(&models.News{})
-	db.AutoMigrate(&models.Tag{})
+	sqlDB, err := conn.DB()
+	if err != nil {
+		return nil, err
+	}
+	sqlDB.SetMaxOpenConns(config.MaxOpenConns)
+	sqlDB.SetMaxIdleConns(config.MaxIdleConns)
+	sqlDB.SetConnMaxLifetime(config.ConnMaxLifetime)
+	sqlDB.SetConnMaxIdleTime(config.ConnMaxIdleTime)
+	return conn, nil
 }
 
-func dbSetup() (*gorm.DB, error) {
-	username := helpers.GetEnv("postgres_username", "postgres")
-	password := helpers.GetEnv("postgres_password", "postgres")
-	dbName := helpers.GetEnv("postgres_dbname", "postgres")
-	host := helpers.GetEnv("postgres_host", "localhost")
-	dbParams := fmt.Sprintf("user=%s password=%s dbname=%s sslmode=disable host=%s", username, password, dbName, host)
-	conn, err := gorm.Open(postgres.New(postgres.Config{
-		DSN:                  helpers.GetEnv("DATABASE_URL", dbParams), // data source name, refer https://github.com/jackc/pgx
-		PreferSimpleProtocol: true,                                     // disables implicit prepared statement usage. By default pgx automatically uses the extended protocol
-	}), &gorm.Config{})
-	return conn, err
+//CloseDB closes the connection pool, waiting for queries in progress to finish
+func CloseDB(db *gorm.DB) error {
+	if db == nil {
+		return nil
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.Close()
 }
@@ -0,0 +1,43 @@
+package infrastructures
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+var errDBNotInitialized = errors.New("database is not initialized")
+
+//PingDB returns a check that db answers before ctx is done
+func PingDB(db *gorm.DB) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		if db == nil {
+			return errDBNotInitialized
+		}
+		sqlDB, err := db.DB()
+		if err != nil {
+			return err
+		}
+		return sqlDB.PingContext(ctx)
+	}
+}
+
+//CheckMigrations returns a check that every embedded migration is applied to db
+func CheckMigrations(db *gorm.DB) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		migrator, err := GetMigrator(db)
+		if err != nil {
+			return err
+		}
+		pending, err := migrator.Pending(ctx)
+		if err != nil {
+			return err
+		}
+		if len(pending) > 0 {
+			return fmt.Errorf("migrations are not current, %d pending starting with %d_%s", len(pending), pending[0].Version, pending[0].Name)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,79 @@
+package infrastructures
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"news-topic-api/helpers"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+//slowQueryThreshold is the duration above which a query is logged as slow
+const slowQueryThreshold = 200 * time.Millisecond
+
+//dbLogger routes gorm logs through the logger of the request context,
+//so queries run with db.WithContext(ctx) are logged with the request id
+type dbLogger struct {
+	level logger.LogLevel
+}
+
+func newDBLogger() logger.Interface {
+	return dbLogger{level: logger.Warn}
+}
+
+//LogMode ...
+func (d dbLogger) LogMode(level logger.LogLevel) logger.Interface {
+	d.level = level
+	return d
+}
+
+//Info ...
+func (d dbLogger) Info(ctx context.Context, msg string, data ...interface{}) {
+	if d.level >= logger.Info {
+		helpers.GetLogger(ctx).Info(fmt.Sprintf(msg, data...), nil)
+	}
+}
+
+//Warn ...
+func (d dbLogger) Warn(ctx context.Context, msg string, data ...interface{}) {
+	if d.level >= logger.Warn {
+		helpers.GetLogger(ctx).Warn(fmt.Sprintf(msg, data...), nil)
+	}
+}
+
+//Error ...
+func (d dbLogger) Error(ctx context.Context, msg string, data ...interface{}) {
+	if d.level >= logger.Error {
+		helpers.GetLogger(ctx).Error(fmt.Sprintf(msg, data...), nil, nil)
+	}
+}
+
+//Trace logs failed and slow queries, a missing record is an expected outcome and is not logged
+func (d dbLogger) Trace(ctx context.Context, begin time.Time, fc func() (string, int64), err error) {
+	if d.level <= logger.Silent {
+		return
+	}
+	elapsed := time.Since(begin)
+	switch {
+	case err != nil && d.level >= logger.Error && !errors.Is(err, gorm.ErrRecordNotFound):
+		sql, rows := fc()
+		helpers.GetLogger(ctx).Error("query failed", err, queryFields(sql, rows, elapsed))
+	case elapsed > slowQueryThreshold && d.level >= logger.Warn:
+		sql, rows := fc()
+		helpers.GetLogger(ctx).Warn("slow query", queryFields(sql, rows, elapsed))
+	case d.level >= logger.Info:
+		sql, rows := fc()
+		helpers.GetLogger(ctx).Info("query", queryFields(sql, rows, elapsed))
+	}
+}
+
+func queryFields(sql string, rows int64, elapsed time.Duration) helpers.LogFields {
+	return helpers.LogFields{
+		"sql":        sql,
+		"rows":       rows,
+		"latency_ms": float64(elapsed.Microseconds()) / 1000,
+	}
+}
@@ -0,0 +1,67 @@
+package infrastructures
+
+import (
+	"context"
+	"database/sql"
+	"news-topic-api/metrics"
+
+	"gorm.io/gorm"
+)
+
+//RegisterDBMetrics exposes the connection pool statistics of db in registry
+func RegisterDBMetrics(registry *metrics.Registry, db *gorm.DB) {
+	registry.RegisterGaugeFunc("db_connections", "Connections in the database pool by state.", []string{"state"}, func(ctx context.Context) ([]metrics.Sample, error) {
+		stats, err := dbStats(db)
+		if err != nil {
+			return nil, err
+		}
+		return []metrics.Sample{
+			{LabelValues: []string{"open"}, Value: float64(stats.OpenConnections)},
+			{LabelValues: []string{"in_use"}, Value: float64(stats.InUse)},
+			{LabelValues: []string{"idle"}, Value: float64(stats.Idle)},
+		}, nil
+	})
+	registry.RegisterGaugeFunc("db_max_open_connections", "Maximum number of open connections to the database, 0 is unlimited.", nil, func(ctx context.Context) ([]metrics.Sample, error) {
+		stats, err := dbStats(db)
+		if err != nil {
+			return nil, err
+		}
+		return []metrics.Sample{{Value: float64(stats.MaxOpenConnections)}}, nil
+	})
+	registry.RegisterCounterFunc("db_wait_count_total", "Number of connections waited for.", nil, func(ctx context.Context) ([]metrics.Sample, error) {
+		stats, err := dbStats(db)
+		if err != nil {
+			return nil, err
+		}
+		return []metrics.Sample{{Value: float64(stats.WaitCount)}}, nil
+	})
+	registry.RegisterCounterFunc("db_wait_duration_seconds_total", "Time spent waiting for a connection in seconds.", nil, func(ctx context.Context) ([]metrics.Sample, error) {
+		stats, err := dbStats(db)
+		if err != nil {
+			return nil, err
+		}
+		return []metrics.Sample{{Value: stats.WaitDuration.Seconds()}}, nil
+	})
+	registry.RegisterCounterFunc("db_closed_connections_total", "Connections closed by the pool by reason.", []string{"reason"}, func(ctx context.Context) ([]metrics.Sample, error) {
+		stats, err := dbStats(db)
+		if err != nil {
+			return nil, err
+		}
+		return []metrics.Sample{
+			{LabelValues: []string{"max_idle"}, Value: float64(stats.MaxIdleClosed)},
+			{LabelValues: []string{"max_idle_time"}, Value: float64(stats.MaxIdleTimeClosed)},
+			{LabelValues: []string{"max_lifetime"}, Value: float64(stats.MaxLifetimeClosed)},
+		}, nil
+	})
+}
+
+func dbStats(db *gorm.DB) (sql.DBStats, error) {
+	if db == nil {
+		return sql.DBStats{}, errDBNotInitialized
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		return sql.DBStats{}, err
+	}
+	return sqlDB.Stats(), nil
+}
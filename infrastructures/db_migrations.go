@@ -0,0 +1,23 @@
+package infrastructures
+
+import (
+	"news-topic-api/migrations"
+
+	"gorm.io/gorm"
+)
+
+//GetMigrator returns a migrator of the embedded migrations for db
+func GetMigrator(db *gorm.DB) (migrations.Migrator, error) {
+	if db == nil {
+		return migrations.Migrator{}, errDBNotInitialized
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		return migrations.Migrator{}, err
+	}
+	embedded, err := migrations.Embedded()
+	if err != nil {
+		return migrations.Migrator{}, err
+	}
+	return migrations.InitMigrator(sqlDB, embedded), nil
+}
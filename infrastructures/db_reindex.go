@@ -0,0 +1,37 @@
+package infrastructures
+
+import (
+	"context"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+//ReindexTables are the application tables rebuilt by Reindex, in dependency order
+var ReindexTables = []string{"tags", "authors", "news", "news_tag", "news_author", "api_keys", "audit_logs"}
+
+//Reindex rebuilds the indexes of the given tables, every application table when none is given
+func Reindex(ctx context.Context, db *gorm.DB, tables []string) error {
+	if db == nil {
+		return errDBNotInitialized
+	}
+	if len(tables) == 0 {
+		tables = ReindexTables
+	}
+	known := map[string]bool{}
+	for _, table := range ReindexTables {
+		known[table] = true
+	}
+	for _, table := range tables {
+		if !known[table] {
+			return fmt.Errorf("unknown table %q", table)
+		}
+	}
+	for _, table := range tables {
+		//table names are checked against ReindexTables, identifiers cannot be bound as parameters
+		if err := db.WithContext(ctx).Exec("REINDEX TABLE " + table).Error; err != nil {
+			return fmt.Errorf("reindexing %s: %w", table, err)
+		}
+	}
+	return nil
+}
@@ -2,46 +2,32 @@ package main
 
 import (
 	"fmt"
-	"github.com/gorilla/handlers"
 	"github.com/joho/godotenv"
-	"log"
-	"net/http"
-	"news-topic-api/infrastructures"
 	"os"
-	"os/signal"
-	"syscall"
-	"time"
-	"news-topic-api/routes"
-	"news-topic-api/helpers"
 )
 
 func main() {
 	godotenv.Load()
+	os.Exit(run(os.Args[1:]))
+}
 
-	var rt routes.Route
-
-	r := rt.Init()
-	infrastructures.InitDB()
-
-	var gracefulStop = make(chan os.Signal)
-	signal.Notify(gracefulStop, syscall.SIGTERM)
-	signal.Notify(gracefulStop, syscall.SIGINT)
-	go func() {
-		sig := <-gracefulStop
-		fmt.Printf("caught sig: %+v", sig)
-		fmt.Println("Wait for 2 second to finish processing")
-		time.Sleep(2 * time.Second)
-		os.Exit(0)
-	}()
-	headersOK := handlers.AllowedHeaders([]string{"X-Requested-With", "Content-Type"})
-	originsOK := handlers.AllowedOrigins([]string{"*"})
-	methodsOK := handlers.AllowedMethods([]string{"GET", "POST", "OPTIONS", "DELETE", "PUT"})
-
-	port := helpers.GetEnv("PORT", "8080")
-	fmt.Println("Server served at port " + port)
-
-	if err := http.ListenAndServe(":"+port, handlers.CORS(originsOK, headersOK, methodsOK)(r)); err != nil {
-		log.Fatal("Unable to start service: " + err.Error())
+//run dispatches args to their command and returns the exit code,
+//the server is started when no command is given so existing deployments keep working
+func run(args []string) int {
+	if len(args) == 0 {
+		return runServe(nil)
 	}
-
+	name := args[0]
+	if name == "-h" || name == "--help" || name == "help" {
+		printUsage()
+		return 0
+	}
+	for _, cmd := range commands() {
+		if cmd.name == name {
+			return cmd.run(args[1:])
+		}
+	}
+	fmt.Fprintf(os.Stderr, "unknown command %q\n\n", name)
+	printUsage()
+	return exitUsage
 }
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunHelpExitsZero(t *testing.T) {
+	for _, args := range [][]string{
+		{"--help"},
+		{"serve", "--help"},
+		{"migrate", "--help"},
+		{"seed", "--help"},
+		{"export", "--help"},
+		{"reindex", "--help"},
+		{"tag", "--help"},
+		{"tag", "merge", "--help"},
+	} {
+		assert.Equal(t, exitOK, run(args), "%v", args)
+	}
+}
+
+func TestRunUsageErrorsExitTwo(t *testing.T) {
+	for _, args := range [][]string{
+		{"unknown"},
+		{"migrate"},
+		{"migrate", "sideways"},
+		{"migrate", "create"},
+		{"migrate", "create", "add_x", "add_y"},
+		{"migrate", "create", "add_x", "-dir"},
+		{"export", "-nope"},
+		{"seed", "-topics", "0"},
+		{"seed", "-published", "2"},
+		{"seed", "-batch", "0"},
+		{"tag"},
+		{"tag", "split"},
+		{"tag", "merge", "2"},
+		{"tag", "merge", "-into", "1"},
+		{"tag", "merge", "-into", "1", "two"},
+	} {
+		assert.Equal(t, exitUsage, run(args), "%v", args)
+	}
+}
+
+func TestMigrateCreateShouldTakeFlagsAroundTheName(t *testing.T) {
+	before, after := t.TempDir(), t.TempDir()
+	assert.Equal(t, exitOK, run([]string{"migrate", "create", "-dir", before, "add_x"}))
+	assert.Equal(t, exitOK, run([]string{"migrate", "create", "add_y", "-dir", after}))
+	for dir, name := range map[string]string{before: "add_x", after: "add_y"} {
+		created, err := filepath.Glob(filepath.Join(dir, "*_"+name+".*.sql"))
+		assert.Nil(t, err)
+		assert.Len(t, created, 2, "%s should hold the up and down files of %s", dir, name)
+	}
+}
+
+func TestRunFailureExitsOne(t *testing.T) {
+	previous, found := os.LookupEnv("CONFIG_FILE")
+	os.Setenv("CONFIG_FILE", t.TempDir()+"/missing.properties")
+	defer func() {
+		if found {
+			os.Setenv("CONFIG_FILE", previous)
+		} else {
+			os.Unsetenv("CONFIG_FILE")
+		}
+	}()
+	assert.Equal(t, exitFailure, run([]string{"reindex"}))
+}
@@ -0,0 +1,286 @@
+package metrics
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"news-topic-api/helpers"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+//DefaultBuckets are the latency histogram upper bounds in seconds
+var DefaultBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+//Sample is one labeled value reported by a collect function
+type Sample struct {
+	LabelValues []string
+	Value       float64
+}
+
+//CollectFunc computes the samples of a metric at scrape time, ctx is done when the scrape is abandoned
+type CollectFunc func(ctx context.Context) ([]Sample, error)
+
+type family interface {
+	write(ctx context.Context, w io.Writer) error
+}
+
+//Registry holds the metrics exposed in the Prometheus text format
+type Registry struct {
+	mutex    sync.Mutex
+	names    map[string]bool
+	families []family
+}
+
+//InitRegistry initializes an empty metrics registry
+func InitRegistry() *Registry {
+	registry := new(Registry)
+	registry.names = map[string]bool{}
+	return registry
+}
+
+func (r *Registry) register(name string, metric family) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.names[name] {
+		panic(fmt.Sprintf("metric %s is already registered", name))
+	}
+	r.names[name] = true
+	r.families = append(r.families, metric)
+}
+
+//NewCounterVec registers a counter partitioned by the given labels
+func (r *Registry) NewCounterVec(name string, help string, labels []string) *CounterVec {
+	counter := &CounterVec{name: name, help: help, labels: labels, series: map[string]*counterSeries{}}
+	r.register(name, counter)
+	return counter
+}
+
+//NewHistogramVec registers a histogram with the given buckets partitioned by the given labels
+func (r *Registry) NewHistogramVec(name string, help string, labels []string, buckets []float64) *HistogramVec {
+	histogram := &HistogramVec{name: name, help: help, labels: labels, buckets: buckets, series: map[string]*histogramSeries{}}
+	r.register(name, histogram)
+	return histogram
+}
+
+//RegisterGaugeFunc registers a gauge whose samples are computed at scrape time
+func (r *Registry) RegisterGaugeFunc(name string, help string, labels []string, collect CollectFunc) {
+	r.register(name, &funcFamily{name: name, help: help, metricType: "gauge", labels: labels, collect: collect})
+}
+
+//RegisterCounterFunc registers a counter whose samples are read at scrape time from a cumulative source
+func (r *Registry) RegisterCounterFunc(name string, help string, labels []string, collect CollectFunc) {
+	r.register(name, &funcFamily{name: name, help: help, metricType: "counter", labels: labels, collect: collect})
+}
+
+//Write renders every registered metric in the Prometheus text exposition format.
+//A metric whose collect function fails is left out and its error returned once the rest is written.
+func (r *Registry) Write(ctx context.Context, w io.Writer) error {
+	r.mutex.Lock()
+	families := append([]family{}, r.families...)
+	r.mutex.Unlock()
+	buffered := bufio.NewWriter(w)
+	var collectErr error
+	for _, metric := range families {
+		if err := metric.write(ctx, buffered); err != nil && collectErr == nil {
+			collectErr = err
+		}
+	}
+	if err := buffered.Flush(); err != nil {
+		return err
+	}
+	return collectErr
+}
+
+//Handler serves the registry to Prometheus scrapers
+func (r *Registry) Handler(res http.ResponseWriter, req *http.Request) {
+	res.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	if err := r.Write(req.Context(), res); err != nil {
+		helpers.GetLogger(req.Context()).Error("unable to collect metrics", err, nil)
+	}
+}
+
+//CounterVec is a monotonically increasing value per label combination
+type CounterVec struct {
+	name   string
+	help   string
+	labels []string
+	mutex  sync.Mutex
+	series map[string]*counterSeries
+}
+
+type counterSeries struct {
+	labelValues []string
+	value       float64
+}
+
+//Inc increments the counter of the label values by one
+func (c *CounterVec) Inc(labelValues ...string) {
+	c.Add(1, labelValues...)
+}
+
+//Add increases the counter of the label values by delta, which must not be negative
+func (c *CounterVec) Add(delta float64, labelValues ...string) {
+	if delta < 0 {
+		panic(fmt.Sprintf("counter %s cannot decrease", c.name))
+	}
+	checkLabels(c.name, c.labels, labelValues)
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	key := seriesKey(labelValues)
+	series, found := c.series[key]
+	if !found {
+		series = &counterSeries{labelValues: labelValues}
+		c.series[key] = series
+	}
+	series.value += delta
+}
+
+func (c *CounterVec) write(ctx context.Context, w io.Writer) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	writeHeader(w, c.name, c.help, "counter")
+	keys := make([]string, 0, len(c.series))
+	for key := range c.series {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		series := c.series[key]
+		writeSample(w, c.name, c.labels, series.labelValues, "", "", series.value)
+	}
+	return nil
+}
+
+//HistogramVec counts observations into cumulative buckets per label combination
+type HistogramVec struct {
+	name    string
+	help    string
+	labels  []string
+	buckets []float64
+	mutex   sync.Mutex
+	series  map[string]*histogramSeries
+}
+
+type histogramSeries struct {
+	labelValues []string
+	counts      []uint64
+	sum         float64
+	count       uint64
+}
+
+//Observe records one value for the label values
+func (h *HistogramVec) Observe(value float64, labelValues ...string) {
+	checkLabels(h.name, h.labels, labelValues)
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	key := seriesKey(labelValues)
+	series, found := h.series[key]
+	if !found {
+		series = &histogramSeries{labelValues: labelValues, counts: make([]uint64, len(h.buckets))}
+		h.series[key] = series
+	}
+	for i, upperBound := range h.buckets {
+		if value <= upperBound {
+			series.counts[i]++
+		}
+	}
+	series.sum += value
+	series.count++
+}
+
+func (h *HistogramVec) write(ctx context.Context, w io.Writer) error {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	writeHeader(w, h.name, h.help, "histogram")
+	keys := make([]string, 0, len(h.series))
+	for key := range h.series {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		series := h.series[key]
+		for i, upperBound := range h.buckets {
+			writeSample(w, h.name+"_bucket", h.labels, series.labelValues, "le", formatFloat(upperBound), float64(series.counts[i]))
+		}
+		writeSample(w, h.name+"_bucket", h.labels, series.labelValues, "le", "+Inf", float64(series.count))
+		writeSample(w, h.name+"_sum", h.labels, series.labelValues, "", "", series.sum)
+		writeSample(w, h.name+"_count", h.labels, series.labelValues, "", "", float64(series.count))
+	}
+	return nil
+}
+
+type funcFamily struct {
+	name       string
+	help       string
+	metricType string
+	labels     []string
+	collect    CollectFunc
+}
+
+func (f *funcFamily) write(ctx context.Context, w io.Writer) error {
+	samples, err := f.collect(ctx)
+	if err != nil {
+		return fmt.Errorf("collecting %s: %w", f.name, err)
+	}
+	sort.Slice(samples, func(i, j int) bool {
+		return seriesKey(samples[i].LabelValues) < seriesKey(samples[j].LabelValues)
+	})
+	writeHeader(w, f.name, f.help, f.metricType)
+	for _, sample := range samples {
+		checkLabels(f.name, f.labels, sample.LabelValues)
+		writeSample(w, f.name, f.labels, sample.LabelValues, "", "", sample.Value)
+	}
+	return nil
+}
+
+func checkLabels(name string, labels []string, labelValues []string) {
+	if len(labels) != len(labelValues) {
+		panic(fmt.Sprintf("metric %s expects %d label values, got %d", name, len(labels), len(labelValues)))
+	}
+}
+
+func seriesKey(labelValues []string) string {
+	return strings.Join(labelValues, "\x00")
+}
+
+func writeHeader(w io.Writer, name string, help string, metricType string) {
+	fmt.Fprintf(w, "# HELP %s %s\n", name, strings.NewReplacer("\\", `\\`, "\n", `\n`).Replace(help))
+	fmt.Fprintf(w, "# TYPE %s %s\n", name, metricType)
+}
+
+func writeSample(w io.Writer, name string, labels []string, labelValues []string, extraLabel string, extraValue string, value float64) {
+	pairs := make([]string, 0, len(labels)+1)
+	for i, label := range labels {
+		pairs = append(pairs, label+`="`+escapeLabelValue(labelValues[i])+`"`)
+	}
+	if extraLabel != "" {
+		pairs = append(pairs, extraLabel+`="`+extraValue+`"`)
+	}
+	if len(pairs) > 0 {
+		fmt.Fprintf(w, "%s{%s} %s\n", name, strings.Join(pairs, ","), formatFloat(value))
+		return
+	}
+	fmt.Fprintf(w, "%s %s\n", name, formatFloat(value))
+}
+
+func escapeLabelValue(value string) string {
+	return strings.NewReplacer("\\", `\\`, "\"", `\"`, "\n", `\n`).Replace(value)
+}
+
+func formatFloat(value float64) string {
+	switch {
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	case math.IsNaN(value):
+		return "NaN"
+	}
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
@@ -0,0 +1,90 @@
+package metrics
+
+import (
+	"context"
+	"bytes"
+	"fmt"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteCounterShouldRenderSortedSeries(t *testing.T) {
+	registry := InitRegistry()
+	counter := registry.NewCounterVec("http_requests_total", "Number of HTTP requests served.", []string{"route", "status"})
+	counter.Inc("/news", "200")
+	counter.Add(2, "/news", "200")
+	counter.Inc("/news/{id}", "404")
+	out := new(bytes.Buffer)
+	err := registry.Write(context.Background(), out)
+	assert.Nil(t, err, "There should be no error")
+	assert.Equal(t, `# HELP http_requests_total Number of HTTP requests served.
+# TYPE http_requests_total counter
+http_requests_total{route="/news",status="200"} 3
+http_requests_total{route="/news/{id}",status="404"} 1
+`, out.String())
+}
+
+func TestWriteHistogramShouldRenderCumulativeBuckets(t *testing.T) {
+	registry := InitRegistry()
+	histogram := registry.NewHistogramVec("latency_seconds", "Latency.", []string{"route"}, []float64{0.1, 1})
+	histogram.Observe(0.05, "/news")
+	histogram.Observe(0.5, "/news")
+	histogram.Observe(3, "/news")
+	out := new(bytes.Buffer)
+	registry.Write(context.Background(), out)
+	assert.Equal(t, `# HELP latency_seconds Latency.
+# TYPE latency_seconds histogram
+latency_seconds_bucket{route="/news",le="0.1"} 1
+latency_seconds_bucket{route="/news",le="1"} 2
+latency_seconds_bucket{route="/news",le="+Inf"} 3
+latency_seconds_sum{route="/news"} 3.55
+latency_seconds_count{route="/news"} 3
+`, out.String())
+}
+
+func TestWriteGaugeFuncShouldEscapeLabelValues(t *testing.T) {
+	registry := InitRegistry()
+	registry.RegisterGaugeFunc("news_count", "Number of news.", []string{"status"}, func(ctx context.Context) ([]Sample, error) {
+		return []Sample{{LabelValues: []string{`say "hi"`}, Value: 2}, {LabelValues: []string{"draft"}, Value: 1}}, nil
+	})
+	out := new(bytes.Buffer)
+	registry.Write(context.Background(), out)
+	assert.Equal(t, `# HELP news_count Number of news.
+# TYPE news_count gauge
+news_count{status="draft"} 1
+news_count{status="say \"hi\""} 2
+`, out.String())
+}
+
+func TestWriteFailingCollectorShouldSkipMetric(t *testing.T) {
+	registry := InitRegistry()
+	registry.RegisterGaugeFunc("broken", "Broken.", nil, func(ctx context.Context) ([]Sample, error) {
+		return nil, fmt.Errorf("connection refused")
+	})
+	registry.RegisterGaugeFunc("up", "Up.", nil, func(ctx context.Context) ([]Sample, error) {
+		return []Sample{{Value: 1}}, nil
+	})
+	out := new(bytes.Buffer)
+	err := registry.Write(context.Background(), out)
+	assert.NotNil(t, err, "There should be an error")
+	assert.Equal(t, "# HELP up Up.\n# TYPE up gauge\nup 1\n", out.String())
+}
+
+func TestRegisterDuplicateNameShouldPanic(t *testing.T) {
+	registry := InitRegistry()
+	registry.NewCounterVec("requests_total", "Requests.", nil)
+	assert.Panics(t, func() {
+		registry.NewCounterVec("requests_total", "Requests.", nil)
+	})
+}
+
+func TestHandlerShouldServeTextFormat(t *testing.T) {
+	registry := InitRegistry()
+	registry.NewCounterVec("requests_total", "Requests.", nil).Inc()
+	response := httptest.NewRecorder()
+	registry.Handler(response, httptest.NewRequest("GET", "/metrics", nil))
+	assert.Equal(t, "text/plain; version=0.0.4; charset=utf-8", response.Header().Get("Content-Type"))
+	assert.Contains(t, response.Body.String(), "requests_total 1\n")
+}
@@ -0,0 +1,82 @@
+package middlewares
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"news-topic-api/helpers"
+	"news-topic-api/models"
+	"news-topic-api/services"
+)
+
+//APIKeyHeader is the header machine clients send their api key in
+const APIKeyHeader = "X-API-Key"
+
+//APIKeyAuthenticator authenticates machine clients by their api key
+type APIKeyAuthenticator struct {
+	apiKeyService services.IAPIKeyService
+}
+
+//InitAPIKeyAuthenticator initializes an api key authenticator given the api key service
+func InitAPIKeyAuthenticator(apiKeyService services.IAPIKeyService) APIKeyAuthenticator {
+	apiKeyAuthenticator := new(APIKeyAuthenticator)
+	apiKeyAuthenticator.apiKeyService = apiKeyService
+	return *apiKeyAuthenticator
+}
+
+//Middleware attaches the principal of a valid api key to the request context.
+//Requests without the header are passed through untouched, requests with an invalid key are rejected.
+func (a APIKeyAuthenticator) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		plaintext := req.Header.Get(APIKeyHeader)
+		if plaintext == "" {
+			next.ServeHTTP(res, req)
+			return
+		}
+		if _, ok := helpers.GetPrincipal(req.Context()); ok {
+			helpers.ResponseError(res, req, http.StatusBadRequest, fmt.Errorf("use either a bearer token or an api key, not both"))
+			return
+		}
+		apiKey, err := a.apiKeyService.Authenticate(req.Context(), plaintext)
+		if err != nil {
+			if status, ok := helpers.ContextErrorStatus(req.Context(), err); ok {
+				helpers.ResponseError(res, req, status, err)
+				return
+			}
+			var unavailableError services.UnavailableError
+			if errors.As(err, &unavailableError) {
+				helpers.GetLogger(req.Context()).Error("api key lookup failed", err, nil)
+				helpers.ResponseError(res, req, http.StatusServiceUnavailable, err)
+				return
+			}
+			res.Header().Set("WWW-Authenticate", "ApiKey")
+			helpers.ResponseError(res, req, http.StatusUnauthorized, err)
+			return
+		}
+		principal := models.Principal{
+			Subject:    fmt.Sprintf("apikey:%d", apiKey.ID),
+			Name:       apiKey.Name,
+			Scopes:     apiKey.Scopes,
+			AuthMethod: "api_key",
+		}
+		recordPrincipal(req.Context(), principal)
+		next.ServeHTTP(res, req.WithContext(helpers.WithPrincipal(req.Context(), principal)))
+	})
+}
+
+//Authorized requires an authenticated caller, api key callers must also hold the scope
+func Authorized(scope string, next http.HandlerFunc) http.HandlerFunc {
+	return Authenticated(AllowAnonymous(scope, next))
+}
+
+//AllowAnonymous lets anonymous callers through, but still requires the scope from api key callers
+func AllowAnonymous(scope string, next http.HandlerFunc) http.HandlerFunc {
+	return func(res http.ResponseWriter, req *http.Request) {
+		principal, ok := helpers.GetPrincipal(req.Context())
+		if ok && !principal.HasScope(scope) {
+			helpers.ResponseError(res, req, http.StatusForbidden, fmt.Errorf("api key is missing the %s scope", scope))
+			return
+		}
+		next(res, req)
+	}
+}
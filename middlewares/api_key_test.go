@@ -0,0 +1,102 @@
+package middlewares
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"news-topic-api/helpers"
+	mockServices "news-topic-api/mocks/services"
+	"news-topic-api/models"
+	"news-topic-api/services"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func getMockAPIKey() models.APIKey {
+	return models.APIKey{
+		Model:  gorm.Model{ID: 7},
+		Name:   "ingestion job",
+		Scopes: []string{models.ScopeNewsRead, models.ScopeNewsWrite},
+	}
+}
+
+func getAPIKeyTestHandler(apiKeyService *mockServices.IAPIKeyService, scope string) http.Handler {
+	handler := Authorized(scope, func(res http.ResponseWriter, req *http.Request) {
+		principal, _ := helpers.GetPrincipal(req.Context())
+		res.Write([]byte(principal.Subject))
+	})
+	return InitAPIKeyAuthenticator(apiKeyService).Middleware(handler)
+}
+
+func TestAPIKeyMiddlewareValidKeyShouldReachHandler(t *testing.T) {
+	mockedAPIKeyService := new(mockServices.IAPIKeyService)
+	mockedAPIKeyService.On("Authenticate", mock.Anything, "ntk_abc_secret").Return(getMockAPIKey(), nil)
+	request, _ := http.NewRequest("POST", "/news/", nil)
+	request.Header.Set(APIKeyHeader, "ntk_abc_secret")
+	response := httptest.NewRecorder()
+	getAPIKeyTestHandler(mockedAPIKeyService, models.ScopeNewsWrite).ServeHTTP(response, request)
+	assert.Equal(t, 200, response.Code, "response code should be 200")
+	assert.Equal(t, "apikey:7", response.Body.String())
+}
+
+func TestAPIKeyMiddlewareInvalidKeyShouldReturnUnauthorized(t *testing.T) {
+	mockedAPIKeyService := new(mockServices.IAPIKeyService)
+	mockedAPIKeyService.On("Authenticate", mock.Anything, "ntk_abc_wrong").Return(models.APIKey{}, fmt.Errorf("invalid api key"))
+	request, _ := http.NewRequest("POST", "/news/", nil)
+	request.Header.Set(APIKeyHeader, "ntk_abc_wrong")
+	response := httptest.NewRecorder()
+	getAPIKeyTestHandler(mockedAPIKeyService, models.ScopeNewsWrite).ServeHTTP(response, request)
+	assert.Equal(t, 401, response.Code, "response code should be 401")
+}
+
+func TestAPIKeyMiddlewareTimedOutLookupShouldReturnGatewayTimeout(t *testing.T) {
+	mockedAPIKeyService := new(mockServices.IAPIKeyService)
+	mockedAPIKeyService.On("Authenticate", mock.Anything, "ntk_abc_secret").Return(models.APIKey{}, context.DeadlineExceeded)
+	request, _ := http.NewRequest("POST", "/news/", nil)
+	request.Header.Set(APIKeyHeader, "ntk_abc_secret")
+	response := httptest.NewRecorder()
+	getAPIKeyTestHandler(mockedAPIKeyService, models.ScopeNewsWrite).ServeHTTP(response, request)
+	assert.Equal(t, 504, response.Code, "response code should be 504")
+	assert.Empty(t, response.Header().Get("WWW-Authenticate"))
+}
+
+func TestAPIKeyMiddlewareDatabaseDownShouldReturnServiceUnavailable(t *testing.T) {
+	mockedAPIKeyService := new(mockServices.IAPIKeyService)
+	mockedAPIKeyService.On("Authenticate", mock.Anything, "ntk_abc_secret").Return(models.APIKey{}, services.UnavailableError{Cause: fmt.Errorf("connection refused")})
+	request, _ := http.NewRequest("POST", "/news/", nil)
+	request.Header.Set(APIKeyHeader, "ntk_abc_secret")
+	response := httptest.NewRecorder()
+	getAPIKeyTestHandler(mockedAPIKeyService, models.ScopeNewsWrite).ServeHTTP(response, request)
+	assert.Equal(t, 503, response.Code, "response code should be 503")
+}
+
+func TestAPIKeyMiddlewareMissingScopeShouldReturnForbidden(t *testing.T) {
+	mockedAPIKeyService := new(mockServices.IAPIKeyService)
+	mockedAPIKeyService.On("Authenticate", mock.Anything, "ntk_abc_secret").Return(getMockAPIKey(), nil)
+	request, _ := http.NewRequest("POST", "/tag/", nil)
+	request.Header.Set(APIKeyHeader, "ntk_abc_secret")
+	response := httptest.NewRecorder()
+	getAPIKeyTestHandler(mockedAPIKeyService, models.ScopeTagAdmin).ServeHTTP(response, request)
+	assert.Equal(t, 403, response.Code, "response code should be 403")
+}
+
+func TestAPIKeyMiddlewareWithoutHeaderShouldPassThrough(t *testing.T) {
+	mockedAPIKeyService := new(mockServices.IAPIKeyService)
+	request, _ := http.NewRequest("POST", "/news/", nil)
+	response := httptest.NewRecorder()
+	getAPIKeyTestHandler(mockedAPIKeyService, models.ScopeNewsWrite).ServeHTTP(response, request)
+	assert.Equal(t, 401, response.Code, "response code should be 401")
+	mockedAPIKeyService.AssertNotCalled(t, "Authenticate", mock.Anything, "")
+}
+
+func TestAllowAnonymousWithoutPrincipalShouldPass(t *testing.T) {
+	handler := AllowAnonymous(models.ScopeNewsRead, func(res http.ResponseWriter, req *http.Request) {})
+	request, _ := http.NewRequest("GET", "/news", nil)
+	response := httptest.NewRecorder()
+	handler.ServeHTTP(response, request)
+	assert.Equal(t, 200, response.Code, "response code should be 200")
+}
@@ -0,0 +1,242 @@
+package middlewares
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"net/http"
+	"news-topic-api/helpers"
+	"news-topic-api/models"
+	"strings"
+	"time"
+)
+
+var (
+	errMissingAuthentication = errors.New("authentication required")
+	errMalformedToken        = errors.New("malformed bearer token")
+	errUnsupportedAlgorithm  = errors.New("unsupported token signing algorithm")
+	errInvalidSignature      = errors.New("invalid token signature")
+	errTokenExpired          = errors.New("token is expired")
+	errTokenNotYetValid      = errors.New("token is not valid yet")
+	errAuthNotConfigured     = errors.New("token authentication is not configured")
+)
+
+//JWTConfig holds the rules used to validate bearer tokens
+type JWTConfig struct {
+	Secret    []byte
+	Issuer    string
+	Audience  string
+	ClockSkew time.Duration
+	MaxTTL    time.Duration
+}
+
+//JWTAuthenticator validates HMAC signed bearer tokens
+type JWTAuthenticator struct {
+	config JWTConfig
+	now    func() time.Time
+}
+
+//InitJWTAuthenticator initializes a jwt authenticator given its configuration
+func InitJWTAuthenticator(config JWTConfig) JWTAuthenticator {
+	jwtAuthenticator := new(JWTAuthenticator)
+	jwtAuthenticator.config = config
+	jwtAuthenticator.now = time.Now
+	return *jwtAuthenticator
+}
+
+//Middleware attaches the principal of a valid bearer token to the request context.
+//Requests without a token are passed through anonymously, requests with an invalid one are rejected.
+func (j JWTAuthenticator) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		token, found := bearerToken(req)
+		if !found {
+			next.ServeHTTP(res, req)
+			return
+		}
+		principal, err := j.Verify(token)
+		if err != nil {
+			unauthorized(res, req, err)
+			return
+		}
+		recordPrincipal(req.Context(), principal)
+		next.ServeHTTP(res, req.WithContext(helpers.WithPrincipal(req.Context(), principal)))
+	})
+}
+
+//Authenticated rejects requests that reach the handler without a principal
+func Authenticated(next http.HandlerFunc) http.HandlerFunc {
+	return func(res http.ResponseWriter, req *http.Request) {
+		if _, ok := helpers.GetPrincipal(req.Context()); !ok {
+			unauthorized(res, req, errMissingAuthentication)
+			return
+		}
+		next(res, req)
+	}
+}
+
+type audience []string
+
+func (a *audience) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*a = audience{single}
+		return nil
+	}
+	var multiple []string
+	if err := json.Unmarshal(data, &multiple); err != nil {
+		return err
+	}
+	*a = audience(multiple)
+	return nil
+}
+
+func (a audience) contains(value string) bool {
+	for _, aud := range a {
+		if aud == value {
+			return true
+		}
+	}
+	return false
+}
+
+type jwtHeader struct {
+	Algorithm string `json:"alg"`
+}
+
+type jwtClaims struct {
+	Issuer    string   `json:"iss"`
+	Subject   string   `json:"sub"`
+	Audience  audience `json:"aud"`
+	ExpiresAt *float64 `json:"exp"`
+	NotBefore *float64 `json:"nbf"`
+	IssuedAt  *float64 `json:"iat"`
+	Name      string   `json:"name"`
+	Roles     []string `json:"roles"`
+}
+
+//Verify checks the signature and registered claims of a token and returns its principal
+func (j JWTAuthenticator) Verify(token string) (models.Principal, error) {
+	if len(j.config.Secret) == 0 {
+		return models.Principal{}, errAuthNotConfigured
+	}
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return models.Principal{}, errMalformedToken
+	}
+	var header jwtHeader
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return models.Principal{}, errMalformedToken
+	}
+	hasher, err := hasherFor(header.Algorithm)
+	if err != nil {
+		return models.Principal{}, err
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return models.Principal{}, errMalformedToken
+	}
+	mac := hmac.New(hasher, j.config.Secret)
+	mac.Write([]byte(parts[0] + "." + parts[1]))
+	if !hmac.Equal(signature, mac.Sum(nil)) {
+		return models.Principal{}, errInvalidSignature
+	}
+	var claims jwtClaims
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return models.Principal{}, errMalformedToken
+	}
+	if err := j.validateClaims(claims); err != nil {
+		return models.Principal{}, err
+	}
+	return models.Principal{
+		Subject:    claims.Subject,
+		Name:       claims.Name,
+		Roles:      claims.Roles,
+		AuthMethod: "jwt",
+	}, nil
+}
+
+func (j JWTAuthenticator) validateClaims(claims jwtClaims) error {
+	now := j.now()
+	skew := j.config.ClockSkew
+	if claims.Subject == "" {
+		return fmt.Errorf("token has no subject")
+	}
+	if j.config.Issuer != "" && claims.Issuer != j.config.Issuer {
+		return fmt.Errorf("token issuer %q is not trusted", claims.Issuer)
+	}
+	if j.config.Audience != "" && !claims.Audience.contains(j.config.Audience) {
+		return fmt.Errorf("token is not intended for audience %q", j.config.Audience)
+	}
+	if claims.ExpiresAt == nil {
+		return fmt.Errorf("token has no expiry")
+	}
+	expiresAt := unixTime(*claims.ExpiresAt)
+	if now.After(expiresAt.Add(skew)) {
+		return errTokenExpired
+	}
+	if claims.NotBefore != nil && now.Add(skew).Before(unixTime(*claims.NotBefore)) {
+		return errTokenNotYetValid
+	}
+	if claims.IssuedAt != nil && now.Add(skew).Before(unixTime(*claims.IssuedAt)) {
+		return fmt.Errorf("token is issued in the future")
+	}
+	if j.config.MaxTTL > 0 {
+		if claims.IssuedAt == nil {
+			return fmt.Errorf("token has no issue time")
+		}
+		if expiresAt.Sub(unixTime(*claims.IssuedAt)) > j.config.MaxTTL {
+			return fmt.Errorf("token lifetime exceeds %s", j.config.MaxTTL)
+		}
+	}
+	return nil
+}
+
+func hasherFor(algorithm string) (func() hash.Hash, error) {
+	switch algorithm {
+	case "HS256":
+		return sha256.New, nil
+	case "HS384":
+		return sha512.New384, nil
+	case "HS512":
+		return sha512.New, nil
+	}
+	return nil, errUnsupportedAlgorithm
+}
+
+func decodeSegment(segment string, target interface{}) error {
+	raw, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(raw, target)
+}
+
+func unixTime(seconds float64) time.Time {
+	return time.Unix(0, int64(seconds*float64(time.Second)))
+}
+
+func bearerToken(req *http.Request) (string, bool) {
+	authorization := req.Header.Get("Authorization")
+	if authorization == "" {
+		return "", false
+	}
+	const prefix = "bearer "
+	if len(authorization) < len(prefix) || strings.ToLower(authorization[:len(prefix)]) != prefix {
+		return "", true
+	}
+	return strings.TrimSpace(authorization[len(prefix):]), true
+}
+
+func unauthorized(res http.ResponseWriter, req *http.Request, err error) {
+	challenge := `Bearer error="invalid_token"`
+	if err == errMissingAuthentication {
+		challenge = "Bearer"
+	}
+	res.Header().Set("WWW-Authenticate", challenge)
+	helpers.ResponseError(res, req, http.StatusUnauthorized, err)
+}
@@ -0,0 +1,195 @@
+package middlewares
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"news-topic-api/helpers"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testNow = time.Date(2021, 5, 1, 12, 0, 0, 0, time.UTC)
+
+func getMockJWTConfig() JWTConfig {
+	return JWTConfig{
+		Secret:    []byte("super-secret"),
+		Issuer:    "news-topic-auth",
+		Audience:  "news-topic-api",
+		ClockSkew: 30 * time.Second,
+	}
+}
+
+func getMockClaims() map[string]interface{} {
+	return map[string]interface{}{
+		"iss":   "news-topic-auth",
+		"aud":   "news-topic-api",
+		"sub":   "42",
+		"name":  "Budi Santoso",
+		"roles": []string{"writer"},
+		"iat":   testNow.Add(-time.Minute).Unix(),
+		"exp":   testNow.Add(time.Hour).Unix(),
+	}
+}
+
+func signToken(secret string, alg string, claims map[string]interface{}) string {
+	header, _ := json.Marshal(map[string]string{"alg": alg, "typ": "JWT"})
+	payload, _ := json.Marshal(claims)
+	unsigned := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(unsigned))
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func getTestAuthenticator(config JWTConfig) JWTAuthenticator {
+	jwtAuthenticator := InitJWTAuthenticator(config)
+	jwtAuthenticator.now = func() time.Time { return testNow }
+	return jwtAuthenticator
+}
+
+func TestVerifyValidTokenReturnPrincipal(t *testing.T) {
+	jwtAuthenticator := getTestAuthenticator(getMockJWTConfig())
+	principal, err := jwtAuthenticator.Verify(signToken("super-secret", "HS256", getMockClaims()))
+	assert.Nil(t, err, "There should be no error")
+	assert.Equal(t, "42", principal.Subject)
+	assert.Equal(t, []string{"writer"}, principal.Roles)
+	assert.Equal(t, "jwt", principal.AuthMethod)
+}
+
+func TestVerifyWrongSecretReturnError(t *testing.T) {
+	jwtAuthenticator := getTestAuthenticator(getMockJWTConfig())
+	_, err := jwtAuthenticator.Verify(signToken("other-secret", "HS256", getMockClaims()))
+	assert.Equal(t, errInvalidSignature, err)
+}
+
+func TestVerifyNoneAlgorithmReturnError(t *testing.T) {
+	jwtAuthenticator := getTestAuthenticator(getMockJWTConfig())
+	_, err := jwtAuthenticator.Verify(signToken("super-secret", "none", getMockClaims()))
+	assert.Equal(t, errUnsupportedAlgorithm, err)
+}
+
+func TestVerifyExpiredTokenReturnError(t *testing.T) {
+	jwtAuthenticator := getTestAuthenticator(getMockJWTConfig())
+	claims := getMockClaims()
+	claims["exp"] = testNow.Add(-time.Minute).Unix()
+	_, err := jwtAuthenticator.Verify(signToken("super-secret", "HS256", claims))
+	assert.Equal(t, errTokenExpired, err)
+}
+
+func TestVerifyExpiredWithinClockSkewIsAccepted(t *testing.T) {
+	jwtAuthenticator := getTestAuthenticator(getMockJWTConfig())
+	claims := getMockClaims()
+	claims["exp"] = testNow.Add(-10 * time.Second).Unix()
+	_, err := jwtAuthenticator.Verify(signToken("super-secret", "HS256", claims))
+	assert.Nil(t, err, "There should be no error")
+}
+
+func TestVerifyMissingExpiryReturnError(t *testing.T) {
+	jwtAuthenticator := getTestAuthenticator(getMockJWTConfig())
+	claims := getMockClaims()
+	delete(claims, "exp")
+	_, err := jwtAuthenticator.Verify(signToken("super-secret", "HS256", claims))
+	assert.NotNil(t, err, "There should be an error")
+}
+
+func TestVerifyNotYetValidTokenReturnError(t *testing.T) {
+	jwtAuthenticator := getTestAuthenticator(getMockJWTConfig())
+	claims := getMockClaims()
+	claims["nbf"] = testNow.Add(time.Minute).Unix()
+	_, err := jwtAuthenticator.Verify(signToken("super-secret", "HS256", claims))
+	assert.Equal(t, errTokenNotYetValid, err)
+}
+
+func TestVerifyWrongIssuerReturnError(t *testing.T) {
+	jwtAuthenticator := getTestAuthenticator(getMockJWTConfig())
+	claims := getMockClaims()
+	claims["iss"] = "someone-else"
+	_, err := jwtAuthenticator.Verify(signToken("super-secret", "HS256", claims))
+	assert.NotNil(t, err, "There should be an error")
+}
+
+func TestVerifyAudienceListContainingServiceIsAccepted(t *testing.T) {
+	jwtAuthenticator := getTestAuthenticator(getMockJWTConfig())
+	claims := getMockClaims()
+	claims["aud"] = []string{"other-api", "news-topic-api"}
+	_, err := jwtAuthenticator.Verify(signToken("super-secret", "HS256", claims))
+	assert.Nil(t, err, "There should be no error")
+}
+
+func TestVerifyWrongAudienceReturnError(t *testing.T) {
+	jwtAuthenticator := getTestAuthenticator(getMockJWTConfig())
+	claims := getMockClaims()
+	claims["aud"] = "other-api"
+	_, err := jwtAuthenticator.Verify(signToken("super-secret", "HS256", claims))
+	assert.NotNil(t, err, "There should be an error")
+}
+
+func TestVerifyLifetimeAboveMaxTTLReturnError(t *testing.T) {
+	config := getMockJWTConfig()
+	config.MaxTTL = 15 * time.Minute
+	jwtAuthenticator := getTestAuthenticator(config)
+	_, err := jwtAuthenticator.Verify(signToken("super-secret", "HS256", getMockClaims()))
+	assert.NotNil(t, err, "There should be an error")
+}
+
+func TestVerifyWithoutSecretReturnError(t *testing.T) {
+	config := getMockJWTConfig()
+	config.Secret = nil
+	jwtAuthenticator := getTestAuthenticator(config)
+	_, err := jwtAuthenticator.Verify(signToken("", "HS256", getMockClaims()))
+	assert.Equal(t, errAuthNotConfigured, err)
+}
+
+func getAuthTestHandler(jwtAuthenticator JWTAuthenticator) http.Handler {
+	handler := Authenticated(func(res http.ResponseWriter, req *http.Request) {
+		principal, _ := helpers.GetPrincipal(req.Context())
+		res.Write([]byte(principal.Subject))
+	})
+	return jwtAuthenticator.Middleware(handler)
+}
+
+func TestMiddlewareValidTokenShouldReachHandler(t *testing.T) {
+	handler := getAuthTestHandler(getTestAuthenticator(getMockJWTConfig()))
+	request, _ := http.NewRequest("POST", "/news/", nil)
+	request.Header.Set("Authorization", "Bearer "+signToken("super-secret", "HS256", getMockClaims()))
+	response := httptest.NewRecorder()
+	handler.ServeHTTP(response, request)
+	assert.Equal(t, 200, response.Code, "response code should be 200")
+	assert.Equal(t, "42", response.Body.String())
+}
+
+func TestMiddlewareInvalidTokenShouldReturnUnauthorized(t *testing.T) {
+	handler := getAuthTestHandler(getTestAuthenticator(getMockJWTConfig()))
+	request, _ := http.NewRequest("POST", "/news/", nil)
+	request.Header.Set("Authorization", "Bearer not-a-token")
+	response := httptest.NewRecorder()
+	handler.ServeHTTP(response, request)
+	assert.Equal(t, 401, response.Code, "response code should be 401")
+	assert.Contains(t, response.Header().Get("WWW-Authenticate"), "invalid_token")
+}
+
+func TestMiddlewareAnonymousOnProtectedRouteShouldReturnUnauthorized(t *testing.T) {
+	handler := getAuthTestHandler(getTestAuthenticator(getMockJWTConfig()))
+	request, _ := http.NewRequest("DELETE", "/news/1", nil)
+	response := httptest.NewRecorder()
+	handler.ServeHTTP(response, request)
+	assert.Equal(t, 401, response.Code, "response code should be 401")
+	assert.Equal(t, "Bearer", response.Header().Get("WWW-Authenticate"))
+}
+
+func TestMiddlewareAnonymousOnPublicRouteShouldPass(t *testing.T) {
+	jwtAuthenticator := getTestAuthenticator(getMockJWTConfig())
+	handler := jwtAuthenticator.Middleware(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		_, ok := helpers.GetPrincipal(req.Context())
+		assert.False(t, ok, "Request should be anonymous")
+	}))
+	request, _ := http.NewRequest("GET", "/news", nil)
+	response := httptest.NewRecorder()
+	handler.ServeHTTP(response, request)
+	assert.Equal(t, 200, response.Code, "response code should be 200")
+}
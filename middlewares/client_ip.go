@@ -0,0 +1,25 @@
+package middlewares
+
+import (
+	"net/http"
+	"news-topic-api/helpers"
+)
+
+//ClientIPResolver resolves the caller address once per request
+type ClientIPResolver struct {
+	trustProxy bool
+}
+
+//InitClientIPResolver initializes a client ip resolver, X-Forwarded-For is only honored behind a trusted proxy
+func InitClientIPResolver(trustProxy bool) ClientIPResolver {
+	clientIPResolver := new(ClientIPResolver)
+	clientIPResolver.trustProxy = trustProxy
+	return *clientIPResolver
+}
+
+//Middleware stores the caller address in the request context for the rate limiter and the audit log
+func (c ClientIPResolver) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		next.ServeHTTP(res, helpers.WithClientIP(req, helpers.ClientIP(req, c.trustProxy)))
+	})
+}
@@ -0,0 +1,30 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"news-topic-api/helpers"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func resolveClientIP(trustProxy bool) string {
+	var clientIP string
+	handler := InitClientIPResolver(trustProxy).Middleware(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		clientIP = helpers.GetClientIP(req)
+	}))
+	request := httptest.NewRequest("GET", "/news", nil)
+	request.RemoteAddr = "10.0.0.1:1234"
+	request.Header.Set("X-Forwarded-For", "1.2.3.4, 203.0.113.7")
+	handler.ServeHTTP(httptest.NewRecorder(), request)
+	return clientIP
+}
+
+func TestClientIPResolverBehindTrustedProxyShouldUseForwardedFor(t *testing.T) {
+	assert.Equal(t, "203.0.113.7", resolveClientIP(true))
+}
+
+func TestClientIPResolverWithoutTrustedProxyShouldUseRemoteAddr(t *testing.T) {
+	assert.Equal(t, "10.0.0.1", resolveClientIP(false))
+}
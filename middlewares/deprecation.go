@@ -0,0 +1,35 @@
+package middlewares
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+//Deprecation marks the responses of routes that are kept as aliases of a newer version
+type Deprecation struct {
+	deprecatedAt    time.Time
+	sunset          time.Time
+	successorPrefix string
+}
+
+//InitDeprecation initializes the deprecation of aliases that are served under successorPrefix,
+//they are deprecated since deprecatedAt and may stop being served after sunset
+func InitDeprecation(deprecatedAt time.Time, sunset time.Time, successorPrefix string) Deprecation {
+	deprecation := new(Deprecation)
+	deprecation.deprecatedAt = deprecatedAt
+	deprecation.sunset = sunset
+	deprecation.successorPrefix = successorPrefix
+	return *deprecation
+}
+
+//Middleware answers the Deprecation (RFC 9745) and Sunset (RFC 8594) headers, and links to the same
+//path under the successor prefix
+func (d Deprecation) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		res.Header().Set("Deprecation", fmt.Sprintf("@%d", d.deprecatedAt.Unix()))
+		res.Header().Set("Sunset", d.sunset.UTC().Format(http.TimeFormat))
+		res.Header().Add("Link", fmt.Sprintf(`<%s%s>; rel="successor-version"`, d.successorPrefix, req.URL.Path))
+		next.ServeHTTP(res, req)
+	})
+}
@@ -0,0 +1,23 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeprecationShouldAnswerHeaders(t *testing.T) {
+	deprecation := InitDeprecation(time.Date(2026, time.November, 1, 0, 0, 0, 0, time.UTC), time.Date(2027, time.May, 1, 0, 0, 0, 0, time.UTC), "/v1")
+	handler := deprecation.Middleware(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		res.WriteHeader(http.StatusOK)
+	}))
+	response := httptest.NewRecorder()
+	handler.ServeHTTP(response, httptest.NewRequest("GET", "/news/1?status=draft", nil))
+	assert.Equal(t, 200, response.Code, "response code should be 200")
+	assert.Equal(t, "@1793491200", response.Header().Get("Deprecation"))
+	assert.Equal(t, "Sat, 01 May 2027 00:00:00 GMT", response.Header().Get("Sunset"))
+	assert.Equal(t, `</v1/news/1>; rel="successor-version"`, response.Header().Get("Link"))
+}
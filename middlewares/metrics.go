@@ -0,0 +1,38 @@
+package middlewares
+
+import (
+	"net/http"
+	"news-topic-api/metrics"
+	"strconv"
+	"time"
+)
+
+//RequestMetrics counts requests and their latency by route template and status
+type RequestMetrics struct {
+	requests *metrics.CounterVec
+	duration *metrics.HistogramVec
+	now      func() time.Time
+}
+
+//InitRequestMetrics initializes the request metrics and registers them in registry
+func InitRequestMetrics(registry *metrics.Registry) RequestMetrics {
+	requestMetrics := new(RequestMetrics)
+	requestMetrics.requests = registry.NewCounterVec("http_requests_total", "Number of HTTP requests served.", []string{"method", "route", "status"})
+	requestMetrics.duration = registry.NewHistogramVec("http_request_duration_seconds", "Latency of HTTP requests in seconds.", []string{"method", "route", "status"}, metrics.DefaultBuckets)
+	requestMetrics.now = time.Now
+	return *requestMetrics
+}
+
+//Middleware records the outcome of every routed request.
+//Routes are labeled by their template so ids in the path do not create new series.
+func (m RequestMetrics) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		start := m.now()
+		recorder := &responseRecorder{ResponseWriter: res, status: http.StatusOK}
+		next.ServeHTTP(recorder, req)
+		route := routeTemplate(req)
+		status := strconv.Itoa(recorder.status)
+		m.requests.Inc(req.Method, route, status)
+		m.duration.Observe(m.now().Sub(start).Seconds(), req.Method, route, status)
+	})
+}
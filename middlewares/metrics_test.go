@@ -0,0 +1,44 @@
+package middlewares
+
+import (
+	"context"
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"news-topic-api/metrics"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestMetricsShouldLabelByRouteTemplate(t *testing.T) {
+	registry := metrics.InitRegistry()
+	requestMetrics := InitRequestMetrics(registry)
+	clock := testNow
+	requestMetrics.now = func() time.Time {
+		clock = clock.Add(20 * time.Millisecond)
+		return clock
+	}
+	router := mux.NewRouter()
+	router.Use(requestMetrics.Middleware)
+	router.HandleFunc("/news/{id}", func(res http.ResponseWriter, req *http.Request) {
+		if mux.Vars(req)["id"] == "0" {
+			res.WriteHeader(http.StatusNotFound)
+		}
+	}).Methods("GET")
+	for _, path := range []string{"/news/1", "/news/2", "/news/0"} {
+		request, _ := http.NewRequest("GET", path, nil)
+		router.ServeHTTP(httptest.NewRecorder(), request)
+	}
+	out := new(bytes.Buffer)
+	registry.Write(context.Background(), out)
+	exposition := out.String()
+	assert.Contains(t, exposition, `http_requests_total{method="GET",route="/news/{id}",status="200"} 2`)
+	assert.Contains(t, exposition, `http_requests_total{method="GET",route="/news/{id}",status="404"} 1`)
+	assert.Contains(t, exposition, `http_request_duration_seconds_bucket{method="GET",route="/news/{id}",status="200",le="0.01"} 0`)
+	assert.Contains(t, exposition, `http_request_duration_seconds_bucket{method="GET",route="/news/{id}",status="200",le="0.025"} 2`)
+	assert.False(t, strings.Contains(exposition, "/news/1"), "raw paths should not become labels")
+}
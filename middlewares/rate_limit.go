@@ -0,0 +1,144 @@
+package middlewares
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"news-topic-api/helpers"
+	"strconv"
+	"sync"
+	"time"
+)
+
+//RateLimitConfig holds the token bucket settings of a route group.
+//A bucket holds at most Burst tokens and is refilled with Rate tokens per second.
+type RateLimitConfig struct {
+	Rate    float64
+	Burst   int
+	IdleTTL time.Duration
+}
+
+type bucket struct {
+	tokens   float64
+	lastSeen time.Time
+}
+
+//RateLimiter throttles callers with one token bucket per key, the api key or client ip of the caller
+type RateLimiter struct {
+	config    RateLimitConfig
+	key       func(req *http.Request) string
+	now       func() time.Time
+	mutex     sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+//InitRateLimiter initializes a rate limiter of a route group, api key callers get their own bucket
+//and everyone else shares the bucket of their client ip
+func InitRateLimiter(config RateLimitConfig) *RateLimiter {
+	return initRateLimiter(config, rateLimitKey)
+}
+
+//InitIPRateLimiter initializes a rate limiter keyed by client ip only, it runs before the authenticators
+//so that callers sending bogus credentials are throttled before the credentials are looked up
+func InitIPRateLimiter(config RateLimitConfig) *RateLimiter {
+	return initRateLimiter(config, clientIPRateLimitKey)
+}
+
+func initRateLimiter(config RateLimitConfig, key func(req *http.Request) string) *RateLimiter {
+	rateLimiter := new(RateLimiter)
+	rateLimiter.config = config
+	rateLimiter.key = key
+	rateLimiter.now = time.Now
+	rateLimiter.buckets = map[string]*bucket{}
+	rateLimiter.lastSweep = rateLimiter.now()
+	return rateLimiter
+}
+
+//Middleware rejects callers that ran out of tokens with 429 and reports the quota in RateLimit-* headers.
+//A limiter from InitRateLimiter must run after the authenticators so api key callers get their own bucket.
+func (r *RateLimiter) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		if r.config.Rate <= 0 {
+			next.ServeHTTP(res, req)
+			return
+		}
+		allowed, remaining, retryAfter := r.take(r.key(req))
+		res.Header().Set("RateLimit-Limit", strconv.Itoa(r.config.Burst))
+		res.Header().Set("RateLimit-Remaining", strconv.Itoa(remaining))
+		res.Header().Set("RateLimit-Reset", strconv.Itoa(r.secondsUntilFull(remaining)))
+		if !allowed {
+			res.Header().Set("Retry-After", strconv.Itoa(ceilSeconds(retryAfter)))
+			helpers.ResponseError(res, req, http.StatusTooManyRequests, fmt.Errorf("rate limit exceeded, retry in %d seconds", ceilSeconds(retryAfter)))
+			return
+		}
+		next.ServeHTTP(res, req)
+	})
+}
+
+//Len returns the number of buckets currently tracked
+func (r *RateLimiter) Len() int {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return len(r.buckets)
+}
+
+func (r *RateLimiter) take(key string) (bool, int, time.Duration) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	now := r.now()
+	r.sweep(now)
+	capacity := float64(r.config.Burst)
+	current, found := r.buckets[key]
+	if !found {
+		current = &bucket{tokens: capacity, lastSeen: now}
+		r.buckets[key] = current
+	}
+	elapsed := now.Sub(current.lastSeen).Seconds()
+	if elapsed > 0 {
+		current.tokens = math.Min(capacity, current.tokens+elapsed*r.config.Rate)
+	}
+	current.lastSeen = now
+	if current.tokens < 1 {
+		missing := (1 - current.tokens) / r.config.Rate
+		return false, 0, time.Duration(missing * float64(time.Second))
+	}
+	current.tokens--
+	return true, int(current.tokens), 0
+}
+
+//sweep drops the buckets of callers idle for longer than IdleTTL, at most once per IdleTTL.
+//Only refilled buckets are dropped, so an evicted caller gets no more tokens than it would have had.
+func (r *RateLimiter) sweep(now time.Time) {
+	if now.Sub(r.lastSweep) < r.config.IdleTTL {
+		return
+	}
+	refill := time.Duration(float64(r.config.Burst) / r.config.Rate * float64(time.Second))
+	for key, idle := range r.buckets {
+		idleFor := now.Sub(idle.lastSeen)
+		if idleFor >= r.config.IdleTTL && idleFor >= refill {
+			delete(r.buckets, key)
+		}
+	}
+	r.lastSweep = now
+}
+
+func (r *RateLimiter) secondsUntilFull(remaining int) int {
+	missing := float64(r.config.Burst - remaining)
+	return ceilSeconds(time.Duration(missing / r.config.Rate * float64(time.Second)))
+}
+
+func rateLimitKey(req *http.Request) string {
+	if principal, ok := helpers.GetPrincipal(req.Context()); ok && principal.AuthMethod == "api_key" {
+		return principal.Subject
+	}
+	return clientIPRateLimitKey(req)
+}
+
+func clientIPRateLimitKey(req *http.Request) string {
+	return "ip:" + helpers.GetClientIP(req)
+}
+
+func ceilSeconds(duration time.Duration) int {
+	return int(math.Ceil(duration.Seconds()))
+}
@@ -0,0 +1,109 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"news-topic-api/helpers"
+	"news-topic-api/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func getTestRateLimiter(config RateLimitConfig, clock *time.Time) *RateLimiter {
+	rateLimiter := InitRateLimiter(config)
+	rateLimiter.now = func() time.Time { return *clock }
+	rateLimiter.lastSweep = *clock
+	return rateLimiter
+}
+
+func rateLimitedRequest(rateLimiter *RateLimiter, remoteAddr string, principal *models.Principal) *httptest.ResponseRecorder {
+	handler := rateLimiter.Middleware(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		res.WriteHeader(http.StatusOK)
+	}))
+	request := httptest.NewRequest("GET", "/news", nil)
+	request.RemoteAddr = remoteAddr
+	if principal != nil {
+		request = request.WithContext(helpers.WithPrincipal(request.Context(), *principal))
+	}
+	response := httptest.NewRecorder()
+	handler.ServeHTTP(response, request)
+	return response
+}
+
+func TestRateLimiterWithinBurstShouldPass(t *testing.T) {
+	clock := testNow
+	rateLimiter := getTestRateLimiter(RateLimitConfig{Rate: 1, Burst: 2, IdleTTL: time.Minute}, &clock)
+	response := rateLimitedRequest(rateLimiter, "10.0.0.1:1234", nil)
+	assert.Equal(t, 200, response.Code, "response code should be 200")
+	assert.Equal(t, "2", response.Header().Get("RateLimit-Limit"))
+	assert.Equal(t, "1", response.Header().Get("RateLimit-Remaining"))
+	assert.Equal(t, "1", response.Header().Get("RateLimit-Reset"))
+}
+
+func TestRateLimiterExhaustedShouldReturnTooManyRequests(t *testing.T) {
+	clock := testNow
+	rateLimiter := getTestRateLimiter(RateLimitConfig{Rate: 0.5, Burst: 2, IdleTTL: time.Minute}, &clock)
+	rateLimitedRequest(rateLimiter, "10.0.0.1:1234", nil)
+	rateLimitedRequest(rateLimiter, "10.0.0.1:1234", nil)
+	response := rateLimitedRequest(rateLimiter, "10.0.0.1:1234", nil)
+	assert.Equal(t, 429, response.Code, "response code should be 429")
+	assert.Equal(t, "2", response.Header().Get("Retry-After"))
+	assert.Equal(t, "0", response.Header().Get("RateLimit-Remaining"))
+	assert.Equal(t, "4", response.Header().Get("RateLimit-Reset"))
+	assert.Equal(t, helpers.ProblemContentType, response.Header().Get("Content-Type"))
+}
+
+func TestRateLimiterShouldRefillOverTime(t *testing.T) {
+	clock := testNow
+	rateLimiter := getTestRateLimiter(RateLimitConfig{Rate: 1, Burst: 1, IdleTTL: time.Minute}, &clock)
+	rateLimitedRequest(rateLimiter, "10.0.0.1:1234", nil)
+	assert.Equal(t, 429, rateLimitedRequest(rateLimiter, "10.0.0.1:1234", nil).Code)
+	clock = clock.Add(time.Second)
+	assert.Equal(t, 200, rateLimitedRequest(rateLimiter, "10.0.0.1:1234", nil).Code)
+}
+
+func TestRateLimiterShouldKeyByAPIKeyOrClientIP(t *testing.T) {
+	clock := testNow
+	rateLimiter := getTestRateLimiter(RateLimitConfig{Rate: 1, Burst: 1, IdleTTL: time.Minute}, &clock)
+	apiKey := models.Principal{Subject: "apikey:3", AuthMethod: "api_key"}
+	user := models.Principal{Subject: "42", AuthMethod: "jwt"}
+	assert.Equal(t, 200, rateLimitedRequest(rateLimiter, "10.0.0.1:1234", &apiKey).Code)
+	assert.Equal(t, 429, rateLimitedRequest(rateLimiter, "10.0.0.2:1234", &apiKey).Code)
+	assert.Equal(t, 200, rateLimitedRequest(rateLimiter, "10.0.0.1:1234", &user).Code)
+	assert.Equal(t, 429, rateLimitedRequest(rateLimiter, "10.0.0.1:1234", nil).Code)
+	assert.Equal(t, 200, rateLimitedRequest(rateLimiter, "10.0.0.2:1234", nil).Code)
+}
+
+func TestIPRateLimiterShouldIgnorePrincipal(t *testing.T) {
+	clock := testNow
+	rateLimiter := InitIPRateLimiter(RateLimitConfig{Rate: 1, Burst: 1, IdleTTL: time.Minute})
+	rateLimiter.now = func() time.Time { return clock }
+	apiKey := models.Principal{Subject: "apikey:3", AuthMethod: "api_key"}
+	assert.Equal(t, 200, rateLimitedRequest(rateLimiter, "10.0.0.1:1234", &apiKey).Code)
+	assert.Equal(t, 429, rateLimitedRequest(rateLimiter, "10.0.0.1:1234", nil).Code)
+	assert.Equal(t, 200, rateLimitedRequest(rateLimiter, "10.0.0.2:1234", &apiKey).Code)
+}
+
+func TestRateLimiterShouldEvictIdleBuckets(t *testing.T) {
+	clock := testNow
+	rateLimiter := getTestRateLimiter(RateLimitConfig{Rate: 1, Burst: 5, IdleTTL: time.Minute}, &clock)
+	rateLimitedRequest(rateLimiter, "10.0.0.1:1234", nil)
+	rateLimitedRequest(rateLimiter, "10.0.0.2:1234", nil)
+	assert.Equal(t, 2, rateLimiter.Len())
+	clock = clock.Add(30 * time.Second)
+	rateLimitedRequest(rateLimiter, "10.0.0.2:1234", nil)
+	clock = clock.Add(40 * time.Second)
+	rateLimitedRequest(rateLimiter, "10.0.0.3:1234", nil)
+	assert.Equal(t, 2, rateLimiter.Len(), "only the bucket idle for over a minute should be evicted")
+}
+
+func TestRateLimiterDisabledShouldPassWithoutHeaders(t *testing.T) {
+	clock := testNow
+	rateLimiter := getTestRateLimiter(RateLimitConfig{Rate: 0, Burst: 1, IdleTTL: time.Minute}, &clock)
+	rateLimitedRequest(rateLimiter, "10.0.0.1:1234", nil)
+	response := rateLimitedRequest(rateLimiter, "10.0.0.1:1234", nil)
+	assert.Equal(t, 200, response.Code, "response code should be 200")
+	assert.Equal(t, "", response.Header().Get("RateLimit-Limit"))
+}
@@ -0,0 +1,144 @@
+package middlewares
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"news-topic-api/helpers"
+	"news-topic-api/models"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+//RequestIDHeader is the header a request id is read from and echoed back in
+const RequestIDHeader = "X-Request-ID"
+
+const maxRequestIDLength = 128
+
+//RequestID propagates the X-Request-ID sent by the caller, or assigns a new one,
+//and echoes it back in the response
+func RequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		requestID := req.Header.Get(RequestIDHeader)
+		if !validRequestID(requestID) {
+			requestID = newRequestID()
+			req.Header.Set(RequestIDHeader, requestID)
+		}
+		res.Header().Set(RequestIDHeader, requestID)
+		next.ServeHTTP(res, req.WithContext(helpers.WithRequestID(req.Context(), requestID)))
+	})
+}
+
+func validRequestID(requestID string) bool {
+	if requestID == "" || len(requestID) > maxRequestIDLength {
+		return false
+	}
+	for _, char := range requestID {
+		isAlphaNumeric := (char >= 'a' && char <= 'z') || (char >= 'A' && char <= 'Z') || (char >= '0' && char <= '9')
+		if !isAlphaNumeric && char != '-' && char != '_' && char != '.' {
+			return false
+		}
+	}
+	return true
+}
+
+func newRequestID() string {
+	raw := make([]byte, 16)
+	rand.Read(raw)
+	return hex.EncodeToString(raw)
+}
+
+//RequestLogger writes one structured access log line per request
+type RequestLogger struct {
+	logger helpers.Logger
+	now    func() time.Time
+}
+
+//InitRequestLogger initializes a request logger writing through logger
+func InitRequestLogger(logger helpers.Logger) RequestLogger {
+	requestLogger := new(RequestLogger)
+	requestLogger.logger = logger
+	requestLogger.now = time.Now
+	return *requestLogger
+}
+
+type accessLogEntry struct {
+	principal string
+}
+
+type accessLogKey struct{}
+
+//Middleware binds a logger carrying the request id to the request context and logs the request once served.
+//It must run after RequestID and before the authenticators so it can report the principal.
+func (r RequestLogger) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		start := r.now()
+		logger := r.logger.With(helpers.LogFields{"request_id": helpers.GetRequestID(req.Context())})
+		entry := &accessLogEntry{}
+		ctx := helpers.WithLogger(req.Context(), logger)
+		ctx = context.WithValue(ctx, accessLogKey{}, entry)
+		recorder := &responseRecorder{ResponseWriter: res, status: http.StatusOK}
+		next.ServeHTTP(recorder, req.WithContext(ctx))
+		logger.Info("request", helpers.LogFields{
+			"method":     req.Method,
+			"route":      routeTemplate(req),
+			"path":       req.URL.Path,
+			"status":     recorder.status,
+			"latency_ms": float64(r.now().Sub(start).Microseconds()) / 1000,
+			"bytes":      recorder.bytes,
+			"principal":  entry.principal,
+		})
+	})
+}
+
+//recordPrincipal reports the authenticated caller to the access log of the request
+func recordPrincipal(ctx context.Context, principal models.Principal) {
+	if entry, ok := ctx.Value(accessLogKey{}).(*accessLogEntry); ok {
+		entry.principal = principal.Subject
+	}
+}
+
+func routeTemplate(req *http.Request) string {
+	route := mux.CurrentRoute(req)
+	if route == nil {
+		return ""
+	}
+	template, err := route.GetPathTemplate()
+	if err != nil {
+		return ""
+	}
+	return template
+}
+
+type responseRecorder struct {
+	http.ResponseWriter
+	status      int
+	bytes       int
+	wroteHeader bool
+}
+
+func (r *responseRecorder) WriteHeader(status int) {
+	if !r.wroteHeader {
+		r.status = status
+		r.wroteHeader = true
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+//Flush sends the buffered response to the client when the wrapped writer supports it, so streaming handlers
+//keep working behind the logger
+func (r *responseRecorder) Flush() {
+	if flusher, ok := r.ResponseWriter.(http.Flusher); ok {
+		r.wroteHeader = true
+		flusher.Flush()
+	}
+}
+
+func (r *responseRecorder) Write(data []byte) (int, error) {
+	r.wroteHeader = true
+	written, err := r.ResponseWriter.Write(data)
+	r.bytes += written
+	return written, err
+}
@@ -0,0 +1,124 @@
+package middlewares
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"news-topic-api/helpers"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func getLoggedRouter(out *bytes.Buffer, handler http.HandlerFunc) *mux.Router {
+	requestLogger := InitRequestLogger(helpers.NewLogger(out))
+	clock := testNow
+	requestLogger.now = func() time.Time {
+		clock = clock.Add(1500 * time.Microsecond)
+		return clock
+	}
+	router := mux.NewRouter()
+	router.Use(RequestID)
+	router.Use(requestLogger.Middleware)
+	router.Use(getTestAuthenticator(getMockJWTConfig()).Middleware)
+	router.HandleFunc("/news/{id}", handler).Methods("GET")
+	return router
+}
+
+func decodeLogLines(t *testing.T, out *bytes.Buffer) []map[string]interface{} {
+	var lines []map[string]interface{}
+	decoder := json.NewDecoder(out)
+	for decoder.More() {
+		var line map[string]interface{}
+		assert.Nil(t, decoder.Decode(&line), "log line should be valid json")
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func TestRequestIDShouldPropagateCallerID(t *testing.T) {
+	var seen string
+	router := getLoggedRouter(new(bytes.Buffer), func(res http.ResponseWriter, req *http.Request) {
+		seen = helpers.GetRequestID(req.Context())
+	})
+	request, _ := http.NewRequest("GET", "/news/1", nil)
+	request.Header.Set("X-Request-ID", "abc-123")
+	response := httptest.NewRecorder()
+	router.ServeHTTP(response, request)
+	assert.Equal(t, "abc-123", seen)
+	assert.Equal(t, "abc-123", response.Header().Get("X-Request-ID"))
+}
+
+func TestRequestIDShouldReplaceInvalidID(t *testing.T) {
+	var seen string
+	router := getLoggedRouter(new(bytes.Buffer), func(res http.ResponseWriter, req *http.Request) {
+		seen = helpers.GetRequestID(req.Context())
+	})
+	request, _ := http.NewRequest("GET", "/news/1", nil)
+	request.Header.Set("X-Request-ID", "bad id\n{\"forged\":true}")
+	response := httptest.NewRecorder()
+	router.ServeHTTP(response, request)
+	assert.Len(t, seen, 32)
+	assert.Equal(t, seen, response.Header().Get("X-Request-ID"))
+}
+
+func TestRequestLoggerShouldWriteAccessLogLine(t *testing.T) {
+	out := new(bytes.Buffer)
+	router := getLoggedRouter(out, func(res http.ResponseWriter, req *http.Request) {
+		helpers.GetLogger(req.Context()).Info("handling", nil)
+		res.WriteHeader(http.StatusCreated)
+		res.Write([]byte("hello"))
+	})
+	request, _ := http.NewRequest("GET", "/news/7", nil)
+	request.Header.Set("X-Request-ID", "abc-123")
+	request.Header.Set("Authorization", "Bearer "+signToken("super-secret", "HS256", getMockClaims()))
+	router.ServeHTTP(httptest.NewRecorder(), request)
+	lines := decodeLogLines(t, out)
+	assert.Len(t, lines, 2)
+	assert.Equal(t, "handling", lines[0]["msg"])
+	assert.Equal(t, "abc-123", lines[0]["request_id"], "handler logs should carry the request id")
+	accessLog := lines[1]
+	assert.Equal(t, "request", accessLog["msg"])
+	assert.Equal(t, "abc-123", accessLog["request_id"])
+	assert.Equal(t, "GET", accessLog["method"])
+	assert.Equal(t, "/news/{id}", accessLog["route"])
+	assert.Equal(t, "/news/7", accessLog["path"])
+	assert.Equal(t, float64(201), accessLog["status"])
+	assert.Equal(t, float64(5), accessLog["bytes"])
+	assert.Equal(t, 1.5, accessLog["latency_ms"])
+	assert.Equal(t, "42", accessLog["principal"])
+}
+
+func TestRequestLoggerShouldLogRejectedRequests(t *testing.T) {
+	out := new(bytes.Buffer)
+	router := getLoggedRouter(out, func(res http.ResponseWriter, req *http.Request) {})
+	request, _ := http.NewRequest("GET", "/news/7", nil)
+	request.Header.Set("Authorization", "Bearer not-a-token")
+	router.ServeHTTP(httptest.NewRecorder(), request)
+	lines := decodeLogLines(t, out)
+	assert.Len(t, lines, 1)
+	assert.Equal(t, float64(401), lines[0]["status"])
+	assert.Equal(t, "", lines[0]["principal"])
+}
+
+func TestRequestLoggerShouldKeepFlusher(t *testing.T) {
+	var out bytes.Buffer
+	flushed := false
+	router := getLoggedRouter(&out, func(res http.ResponseWriter, req *http.Request) {
+		flusher, ok := res.(http.Flusher)
+		assert.True(t, ok, "the logged writer should be a flusher")
+		if ok {
+			res.Write([]byte("partial"))
+			flusher.Flush()
+			flushed = true
+		}
+	})
+	response := httptest.NewRecorder()
+	router.ServeHTTP(response, httptest.NewRequest("GET", "/news/1", nil))
+	assert.True(t, flushed)
+	assert.True(t, response.Flushed, "the flush should reach the underlying writer")
+	assert.Equal(t, float64(200), decodeLogLines(t, &out)[0]["status"])
+}
@@ -0,0 +1,33 @@
+package middlewares
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+//Timeout bounds the time the handlers of a route group may spend on a request
+type Timeout struct {
+	duration time.Duration
+}
+
+//InitTimeout initializes a request timeout, a duration of 0 disables it
+func InitTimeout(duration time.Duration) Timeout {
+	timeout := new(Timeout)
+	timeout.duration = duration
+	return *timeout
+}
+
+//Middleware gives the request context a deadline, the queries still running when it expires are cancelled
+//and the controllers answer 504
+func (t Timeout) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		if t.duration <= 0 {
+			next.ServeHTTP(res, req)
+			return
+		}
+		ctx, cancel := context.WithTimeout(req.Context(), t.duration)
+		defer cancel()
+		next.ServeHTTP(res, req.WithContext(ctx))
+	})
+}
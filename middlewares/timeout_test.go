@@ -0,0 +1,41 @@
+package middlewares
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimeoutShouldSetRequestDeadline(t *testing.T) {
+	var deadline time.Time
+	var hasDeadline bool
+	handler := InitTimeout(time.Second).Middleware(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		deadline, hasDeadline = req.Context().Deadline()
+	}))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/news", nil))
+	assert.True(t, hasDeadline, "request context should have a deadline")
+	assert.WithinDuration(t, time.Now().Add(time.Second), deadline, 100*time.Millisecond)
+}
+
+func TestTimeoutShouldExpireRequestContext(t *testing.T) {
+	var err error
+	handler := InitTimeout(time.Millisecond).Middleware(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		<-req.Context().Done()
+		err = req.Context().Err()
+	}))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/news", nil))
+	assert.Equal(t, context.DeadlineExceeded, err)
+}
+
+func TestTimeoutDisabledShouldKeepRequestContext(t *testing.T) {
+	var hasDeadline bool
+	handler := InitTimeout(0).Middleware(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		_, hasDeadline = req.Context().Deadline()
+	}))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/news", nil))
+	assert.False(t, hasDeadline, "request context should not have a deadline")
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"news-topic-api/helpers"
+	"news-topic-api/infrastructures"
+	"news-topic-api/migrations"
+	"os"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const migrateUsage = `Usage: news-topic-api migrate <command> [flags]
+
+Commands:
+  up              apply every pending migration
+  down [-steps N] revert the last N applied migrations, 1 by default,
+                  the initial migration refuses to revert
+  status          list migrations and when they were applied
+  create <name> [-dir D]
+                  write an empty up and down migration in D, migrations/sql by default,
+                  flags may come before or after the name
+`
+
+//runMigrate runs a migrate subcommand and returns the exit code
+func runMigrate(args []string) int {
+	if len(args) == 0 || args[0] == "-h" || args[0] == "--help" || args[0] == "help" {
+		fmt.Fprint(os.Stderr, migrateUsage)
+		if len(args) == 0 {
+			return exitUsage
+		}
+		return exitOK
+	}
+	command := args[0]
+	flags := newFlagSet("migrate "+command, migrateUsage)
+	steps := flags.Int("steps", 1, "number of migrations to revert")
+	dir := flags.String("dir", migrations.Dir, "directory new migrations are created in")
+	if exitCode, ok := parseFlags(flags, args[1:]); !ok {
+		return exitCode
+	}
+
+	switch command {
+	case "create":
+		if flags.NArg() == 0 {
+			fmt.Fprintln(os.Stderr, "migrate create needs exactly one name")
+			return exitUsage
+		}
+		//flag parsing stops at the name, parse what follows it as well
+		name := flags.Arg(0)
+		if exitCode, ok := parseFlags(flags, flags.Args()[1:]); !ok {
+			return exitCode
+		}
+		if flags.NArg() != 0 {
+			fmt.Fprintln(os.Stderr, "migrate create needs exactly one name")
+			return exitUsage
+		}
+		paths, err := migrations.Create(*dir, name, time.Now())
+		if err != nil {
+			return fail(err)
+		}
+		for _, path := range paths {
+			fmt.Println("created", path)
+		}
+		return exitOK
+	case "up", "down", "status":
+		if *steps < 1 {
+			fmt.Fprintln(os.Stderr, "-steps must be at least 1")
+			return exitUsage
+		}
+		_, db, err := openDatabase()
+		if err != nil {
+			return fail(err)
+		}
+		defer infrastructures.CloseDB(db)
+		if err := runMigrateCommand(db, command, *steps); err != nil {
+			return fail(err)
+		}
+		return exitOK
+	}
+	fmt.Fprintf(os.Stderr, "unknown migrate command %q\n\n%s", command, migrateUsage)
+	return exitUsage
+}
+
+func runMigrateCommand(db *gorm.DB, command string, steps int) error {
+	ctx := context.Background()
+	migrator, err := infrastructures.GetMigrator(db)
+	if err != nil {
+		return err
+	}
+	switch command {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		for _, migration := range applied {
+			fmt.Printf("applied %d_%s\n", migration.Version, migration.Name)
+		}
+		if err == nil && len(applied) == 0 {
+			fmt.Println("no pending migrations")
+		}
+		return err
+	case "down":
+		reverted, err := migrator.Down(ctx, steps)
+		for _, migration := range reverted {
+			fmt.Printf("reverted %d_%s\n", migration.Version, migration.Name)
+		}
+		if err == nil && len(reverted) == 0 {
+			fmt.Println("no applied migrations")
+		}
+		return err
+	}
+	statuses, err := migrator.Status(ctx)
+	if err != nil {
+		return err
+	}
+	for _, status := range statuses {
+		appliedAt := "pending"
+		if status.AppliedAt != nil {
+			appliedAt = status.AppliedAt.UTC().Format(time.RFC3339)
+		}
+		fmt.Printf("%-16d %-40s %s\n", status.Version, status.Name, appliedAt)
+	}
+	return nil
+}
+
+//migrateUp applies pending migrations when the server starts
+func migrateUp(db *gorm.DB) error {
+	migrator, err := infrastructures.GetMigrator(db)
+	if err != nil {
+		return err
+	}
+	applied, err := migrator.Up(context.Background())
+	for _, migration := range applied {
+		helpers.DefaultLogger().Info("applied migration", helpers.LogFields{"version": migration.Version, "name": migration.Name})
+	}
+	return err
+}
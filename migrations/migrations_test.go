@@ -0,0 +1,87 @@
+package migrations
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEmbeddedShouldLoadOrderedMigrations(t *testing.T) {
+	migrations, err := Embedded()
+	assert.Nil(t, err, "There should be no error")
+	assert.NotEmpty(t, migrations)
+	assert.Equal(t, "initial_schema", migrations[0].Name)
+	for i := 1; i < len(migrations); i++ {
+		assert.True(t, migrations[i-1].Version < migrations[i].Version, "migrations should be ordered by version")
+	}
+}
+
+func TestEmbeddedInitialMigrationShouldRefuseToRevert(t *testing.T) {
+	migrations, err := Embedded()
+	assert.Nil(t, err, "There should be no error")
+	assert.Contains(t, migrations[0].Down, "RAISE EXCEPTION")
+	assert.NotContains(t, migrations[0].Down, "DROP", "reverting the initial migration would drop adopted tables")
+}
+
+func TestLoadShouldPairUpAndDownFiles(t *testing.T) {
+	fsys := fstest.MapFS{
+		"2_add_index.up.sql":     {Data: []byte("CREATE INDEX idx ON news (topic);")},
+		"2_add_index.down.sql":   {Data: []byte("DROP INDEX idx;")},
+		"1_create_news.up.sql":   {Data: []byte("CREATE TABLE news ();")},
+		"1_create_news.down.sql": {Data: []byte("DROP TABLE news;")},
+	}
+	migrations, err := Load(fsys)
+	assert.Nil(t, err, "There should be no error")
+	assert.Equal(t, []Migration{
+		{Version: 1, Name: "create_news", Up: "CREATE TABLE news ();", Down: "DROP TABLE news;"},
+		{Version: 2, Name: "add_index", Up: "CREATE INDEX idx ON news (topic);", Down: "DROP INDEX idx;"},
+	}, migrations)
+}
+
+func TestLoadMissingDownFileShouldFail(t *testing.T) {
+	fsys := fstest.MapFS{
+		"1_create_news.up.sql": {Data: []byte("CREATE TABLE news ();")},
+	}
+	_, err := Load(fsys)
+	assert.NotNil(t, err, "There should be an error")
+}
+
+func TestLoadDuplicateVersionShouldFail(t *testing.T) {
+	fsys := fstest.MapFS{
+		"1_create_news.up.sql":   {Data: []byte("CREATE TABLE news ();")},
+		"1_create_news.down.sql": {Data: []byte("DROP TABLE news;")},
+		"1_create_tags.up.sql":   {Data: []byte("CREATE TABLE tags ();")},
+	}
+	_, err := Load(fsys)
+	assert.NotNil(t, err, "There should be an error")
+}
+
+func TestLoadMalformedFileNameShouldFail(t *testing.T) {
+	fsys := fstest.MapFS{
+		"create_news.sql": {Data: []byte("CREATE TABLE news ();")},
+	}
+	_, err := Load(fsys)
+	assert.NotNil(t, err, "There should be an error")
+}
+
+func TestCreateShouldWriteLoadableFiles(t *testing.T) {
+	dir := t.TempDir()
+	paths, err := Create(dir, "add_news_slug", time.Date(2021, 6, 1, 8, 30, 0, 0, time.UTC))
+	assert.Nil(t, err, "There should be no error")
+	assert.Equal(t, []string{
+		filepath.Join(dir, "20210601083000_add_news_slug.up.sql"),
+		filepath.Join(dir, "20210601083000_add_news_slug.down.sql"),
+	}, paths)
+	migrations, err := Load(os.DirFS(dir))
+	assert.Nil(t, err, "There should be no error")
+	assert.Equal(t, int64(20210601083000), migrations[0].Version)
+}
+
+func TestCreateInvalidNameShouldFail(t *testing.T) {
+	_, err := Create(t.TempDir(), "Add News Slug", time.Now())
+	assert.NotNil(t, err, "There should be an error")
+}
@@ -0,0 +1,123 @@
+package migrations
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	lockQuery          = `SELECT pg_advisory_lock\(\$1\)`
+	unlockQuery        = `SELECT pg_advisory_unlock\(\$1\)`
+	createTableQuery   = `CREATE TABLE IF NOT EXISTS schema_migrations`
+	appliedQuery       = `SELECT version, applied_at FROM schema_migrations`
+	insertVersionQuery = `INSERT INTO schema_migrations`
+	deleteVersionQuery = `DELETE FROM schema_migrations WHERE version = \$1`
+)
+
+var testNow = time.Date(2021, 6, 1, 8, 30, 0, 0, time.UTC)
+
+func getMockMigrations() []Migration {
+	return []Migration{
+		{Version: 1, Name: "create_news", Up: "CREATE TABLE news ()", Down: "DROP TABLE news"},
+		{Version: 2, Name: "create_tags", Up: "CREATE TABLE tags ()", Down: "DROP TABLE tags"},
+		{Version: 3, Name: "add_index", Up: "CREATE INDEX idx ON tags (name)", Down: "DROP INDEX idx"},
+	}
+}
+
+func setUpMigrator(t *testing.T) (sqlmock.Sqlmock, Migrator) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err, "sqlmock should open")
+	t.Cleanup(func() { db.Close() })
+	migrator := InitMigrator(db, getMockMigrations())
+	migrator.now = func() time.Time { return testNow }
+	return mock, migrator
+}
+
+func expectLocked(mock sqlmock.Sqlmock, applied ...int64) {
+	mock.ExpectExec(lockQuery).WithArgs(lockKey).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(createTableQuery).WillReturnResult(sqlmock.NewResult(0, 0))
+	rows := sqlmock.NewRows([]string{"version", "applied_at"})
+	for _, version := range applied {
+		rows.AddRow(version, testNow)
+	}
+	mock.ExpectQuery(appliedQuery).WillReturnRows(rows)
+}
+
+func TestUpShouldApplyPendingMigrationsInOrder(t *testing.T) {
+	mock, migrator := setUpMigrator(t)
+	expectLocked(mock, 1)
+	for _, migration := range getMockMigrations()[1:] {
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta(migration.Up)).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(insertVersionQuery).WithArgs(migration.Version, migration.Name, testNow).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+	}
+	mock.ExpectExec(unlockQuery).WithArgs(lockKey).WillReturnResult(sqlmock.NewResult(0, 0))
+	applied, err := migrator.Up(context.Background())
+	assert.Nil(t, err, "There should be no error")
+	assert.Equal(t, getMockMigrations()[1:], applied)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestUpFailingMigrationShouldRollbackAndStop(t *testing.T) {
+	mock, migrator := setUpMigrator(t)
+	expectLocked(mock, 1)
+	mock.ExpectBegin()
+	mock.ExpectExec("CREATE TABLE tags").WillReturnError(errors.New("syntax error"))
+	mock.ExpectRollback()
+	mock.ExpectExec(unlockQuery).WithArgs(lockKey).WillReturnResult(sqlmock.NewResult(0, 0))
+	applied, err := migrator.Up(context.Background())
+	assert.NotNil(t, err, "There should be an error")
+	assert.Empty(t, applied)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestUpLockFailureShouldNotMigrate(t *testing.T) {
+	mock, migrator := setUpMigrator(t)
+	mock.ExpectExec(lockQuery).WithArgs(lockKey).WillReturnError(errors.New("canceling statement due to statement timeout"))
+	_, err := migrator.Up(context.Background())
+	assert.NotNil(t, err, "There should be an error")
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestDownShouldRevertNewestFirst(t *testing.T) {
+	mock, migrator := setUpMigrator(t)
+	expectLocked(mock, 1, 2, 3)
+	for _, migration := range []Migration{getMockMigrations()[2], getMockMigrations()[1]} {
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta(migration.Down)).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(deleteVersionQuery).WithArgs(migration.Version).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+	}
+	mock.ExpectExec(unlockQuery).WithArgs(lockKey).WillReturnResult(sqlmock.NewResult(0, 0))
+	reverted, err := migrator.Down(context.Background(), 2)
+	assert.Nil(t, err, "There should be no error")
+	assert.Len(t, reverted, 2)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestStatusShouldReportPendingMigrations(t *testing.T) {
+	mock, migrator := setUpMigrator(t)
+	expectLocked(mock, 1)
+	mock.ExpectExec(unlockQuery).WithArgs(lockKey).WillReturnResult(sqlmock.NewResult(0, 0))
+	statuses, err := migrator.Status(context.Background())
+	assert.Nil(t, err, "There should be no error")
+	assert.Len(t, statuses, 3)
+	assert.Equal(t, testNow, *statuses[0].AppliedAt)
+	assert.Nil(t, statuses[1].AppliedAt)
+	assert.Nil(t, statuses[2].AppliedAt)
+}
+
+func TestPendingShouldListUnappliedMigrations(t *testing.T) {
+	mock, migrator := setUpMigrator(t)
+	mock.ExpectQuery(`SELECT version FROM schema_migrations`).WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(1).AddRow(2))
+	pending, err := migrator.Pending(context.Background())
+	assert.Nil(t, err, "There should be no error")
+	assert.Equal(t, []Migration{getMockMigrations()[2]}, pending)
+}
@@ -0,0 +1,7 @@
+-- The initial migration adopted tables created by the former gorm AutoMigrate along with their data,
+-- so reverting it would drop the whole database. Drop the tables by hand if that is really meant.
+DO $$
+BEGIN
+    RAISE EXCEPTION 'migration 20210501000000_initial_schema cannot be reverted';
+END
+$$;
@@ -0,0 +1,88 @@
+-- Schema previously created by gorm AutoMigrate, IF NOT EXISTS lets databases created that way adopt it.
+
+CREATE TABLE IF NOT EXISTS news (
+    id bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    title text NOT NULL,
+    thumbnail text NOT NULL,
+    summary text NOT NULL,
+    content text NOT NULL,
+    topic text NOT NULL,
+    status text NOT NULL,
+    created_by text
+);
+-- columns added after the AutoMigrate baseline, CREATE TABLE IF NOT EXISTS skips them on tables that already exist
+ALTER TABLE news ADD COLUMN IF NOT EXISTS created_by text;
+CREATE INDEX IF NOT EXISTS idx_news_deleted_at ON news (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_news_created_by ON news (created_by);
+
+CREATE TABLE IF NOT EXISTS tags (
+    id bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    name text NOT NULL UNIQUE
+);
+CREATE INDEX IF NOT EXISTS idx_tags_deleted_at ON tags (deleted_at);
+
+CREATE TABLE IF NOT EXISTS authors (
+    id bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    name text NOT NULL,
+    bio text,
+    avatar text
+);
+CREATE INDEX IF NOT EXISTS idx_authors_deleted_at ON authors (deleted_at);
+
+CREATE TABLE IF NOT EXISTS news_tag (
+    news_id bigint,
+    tag_id bigint,
+    PRIMARY KEY (news_id, tag_id),
+    CONSTRAINT fk_news_tag_news FOREIGN KEY (news_id) REFERENCES news (id) ON UPDATE CASCADE ON DELETE SET NULL,
+    CONSTRAINT fk_news_tag_tag FOREIGN KEY (tag_id) REFERENCES tags (id) ON UPDATE CASCADE ON DELETE SET NULL
+);
+
+CREATE TABLE IF NOT EXISTS news_author (
+    news_id bigint,
+    author_id bigint,
+    PRIMARY KEY (news_id, author_id),
+    CONSTRAINT fk_news_author_news FOREIGN KEY (news_id) REFERENCES news (id) ON UPDATE CASCADE ON DELETE SET NULL,
+    CONSTRAINT fk_news_author_author FOREIGN KEY (author_id) REFERENCES authors (id) ON UPDATE CASCADE ON DELETE SET NULL
+);
+
+CREATE TABLE IF NOT EXISTS api_keys (
+    id bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    name text NOT NULL,
+    owner text NOT NULL,
+    prefix text NOT NULL,
+    key_hash text NOT NULL,
+    scopes text[] NOT NULL,
+    expires_at timestamptz,
+    last_used_at timestamptz,
+    revoked_at timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_api_keys_deleted_at ON api_keys (deleted_at);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_api_keys_prefix ON api_keys (prefix);
+
+CREATE TABLE IF NOT EXISTS audit_logs (
+    id bigserial PRIMARY KEY,
+    timestamp timestamptz NOT NULL,
+    actor text NOT NULL,
+    action text NOT NULL,
+    entity_type text NOT NULL,
+    entity_id bigint NOT NULL,
+    before jsonb,
+    after jsonb,
+    request_id text,
+    ip text
+);
+CREATE INDEX IF NOT EXISTS idx_audit_logs_timestamp ON audit_logs (timestamp);
+CREATE INDEX IF NOT EXISTS idx_audit_logs_actor ON audit_logs (actor);
+CREATE INDEX IF NOT EXISTS idx_audit_logs_entity ON audit_logs (entity_type, entity_id);
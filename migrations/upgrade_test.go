@@ -0,0 +1,109 @@
+package migrations
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+//baselineNews and baselineTag are the models gorm AutoMigrate created tables from before the migrations existed
+type baselineNews struct {
+	gorm.Model
+	Title     string        `gorm:"not null"`
+	Thumbnail string        `gorm:"not null"`
+	Summary   string        `gorm:"not null"`
+	Content   string        `gorm:"not null"`
+	Tags      []baselineTag `gorm:"many2many:news_tag;not null;constraint:OnUpdate:CASCADE,OnDelete:SET NULL;"`
+	Topic     string        `gorm:"not null"`
+	Status    string        `gorm:"not null"`
+}
+
+func (baselineNews) TableName() string {
+	return "news"
+}
+
+type baselineTag struct {
+	gorm.Model
+	Name string `gorm:"not null;unique;"`
+}
+
+func (baselineTag) TableName() string {
+	return "tags"
+}
+
+//openTestSchema opens TEST_DATABASE_URL in a schema of its own, dropped when the test ends. CI must always set it
+func openTestSchema(t *testing.T) *gorm.DB {
+	url := os.Getenv("TEST_DATABASE_URL")
+	if url == "" {
+		if os.Getenv("CI") != "" {
+			t.Fatal("TEST_DATABASE_URL is not set in CI, the upgrade would not be checked")
+		}
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+	config := &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)}
+	admin, err := gorm.Open(postgres.Open(url), config)
+	if err != nil {
+		t.Fatalf("unable to open the test database: %s", err)
+	}
+	schema := fmt.Sprintf("upgrade_%d", time.Now().UnixNano())
+	if err := admin.Exec("CREATE SCHEMA " + schema).Error; err != nil {
+		t.Fatalf("unable to create schema %s: %s", schema, err)
+	}
+	separator := " "
+	if strings.Contains(url, "://") {
+		separator = "&"
+		if !strings.Contains(url, "?") {
+			separator = "?"
+		}
+	}
+	db, err := gorm.Open(postgres.Open(url+separator+"search_path="+schema), config)
+	if err != nil {
+		t.Fatalf("unable to open schema %s: %s", schema, err)
+	}
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+		admin.Exec("DROP SCHEMA " + schema + " CASCADE")
+		if sqlDB, err := admin.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+	return db
+}
+
+func TestUpShouldUpgradeBaselineAutoMigrateSchema(t *testing.T) {
+	db := openTestSchema(t)
+	assert.Nil(t, db.AutoMigrate(&baselineNews{}, &baselineTag{}), "the baseline schema should be created")
+	assert.Nil(t, db.Create(&baselineNews{Title: "Harga bitcoin anjlok", Thumbnail: "t", Summary: "s", Content: "c", Topic: "crypto", Status: "published"}).Error)
+
+	sqlDB, err := db.DB()
+	assert.Nil(t, err)
+	embedded, err := Embedded()
+	assert.Nil(t, err)
+	applied, err := InitMigrator(sqlDB, embedded).Up(context.Background())
+	assert.Nil(t, err, "the migrations should apply over the baseline schema")
+	assert.Equal(t, embedded, applied)
+
+	var createdBy []*string
+	assert.Nil(t, db.Raw("SELECT created_by FROM news").Scan(&createdBy).Error, "news should have the created_by column")
+	assert.Equal(t, []*string{nil}, createdBy, "existing news should keep an empty created_by")
+	var indexes int64
+	assert.Nil(t, db.Raw("SELECT count(*) FROM pg_indexes WHERE schemaname = current_schema() AND indexname = 'idx_news_created_by'").Scan(&indexes).Error)
+	assert.Equal(t, int64(1), indexes)
+
+	reverted, err := InitMigrator(sqlDB, embedded[:1]).Down(context.Background(), 1)
+	assert.NotNil(t, err, "the initial migration should not be reverted")
+	assert.Empty(t, reverted)
+	var news int64
+	assert.Nil(t, db.Raw("SELECT count(*) FROM news").Scan(&news).Error, "news should survive the refused revert")
+	assert.Equal(t, int64(1), news)
+}
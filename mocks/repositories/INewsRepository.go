@@ -3,6 +3,8 @@
 package mocks
 
 import (
+	context "context"
+
 	models "news-topic-api/models"
 
 	mock "github.com/stretchr/testify/mock"
@@ -13,20 +15,43 @@ type INewsRepository struct {
 	mock.Mock
 }
 
-// Create provides a mock function with given fields: news
-func (_m *INewsRepository) Create(news models.News) (models.News, error) {
-	ret := _m.Called(news)
+// CountByStatus provides a mock function with given fields: ctx
+func (_m *INewsRepository) CountByStatus(ctx context.Context) (map[string]int64, error) {
+	ret := _m.Called(ctx)
+
+	var r0 map[string]int64
+	if rf, ok := ret.Get(0).(func(context.Context) map[string]int64); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]int64)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Create provides a mock function with given fields: ctx, news, audit
+func (_m *INewsRepository) Create(ctx context.Context, news models.News, audit models.AuditLog) (models.News, error) {
+	ret := _m.Called(ctx, news, audit)
 
 	var r0 models.News
-	if rf, ok := ret.Get(0).(func(models.News) models.News); ok {
-		r0 = rf(news)
+	if rf, ok := ret.Get(0).(func(context.Context, models.News, models.AuditLog) models.News); ok {
+		r0 = rf(ctx, news, audit)
 	} else {
 		r0 = ret.Get(0).(models.News)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(models.News) error); ok {
-		r1 = rf(news)
+	if rf, ok := ret.Get(1).(func(context.Context, models.News, models.AuditLog) error); ok {
+		r1 = rf(ctx, news, audit)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -34,13 +59,36 @@ func (_m *INewsRepository) Create(news models.News) (models.News, error) {
 	return r0, r1
 }
 
-// Delete provides a mock function with given fields: newsID
-func (_m *INewsRepository) Delete(newsID uint) error {
-	ret := _m.Called(newsID)
+// CreateBatch provides a mock function with given fields: ctx, newsList, batchSize, audit
+func (_m *INewsRepository) CreateBatch(ctx context.Context, newsList []models.News, batchSize int, audit models.AuditLog) ([]models.News, error) {
+	ret := _m.Called(ctx, newsList, batchSize, audit)
+
+	var r0 []models.News
+	if rf, ok := ret.Get(0).(func(context.Context, []models.News, int, models.AuditLog) []models.News); ok {
+		r0 = rf(ctx, newsList, batchSize, audit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.News)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, []models.News, int, models.AuditLog) error); ok {
+		r1 = rf(ctx, newsList, batchSize, audit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Delete provides a mock function with given fields: ctx, newsID, audit
+func (_m *INewsRepository) Delete(ctx context.Context, newsID uint, audit models.AuditLog) error {
+	ret := _m.Called(ctx, newsID, audit)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(uint) error); ok {
-		r0 = rf(newsID)
+	if rf, ok := ret.Get(0).(func(context.Context, uint, models.AuditLog) error); ok {
+		r0 = rf(ctx, newsID, audit)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -48,20 +96,20 @@ func (_m *INewsRepository) Delete(newsID uint) error {
 	return r0
 }
 
-// GetByID provides a mock function with given fields: penyitaanID
-func (_m *INewsRepository) GetByID(penyitaanID uint) (models.News, error) {
-	ret := _m.Called(penyitaanID)
+// GetByID provides a mock function with given fields: ctx, penyitaanID
+func (_m *INewsRepository) GetByID(ctx context.Context, penyitaanID uint) (models.News, error) {
+	ret := _m.Called(ctx, penyitaanID)
 
 	var r0 models.News
-	if rf, ok := ret.Get(0).(func(uint) models.News); ok {
-		r0 = rf(penyitaanID)
+	if rf, ok := ret.Get(0).(func(context.Context, uint) models.News); ok {
+		r0 = rf(ctx, penyitaanID)
 	} else {
 		r0 = ret.Get(0).(models.News)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(uint) error); ok {
-		r1 = rf(penyitaanID)
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, penyitaanID)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -69,13 +117,13 @@ func (_m *INewsRepository) GetByID(penyitaanID uint) (models.News, error) {
 	return r0, r1
 }
 
-// List provides a mock function with given fields: queryParams
-func (_m *INewsRepository) List(queryParams map[string]string) ([]models.News, error) {
-	ret := _m.Called(queryParams)
+// List provides a mock function with given fields: ctx, queryParams
+func (_m *INewsRepository) List(ctx context.Context, queryParams map[string]string) ([]models.News, error) {
+	ret := _m.Called(ctx, queryParams)
 
 	var r0 []models.News
-	if rf, ok := ret.Get(0).(func(map[string]string) []models.News); ok {
-		r0 = rf(queryParams)
+	if rf, ok := ret.Get(0).(func(context.Context, map[string]string) []models.News); ok {
+		r0 = rf(ctx, queryParams)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]models.News)
@@ -83,8 +131,29 @@ func (_m *INewsRepository) List(queryParams map[string]string) ([]models.News, e
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(map[string]string) error); ok {
-		r1 = rf(queryParams)
+	if rf, ok := ret.Get(1).(func(context.Context, map[string]string) error); ok {
+		r1 = rf(ctx, queryParams)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Restore provides a mock function with given fields: ctx, newsID, audit
+func (_m *INewsRepository) Restore(ctx context.Context, newsID uint, audit models.AuditLog) (models.News, error) {
+	ret := _m.Called(ctx, newsID, audit)
+
+	var r0 models.News
+	if rf, ok := ret.Get(0).(func(context.Context, uint, models.AuditLog) models.News); ok {
+		r0 = rf(ctx, newsID, audit)
+	} else {
+		r0 = ret.Get(0).(models.News)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, uint, models.AuditLog) error); ok {
+		r1 = rf(ctx, newsID, audit)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -92,20 +161,20 @@ func (_m *INewsRepository) List(queryParams map[string]string) ([]models.News, e
 	return r0, r1
 }
 
-// Update provides a mock function with given fields: newsID, news
-func (_m *INewsRepository) Update(newsID uint, news models.News) (models.News, error) {
-	ret := _m.Called(newsID, news)
+// Update provides a mock function with given fields: ctx, newsID, news, audit
+func (_m *INewsRepository) Update(ctx context.Context, newsID uint, news models.News, audit models.AuditLog) (models.News, error) {
+	ret := _m.Called(ctx, newsID, news, audit)
 
 	var r0 models.News
-	if rf, ok := ret.Get(0).(func(uint, models.News) models.News); ok {
-		r0 = rf(newsID, news)
+	if rf, ok := ret.Get(0).(func(context.Context, uint, models.News, models.AuditLog) models.News); ok {
+		r0 = rf(ctx, newsID, news, audit)
 	} else {
 		r0 = ret.Get(0).(models.News)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(uint, models.News) error); ok {
-		r1 = rf(newsID, news)
+	if rf, ok := ret.Get(1).(func(context.Context, uint, models.News, models.AuditLog) error); ok {
+		r1 = rf(ctx, newsID, news, audit)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -3,6 +3,8 @@
 package mocks
 
 import (
+	context "context"
+
 	models "news-topic-api/models"
 
 	mock "github.com/stretchr/testify/mock"
@@ -13,20 +15,20 @@ type ITagRepository struct {
 	mock.Mock
 }
 
-// Create provides a mock function with given fields: tag
-func (_m *ITagRepository) Create(tag models.Tag) (models.Tag, error) {
-	ret := _m.Called(tag)
+// Create provides a mock function with given fields: ctx, tag, audit
+func (_m *ITagRepository) Create(ctx context.Context, tag models.Tag, audit models.AuditLog) (models.Tag, error) {
+	ret := _m.Called(ctx, tag, audit)
 
 	var r0 models.Tag
-	if rf, ok := ret.Get(0).(func(models.Tag) models.Tag); ok {
-		r0 = rf(tag)
+	if rf, ok := ret.Get(0).(func(context.Context, models.Tag, models.AuditLog) models.Tag); ok {
+		r0 = rf(ctx, tag, audit)
 	} else {
 		r0 = ret.Get(0).(models.Tag)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(models.Tag) error); ok {
-		r1 = rf(tag)
+	if rf, ok := ret.Get(1).(func(context.Context, models.Tag, models.AuditLog) error); ok {
+		r1 = rf(ctx, tag, audit)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -34,13 +36,36 @@ func (_m *ITagRepository) Create(tag models.Tag) (models.Tag, error) {
 	return r0, r1
 }
 
-// Delete provides a mock function with given fields: tagID
-func (_m *ITagRepository) Delete(tagID uint) error {
-	ret := _m.Called(tagID)
+// CreateBatch provides a mock function with given fields: ctx, tags, batchSize, audit
+func (_m *ITagRepository) CreateBatch(ctx context.Context, tags []models.Tag, batchSize int, audit models.AuditLog) ([]models.Tag, error) {
+	ret := _m.Called(ctx, tags, batchSize, audit)
+
+	var r0 []models.Tag
+	if rf, ok := ret.Get(0).(func(context.Context, []models.Tag, int, models.AuditLog) []models.Tag); ok {
+		r0 = rf(ctx, tags, batchSize, audit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Tag)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, []models.Tag, int, models.AuditLog) error); ok {
+		r1 = rf(ctx, tags, batchSize, audit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Delete provides a mock function with given fields: ctx, tagID, audit
+func (_m *ITagRepository) Delete(ctx context.Context, tagID uint, audit models.AuditLog) error {
+	ret := _m.Called(ctx, tagID, audit)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(uint) error); ok {
-		r0 = rf(tagID)
+	if rf, ok := ret.Get(0).(func(context.Context, uint, models.AuditLog) error); ok {
+		r0 = rf(ctx, tagID, audit)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -48,13 +73,13 @@ func (_m *ITagRepository) Delete(tagID uint) error {
 	return r0
 }
 
-// List provides a mock function with given fields:
-func (_m *ITagRepository) List() ([]models.Tag, error) {
-	ret := _m.Called()
+// FindByIDs provides a mock function with given fields: ctx, tagIDs
+func (_m *ITagRepository) FindByIDs(ctx context.Context, tagIDs []uint) ([]models.Tag, error) {
+	ret := _m.Called(ctx, tagIDs)
 
 	var r0 []models.Tag
-	if rf, ok := ret.Get(0).(func() []models.Tag); ok {
-		r0 = rf()
+	if rf, ok := ret.Get(0).(func(context.Context, []uint) []models.Tag); ok {
+		r0 = rf(ctx, tagIDs)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]models.Tag)
@@ -62,8 +87,73 @@ func (_m *ITagRepository) List() ([]models.Tag, error) {
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func() error); ok {
-		r1 = rf()
+	if rf, ok := ret.Get(1).(func(context.Context, []uint) error); ok {
+		r1 = rf(ctx, tagIDs)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// List provides a mock function with given fields: ctx
+func (_m *ITagRepository) List(ctx context.Context) ([]models.Tag, error) {
+	ret := _m.Called(ctx)
+
+	var r0 []models.Tag
+	if rf, ok := ret.Get(0).(func(context.Context) []models.Tag); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Tag)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Merge provides a mock function with given fields: ctx, targetID, sourceIDs, audit
+func (_m *ITagRepository) Merge(ctx context.Context, targetID uint, sourceIDs []uint, audit models.AuditLog) (models.Tag, error) {
+	ret := _m.Called(ctx, targetID, sourceIDs, audit)
+
+	var r0 models.Tag
+	if rf, ok := ret.Get(0).(func(context.Context, uint, []uint, models.AuditLog) models.Tag); ok {
+		r0 = rf(ctx, targetID, sourceIDs, audit)
+	} else {
+		r0 = ret.Get(0).(models.Tag)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, uint, []uint, models.AuditLog) error); ok {
+		r1 = rf(ctx, targetID, sourceIDs, audit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Restore provides a mock function with given fields: ctx, tagID, audit
+func (_m *ITagRepository) Restore(ctx context.Context, tagID uint, audit models.AuditLog) (models.Tag, error) {
+	ret := _m.Called(ctx, tagID, audit)
+
+	var r0 models.Tag
+	if rf, ok := ret.Get(0).(func(context.Context, uint, models.AuditLog) models.Tag); ok {
+		r0 = rf(ctx, tagID, audit)
+	} else {
+		r0 = ret.Get(0).(models.Tag)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, uint, models.AuditLog) error); ok {
+		r1 = rf(ctx, tagID, audit)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -71,20 +161,20 @@ func (_m *ITagRepository) List() ([]models.Tag, error) {
 	return r0, r1
 }
 
-// Update provides a mock function with given fields: tagID, tag
-func (_m *ITagRepository) Update(tagID uint, tag models.Tag) (models.Tag, error) {
-	ret := _m.Called(tagID, tag)
+// Update provides a mock function with given fields: ctx, tagID, tag, audit
+func (_m *ITagRepository) Update(ctx context.Context, tagID uint, tag models.Tag, audit models.AuditLog) (models.Tag, error) {
+	ret := _m.Called(ctx, tagID, tag, audit)
 
 	var r0 models.Tag
-	if rf, ok := ret.Get(0).(func(uint, models.Tag) models.Tag); ok {
-		r0 = rf(tagID, tag)
+	if rf, ok := ret.Get(0).(func(context.Context, uint, models.Tag, models.AuditLog) models.Tag); ok {
+		r0 = rf(ctx, tagID, tag, audit)
 	} else {
 		r0 = ret.Get(0).(models.Tag)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(uint, models.Tag) error); ok {
-		r1 = rf(tagID, tag)
+	if rf, ok := ret.Get(1).(func(context.Context, uint, models.Tag, models.AuditLog) error); ok {
+		r1 = rf(ctx, tagID, tag, audit)
 	} else {
 		r1 = ret.Error(1)
 	}
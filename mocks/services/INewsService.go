@@ -3,6 +3,8 @@
 package mocks
 
 import (
+	context "context"
+
 	models "news-topic-api/models"
 
 	mock "github.com/stretchr/testify/mock"
@@ -13,20 +15,43 @@ type INewsService struct {
 	mock.Mock
 }
 
-// Create provides a mock function with given fields: news
-func (_m *INewsService) Create(news models.News) (models.News, error) {
-	ret := _m.Called(news)
+// CountByStatus provides a mock function with given fields: ctx
+func (_m *INewsService) CountByStatus(ctx context.Context) (map[string]int64, error) {
+	ret := _m.Called(ctx)
+
+	var r0 map[string]int64
+	if rf, ok := ret.Get(0).(func(context.Context) map[string]int64); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]int64)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Create provides a mock function with given fields: ctx, news, meta
+func (_m *INewsService) Create(ctx context.Context, news models.News, meta models.AuditMeta) (models.News, error) {
+	ret := _m.Called(ctx, news, meta)
 
 	var r0 models.News
-	if rf, ok := ret.Get(0).(func(models.News) models.News); ok {
-		r0 = rf(news)
+	if rf, ok := ret.Get(0).(func(context.Context, models.News, models.AuditMeta) models.News); ok {
+		r0 = rf(ctx, news, meta)
 	} else {
 		r0 = ret.Get(0).(models.News)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(models.News) error); ok {
-		r1 = rf(news)
+	if rf, ok := ret.Get(1).(func(context.Context, models.News, models.AuditMeta) error); ok {
+		r1 = rf(ctx, news, meta)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -34,13 +59,13 @@ func (_m *INewsService) Create(news models.News) (models.News, error) {
 	return r0, r1
 }
 
-// Delete provides a mock function with given fields: newsID
-func (_m *INewsService) Delete(newsID uint) error {
-	ret := _m.Called(newsID)
+// Delete provides a mock function with given fields: ctx, newsID, meta
+func (_m *INewsService) Delete(ctx context.Context, newsID uint, meta models.AuditMeta) error {
+	ret := _m.Called(ctx, newsID, meta)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(uint) error); ok {
-		r0 = rf(newsID)
+	if rf, ok := ret.Get(0).(func(context.Context, uint, models.AuditMeta) error); ok {
+		r0 = rf(ctx, newsID, meta)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -48,20 +73,20 @@ func (_m *INewsService) Delete(newsID uint) error {
 	return r0
 }
 
-// GetDetail provides a mock function with given fields: newsID
-func (_m *INewsService) GetDetail(newsID uint) (models.News, error) {
-	ret := _m.Called(newsID)
+// GetDetail provides a mock function with given fields: ctx, newsID
+func (_m *INewsService) GetDetail(ctx context.Context, newsID uint) (models.News, error) {
+	ret := _m.Called(ctx, newsID)
 
 	var r0 models.News
-	if rf, ok := ret.Get(0).(func(uint) models.News); ok {
-		r0 = rf(newsID)
+	if rf, ok := ret.Get(0).(func(context.Context, uint) models.News); ok {
+		r0 = rf(ctx, newsID)
 	} else {
 		r0 = ret.Get(0).(models.News)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(uint) error); ok {
-		r1 = rf(newsID)
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, newsID)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -69,20 +94,41 @@ func (_m *INewsService) GetDetail(newsID uint) (models.News, error) {
 	return r0, r1
 }
 
-// List provides a mock function with given fields: queryParams
-func (_m *INewsService) List(queryParams map[string]string) (models.NewsList, error) {
-	ret := _m.Called(queryParams)
+// List provides a mock function with given fields: ctx, queryParams
+func (_m *INewsService) List(ctx context.Context, queryParams map[string]string) (models.NewsList, error) {
+	ret := _m.Called(ctx, queryParams)
 
 	var r0 models.NewsList
-	if rf, ok := ret.Get(0).(func(map[string]string) models.NewsList); ok {
-		r0 = rf(queryParams)
+	if rf, ok := ret.Get(0).(func(context.Context, map[string]string) models.NewsList); ok {
+		r0 = rf(ctx, queryParams)
 	} else {
 		r0 = ret.Get(0).(models.NewsList)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(map[string]string) error); ok {
-		r1 = rf(queryParams)
+	if rf, ok := ret.Get(1).(func(context.Context, map[string]string) error); ok {
+		r1 = rf(ctx, queryParams)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Restore provides a mock function with given fields: ctx, newsID, meta
+func (_m *INewsService) Restore(ctx context.Context, newsID uint, meta models.AuditMeta) (models.News, error) {
+	ret := _m.Called(ctx, newsID, meta)
+
+	var r0 models.News
+	if rf, ok := ret.Get(0).(func(context.Context, uint, models.AuditMeta) models.News); ok {
+		r0 = rf(ctx, newsID, meta)
+	} else {
+		r0 = ret.Get(0).(models.News)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, uint, models.AuditMeta) error); ok {
+		r1 = rf(ctx, newsID, meta)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -90,20 +136,20 @@ func (_m *INewsService) List(queryParams map[string]string) (models.NewsList, er
 	return r0, r1
 }
 
-// Update provides a mock function with given fields: newsID, news
-func (_m *INewsService) Update(newsID uint, news models.News) (models.News, error) {
-	ret := _m.Called(newsID, news)
+// Update provides a mock function with given fields: ctx, newsID, news, meta
+func (_m *INewsService) Update(ctx context.Context, newsID uint, news models.News, meta models.AuditMeta) (models.News, error) {
+	ret := _m.Called(ctx, newsID, news, meta)
 
 	var r0 models.News
-	if rf, ok := ret.Get(0).(func(uint, models.News) models.News); ok {
-		r0 = rf(newsID, news)
+	if rf, ok := ret.Get(0).(func(context.Context, uint, models.News, models.AuditMeta) models.News); ok {
+		r0 = rf(ctx, newsID, news, meta)
 	} else {
 		r0 = ret.Get(0).(models.News)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(uint, models.News) error); ok {
-		r1 = rf(newsID, news)
+	if rf, ok := ret.Get(1).(func(context.Context, uint, models.News, models.AuditMeta) error); ok {
+		r1 = rf(ctx, newsID, news, meta)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -3,6 +3,8 @@
 package mocks
 
 import (
+	context "context"
+
 	models "news-topic-api/models"
 
 	mock "github.com/stretchr/testify/mock"
@@ -13,20 +15,20 @@ type ITagService struct {
 	mock.Mock
 }
 
-// Create provides a mock function with given fields: tag
-func (_m *ITagService) Create(tag models.Tag) (models.Tag, error) {
-	ret := _m.Called(tag)
+// Create provides a mock function with given fields: ctx, tag, meta
+func (_m *ITagService) Create(ctx context.Context, tag models.Tag, meta models.AuditMeta) (models.Tag, error) {
+	ret := _m.Called(ctx, tag, meta)
 
 	var r0 models.Tag
-	if rf, ok := ret.Get(0).(func(models.Tag) models.Tag); ok {
-		r0 = rf(tag)
+	if rf, ok := ret.Get(0).(func(context.Context, models.Tag, models.AuditMeta) models.Tag); ok {
+		r0 = rf(ctx, tag, meta)
 	} else {
 		r0 = ret.Get(0).(models.Tag)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(models.Tag) error); ok {
-		r1 = rf(tag)
+	if rf, ok := ret.Get(1).(func(context.Context, models.Tag, models.AuditMeta) error); ok {
+		r1 = rf(ctx, tag, meta)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -34,13 +36,13 @@ func (_m *ITagService) Create(tag models.Tag) (models.Tag, error) {
 	return r0, r1
 }
 
-// Delete provides a mock function with given fields: tagID
-func (_m *ITagService) Delete(tagID uint) error {
-	ret := _m.Called(tagID)
+// Delete provides a mock function with given fields: ctx, tagID, meta
+func (_m *ITagService) Delete(ctx context.Context, tagID uint, meta models.AuditMeta) error {
+	ret := _m.Called(ctx, tagID, meta)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(uint) error); ok {
-		r0 = rf(tagID)
+	if rf, ok := ret.Get(0).(func(context.Context, uint, models.AuditMeta) error); ok {
+		r0 = rf(ctx, tagID, meta)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -48,20 +50,62 @@ func (_m *ITagService) Delete(tagID uint) error {
 	return r0
 }
 
-// List provides a mock function with given fields:
-func (_m *ITagService) List() (models.TagsList, error) {
-	ret := _m.Called()
+// List provides a mock function with given fields: ctx
+func (_m *ITagService) List(ctx context.Context) (models.TagsList, error) {
+	ret := _m.Called(ctx)
 
 	var r0 models.TagsList
-	if rf, ok := ret.Get(0).(func() models.TagsList); ok {
-		r0 = rf()
+	if rf, ok := ret.Get(0).(func(context.Context) models.TagsList); ok {
+		r0 = rf(ctx)
 	} else {
 		r0 = ret.Get(0).(models.TagsList)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func() error); ok {
-		r1 = rf()
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Merge provides a mock function with given fields: ctx, targetID, sourceIDs, meta
+func (_m *ITagService) Merge(ctx context.Context, targetID uint, sourceIDs []uint, meta models.AuditMeta) (models.Tag, error) {
+	ret := _m.Called(ctx, targetID, sourceIDs, meta)
+
+	var r0 models.Tag
+	if rf, ok := ret.Get(0).(func(context.Context, uint, []uint, models.AuditMeta) models.Tag); ok {
+		r0 = rf(ctx, targetID, sourceIDs, meta)
+	} else {
+		r0 = ret.Get(0).(models.Tag)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, uint, []uint, models.AuditMeta) error); ok {
+		r1 = rf(ctx, targetID, sourceIDs, meta)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Restore provides a mock function with given fields: ctx, tagID, meta
+func (_m *ITagService) Restore(ctx context.Context, tagID uint, meta models.AuditMeta) (models.Tag, error) {
+	ret := _m.Called(ctx, tagID, meta)
+
+	var r0 models.Tag
+	if rf, ok := ret.Get(0).(func(context.Context, uint, models.AuditMeta) models.Tag); ok {
+		r0 = rf(ctx, tagID, meta)
+	} else {
+		r0 = ret.Get(0).(models.Tag)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, uint, models.AuditMeta) error); ok {
+		r1 = rf(ctx, tagID, meta)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -69,20 +113,20 @@ func (_m *ITagService) List() (models.TagsList, error) {
 	return r0, r1
 }
 
-// Update provides a mock function with given fields: tagID, tag
-func (_m *ITagService) Update(tagID uint, tag models.Tag) (models.Tag, error) {
-	ret := _m.Called(tagID, tag)
+// Update provides a mock function with given fields: ctx, tagID, tag, meta
+func (_m *ITagService) Update(ctx context.Context, tagID uint, tag models.Tag, meta models.AuditMeta) (models.Tag, error) {
+	ret := _m.Called(ctx, tagID, tag, meta)
 
 	var r0 models.Tag
-	if rf, ok := ret.Get(0).(func(uint, models.Tag) models.Tag); ok {
-		r0 = rf(tagID, tag)
+	if rf, ok := ret.Get(0).(func(context.Context, uint, models.Tag, models.AuditMeta) models.Tag); ok {
+		r0 = rf(ctx, tagID, tag, meta)
 	} else {
 		r0 = ret.Get(0).(models.Tag)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(uint, models.Tag) error); ok {
-		r1 = rf(tagID, tag)
+	if rf, ok := ret.Get(1).(func(context.Context, uint, models.Tag, models.AuditMeta) error); ok {
+		r1 = rf(ctx, tagID, tag, meta)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -0,0 +1,63 @@
+package models
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+)
+
+//API key scopes that can be granted to machine clients
+const (
+	ScopeNewsRead    = "news:read"
+	ScopeNewsWrite   = "news:write"
+	//ScopeNewsPublish lets a key act as an editor on news: publish, edit any news and delete
+	ScopeNewsPublish = "news:publish"
+	ScopeTagAdmin    = "tag:admin"
+	ScopeAuthorAdmin = "author:admin"
+	ScopeAuditRead   = "audit:read"
+	//ScopeAPIKeyAdmin is never grantable, so api keys can not manage other keys
+	ScopeAPIKeyAdmin = "apikey:admin"
+)
+
+//GrantableScopes lists the scopes an api key may be minted with
+var GrantableScopes = []string{ScopeNewsRead, ScopeNewsWrite, ScopeNewsPublish, ScopeTagAdmin, ScopeAuthorAdmin, ScopeAuditRead}
+
+//APIKey is a hashed credential used by non-interactive clients
+type APIKey struct {
+	gorm.Model
+	Name string `gorm:"not null" json:"name"`
+	Owner string `gorm:"not null" json:"owner"`
+	Prefix string `gorm:"not null;uniqueIndex" json:"prefix"`
+	KeyHash string `gorm:"not null" json:"-"`
+	Scopes pq.StringArray `gorm:"type:text[];not null" json:"scopes"`
+	ExpiresAt *time.Time `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	RevokedAt *time.Time `json:"revoked_at"`
+}
+
+//TableName setup entities name on db
+func (APIKey) TableName() string {
+	return "api_keys"
+}
+
+//HasScope reports whether the key was granted the given scope
+func (a APIKey) HasScope(scope string) bool {
+	for _, granted := range a.Scopes {
+		if granted == scope {
+			return true
+		}
+	}
+	return false
+}
+
+//APIKeysList ...
+type APIKeysList struct {
+	Data []APIKey `json:"data"`
+}
+
+//APIKeySecret carries the plaintext key, which is only shown once on mint and rotate
+type APIKeySecret struct {
+	APIKey APIKey `json:"api_key"`
+	Key string `json:"key"`
+}
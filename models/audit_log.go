@@ -0,0 +1,99 @@
+package models
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"time"
+)
+
+//Audited actions
+const (
+	AuditActionCreate  = "create"
+	AuditActionUpdate  = "update"
+	AuditActionDelete  = "delete"
+	AuditActionMerge   = "merge"
+	AuditActionRestore = "restore"
+)
+
+//Audited entity types
+const (
+	AuditEntityNews = "news"
+	AuditEntityTag  = "tag"
+)
+
+//JSON is a raw json document stored in a jsonb column
+type JSON []byte
+
+//Value ...
+func (j JSON) Value() (driver.Value, error) {
+	if len(j) == 0 {
+		return nil, nil
+	}
+	return string(j), nil
+}
+
+//Scan ...
+func (j *JSON) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*j = nil
+	case []byte:
+		*j = append(JSON{}, v...)
+	case string:
+		*j = JSON(v)
+	default:
+		return fmt.Errorf("unsupported json value %T", value)
+	}
+	return nil
+}
+
+//MarshalJSON ...
+func (j JSON) MarshalJSON() ([]byte, error) {
+	if len(j) == 0 {
+		return []byte("null"), nil
+	}
+	return j, nil
+}
+
+//UnmarshalJSON ...
+func (j *JSON) UnmarshalJSON(data []byte) error {
+	*j = append(JSON{}, data...)
+	return nil
+}
+
+//AuditMeta identifies who performed a mutation and from which request
+type AuditMeta struct {
+	Actor string
+	RequestID string
+	IP string
+}
+
+//AuditLog is an immutable record of a single mutation
+type AuditLog struct {
+	ID uint `gorm:"primarykey" json:"id"`
+	Timestamp time.Time `gorm:"not null;index" json:"timestamp"`
+	Actor string `gorm:"not null;index" json:"actor"`
+	Action string `gorm:"not null" json:"action"`
+	EntityType string `gorm:"not null;index:idx_audit_logs_entity" json:"entity_type"`
+	EntityID uint `gorm:"not null;index:idx_audit_logs_entity" json:"entity_id"`
+	Before JSON `gorm:"type:jsonb" json:"before"`
+	After JSON `gorm:"type:jsonb" json:"after"`
+	RequestID string `json:"request_id"`
+	IP string `json:"ip"`
+}
+
+//NewAuditLog starts an audit entry for an action, the repository completes it with the entity state
+func NewAuditLog(meta AuditMeta, action string, entityType string) AuditLog {
+	return AuditLog{
+		Actor: meta.Actor,
+		Action: action,
+		EntityType: entityType,
+		RequestID: meta.RequestID,
+		IP: meta.IP,
+	}
+}
+
+//AuditLogsList ...
+type AuditLogsList struct {
+	Data []AuditLog `json:"data"`
+}
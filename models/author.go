@@ -0,0 +1,17 @@
+package models
+import (
+	"gorm.io/gorm"
+)
+
+//Author ...
+type Author struct {
+	gorm.Model
+	Name string `gorm:"not null" json:"name"`
+	Bio string `json:"bio"`
+	Avatar string `json:"avatar"`
+}
+
+//AuthorsList ...
+type AuthorsList struct {
+	Data []Author `json:"data"`
+}
@@ -3,6 +3,12 @@ import (
 	"gorm.io/gorm"
 )
 
+//News statuses
+const (
+	StatusDraft = "draft"
+	StatusPublished = "published"
+)
+
 //News ...
 type News struct {
 	gorm.Model
@@ -11,8 +17,10 @@ type News struct {
 	Summary string `gorm:"not null" json:"summary"`
 	Content string `gorm:"not null" json:"content"`
 	Tags []Tag `gorm:"many2many:news_tag;not null;constraint:OnUpdate:CASCADE,OnDelete:SET NULL;" json:"tags"`
+	Authors []Author `gorm:"many2many:news_author;constraint:OnUpdate:CASCADE,OnDelete:SET NULL;" json:"authors"`
 	Topic string `gorm:"not null" json:"topic"`
 	Status string `gorm:"not null" json:"status"`
+	CreatedBy string `gorm:"index" json:"created_by"`
 }
 
 //TableName setup entities name on db
@@ -0,0 +1,32 @@
+package models
+
+//Editorial roles, each role is granted everything the previous one is
+const (
+	RoleReader = "reader"
+	RoleWriter = "writer"
+	RoleEditor = "editor"
+	RoleAdmin  = "admin"
+)
+
+//Principal is the authenticated caller attached to a request context
+type Principal struct {
+	Subject string `json:"subject"`
+	Name string `json:"name"`
+	Roles []string `json:"roles"`
+	Scopes []string `json:"scopes"`
+	AuthMethod string `json:"auth_method"`
+}
+
+//HasScope reports whether an api key principal was granted the scope.
+//Interactive principals are not scoped and always pass this check.
+func (p Principal) HasScope(scope string) bool {
+	if p.AuthMethod != "api_key" {
+		return true
+	}
+	for _, granted := range p.Scopes {
+		if granted == scope {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,27 @@
+package policies
+
+import (
+	"news-topic-api/models"
+)
+
+//IAPIKeyPolicy interface for api key policy
+type IAPIKeyPolicy interface {
+	CanManage(principal models.Principal) error
+}
+
+//APIKeyPolicy decides who may mint, rotate and revoke api keys
+type APIKeyPolicy struct {
+}
+
+//InitAPIKeyPolicy initializes an api key policy
+func InitAPIKeyPolicy() IAPIKeyPolicy {
+	return new(APIKeyPolicy)
+}
+
+//CanManage only admins may manage api keys, api keys can never manage other keys
+func (a APIKeyPolicy) CanManage(principal models.Principal) error {
+	if isMachineClient(principal) {
+		return forbidden("api keys can not manage api keys")
+	}
+	return requireRole(principal, models.RoleAdmin, models.ScopeAPIKeyAdmin, "managing api keys")
+}
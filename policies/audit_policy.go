@@ -0,0 +1,24 @@
+package policies
+
+import (
+	"news-topic-api/models"
+)
+
+//IAuditPolicy interface for audit policy
+type IAuditPolicy interface {
+	CanView(principal models.Principal) error
+}
+
+//AuditPolicy decides who may read the audit log
+type AuditPolicy struct {
+}
+
+//InitAuditPolicy initializes an audit policy
+func InitAuditPolicy() IAuditPolicy {
+	return new(AuditPolicy)
+}
+
+//CanView only admins may read the audit log
+func (a AuditPolicy) CanView(principal models.Principal) error {
+	return requireRole(principal, models.RoleAdmin, models.ScopeAuditRead, "reading the audit log")
+}
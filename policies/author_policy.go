@@ -0,0 +1,24 @@
+package policies
+
+import (
+	"news-topic-api/models"
+)
+
+//IAuthorPolicy interface for author policy
+type IAuthorPolicy interface {
+	CanManage(principal models.Principal) error
+}
+
+//AuthorPolicy decides who may create, update and delete authors
+type AuthorPolicy struct {
+}
+
+//InitAuthorPolicy initializes an author policy
+func InitAuthorPolicy() IAuthorPolicy {
+	return new(AuthorPolicy)
+}
+
+//CanManage only editors may manage the bylines available to news
+func (a AuthorPolicy) CanManage(principal models.Principal) error {
+	return requireRole(principal, models.RoleEditor, models.ScopeAuthorAdmin, "managing authors")
+}
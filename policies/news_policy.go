@@ -0,0 +1,81 @@
+package policies
+
+import (
+	"context"
+	"news-topic-api/models"
+	"news-topic-api/services"
+)
+
+//INewsPolicy interface for news policy
+type INewsPolicy interface {
+	CanWrite(principal models.Principal) error
+	CanCreate(principal models.Principal, news models.News) error
+	CanUpdate(ctx context.Context, principal models.Principal, newsID uint, news models.News) error
+	CanDelete(principal models.Principal, newsID uint) error
+	CanRestore(principal models.Principal, newsID uint) error
+}
+
+//NewsPolicy decides which editorial operations a principal may perform on news
+type NewsPolicy struct {
+	newsService services.INewsService
+}
+
+//InitNewsPolicy initializes a news policy given the news service used to look up existing news
+func InitNewsPolicy(newsService services.INewsService) INewsPolicy {
+	newsPolicy := new(NewsPolicy)
+	newsPolicy.newsService = newsService
+	return newsPolicy
+}
+
+//CanWrite only writers and api keys with the news:write scope may create or edit news, it needs no body
+//so callers are turned away before their body is read. CanCreate and CanUpdate check the rest
+func (n NewsPolicy) CanWrite(principal models.Principal) error {
+	return requireRole(principal, models.RoleWriter, models.ScopeNewsWrite, "writing news")
+}
+
+//CanCreate writers may create drafts, editors may create news with any status.
+//Api keys need the news:write scope for drafts and the news:publish scope for any other status
+func (n NewsPolicy) CanCreate(principal models.Principal, news models.News) error {
+	if err := requireRole(principal, models.RoleWriter, models.ScopeNewsWrite, "creating news"); err != nil {
+		return err
+	}
+	if news.Status != models.StatusDraft && !actsAs(principal, models.RoleEditor, models.ScopeNewsPublish) {
+		return forbidden("only editors can create news with status %q", news.Status)
+	}
+	return nil
+}
+
+//CanUpdate writers may only edit their own drafts and keep them as drafts, editors may edit any news.
+//Api keys with the news:write scope are held to the writer rules, news:publish lifts them
+func (n NewsPolicy) CanUpdate(ctx context.Context, principal models.Principal, newsID uint, news models.News) error {
+	if err := requireRole(principal, models.RoleWriter, models.ScopeNewsWrite, "editing news"); err != nil {
+		return err
+	}
+	if actsAs(principal, models.RoleEditor, models.ScopeNewsPublish) {
+		return nil
+	}
+	existing, err := n.newsService.GetDetail(ctx, newsID)
+	if err != nil {
+		return err
+	}
+	if existing.CreatedBy != principal.Subject {
+		return forbidden("writers can only edit their own news")
+	}
+	if existing.Status != models.StatusDraft {
+		return forbidden("writers can only edit drafts, this news is %q", existing.Status)
+	}
+	if news.Status != models.StatusDraft {
+		return forbidden("only editors can change the status of news to %q", news.Status)
+	}
+	return nil
+}
+
+//CanDelete only editors and api keys with the news:publish scope may delete news
+func (n NewsPolicy) CanDelete(principal models.Principal, newsID uint) error {
+	return requireRole(principal, models.RoleEditor, models.ScopeNewsPublish, "deleting news")
+}
+
+//CanRestore only those who may delete news may bring it back
+func (n NewsPolicy) CanRestore(principal models.Principal, newsID uint) error {
+	return requireRole(principal, models.RoleEditor, models.ScopeNewsPublish, "restoring news")
+}
@@ -0,0 +1,134 @@
+package policies
+
+import (
+	"context"
+	"fmt"
+	"news-topic-api/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	mockServices "news-topic-api/mocks/services"
+)
+
+func getMockPrincipal(subject string, roles ...string) models.Principal {
+	return models.Principal{Subject: subject, Roles: roles, AuthMethod: "jwt"}
+}
+
+func getMockDraft(createdBy string) models.News {
+	return models.News{Title: "Harga bitcoin anjlok", Status: models.StatusDraft, CreatedBy: createdBy}
+}
+
+func TestHasRoleHigherRoleIncludesLowerOnes(t *testing.T) {
+	admin := getMockPrincipal("1", models.RoleAdmin)
+	assert.True(t, HasRole(admin, models.RoleWriter))
+	assert.True(t, HasRole(admin, models.RoleEditor))
+	reader := getMockPrincipal("2")
+	assert.True(t, HasRole(reader, models.RoleReader))
+	assert.False(t, HasRole(reader, models.RoleWriter))
+}
+
+func TestCanCreateReaderIsForbidden(t *testing.T) {
+	newsPolicy := InitNewsPolicy(new(mockServices.INewsService))
+	err := newsPolicy.CanCreate(getMockPrincipal("2", models.RoleReader), getMockDraft("2"))
+	assert.IsType(t, ForbiddenError{}, err)
+}
+
+func TestCanCreatePublishedAsEditorIsAllowed(t *testing.T) {
+	newsPolicy := InitNewsPolicy(new(mockServices.INewsService))
+	news := getMockDraft("3")
+	news.Status = models.StatusPublished
+	assert.Nil(t, newsPolicy.CanCreate(getMockPrincipal("3", models.RoleEditor), news))
+}
+
+func TestCanUpdatePublishedNewsAsWriterIsForbidden(t *testing.T) {
+	mockedNewsService := new(mockServices.INewsService)
+	existing := getMockDraft("7")
+	existing.Status = models.StatusPublished
+	mockedNewsService.On("GetDetail", mock.Anything, uint(1)).Return(existing, nil)
+	newsPolicy := InitNewsPolicy(mockedNewsService)
+	err := newsPolicy.CanUpdate(context.Background(), getMockPrincipal("7", models.RoleWriter), uint(1), getMockDraft("7"))
+	assert.IsType(t, ForbiddenError{}, err)
+}
+
+func TestCanUpdatePublishingOwnDraftAsWriterIsForbidden(t *testing.T) {
+	mockedNewsService := new(mockServices.INewsService)
+	mockedNewsService.On("GetDetail", mock.Anything, uint(1)).Return(getMockDraft("7"), nil)
+	newsPolicy := InitNewsPolicy(mockedNewsService)
+	changes := getMockDraft("7")
+	changes.Status = models.StatusPublished
+	err := newsPolicy.CanUpdate(context.Background(), getMockPrincipal("7", models.RoleWriter), uint(1), changes)
+	assert.IsType(t, ForbiddenError{}, err)
+}
+
+func TestCanUpdateMissingNewsReturnLookupError(t *testing.T) {
+	mockedNewsService := new(mockServices.INewsService)
+	mockedNewsService.On("GetDetail", mock.Anything, uint(1)).Return(models.News{}, fmt.Errorf("record not found"))
+	newsPolicy := InitNewsPolicy(mockedNewsService)
+	err := newsPolicy.CanUpdate(context.Background(), getMockPrincipal("7", models.RoleWriter), uint(1), getMockDraft("7"))
+	assert.NotNil(t, err, "There should be an error")
+	assert.NotEqual(t, ForbiddenError{}, err)
+}
+
+func TestCanUpdateAnyNewsAsEditorSkipsLookup(t *testing.T) {
+	mockedNewsService := new(mockServices.INewsService)
+	newsPolicy := InitNewsPolicy(mockedNewsService)
+	assert.Nil(t, newsPolicy.CanUpdate(context.Background(), getMockPrincipal("3", models.RoleEditor), uint(1), getMockDraft("7")))
+	mockedNewsService.AssertNotCalled(t, "GetDetail", mock.Anything, uint(1))
+}
+
+func getMockAPIKeyPrincipal(subject string, scopes ...string) models.Principal {
+	return models.Principal{Subject: subject, AuthMethod: "api_key", Scopes: scopes}
+}
+
+func TestCanDeleteAsWritingAPIKeyIsForbidden(t *testing.T) {
+	newsPolicy := InitNewsPolicy(new(mockServices.INewsService))
+	err := newsPolicy.CanDelete(getMockAPIKeyPrincipal("apikey:1", models.ScopeNewsWrite), uint(1))
+	assert.IsType(t, ForbiddenError{}, err)
+	assert.Nil(t, newsPolicy.CanDelete(getMockAPIKeyPrincipal("apikey:1", models.ScopeNewsPublish), uint(1)))
+}
+
+func TestCanCreatePublishedAsWritingAPIKeyIsForbidden(t *testing.T) {
+	newsPolicy := InitNewsPolicy(new(mockServices.INewsService))
+	news := getMockDraft("apikey:1")
+	news.Status = models.StatusPublished
+	err := newsPolicy.CanCreate(getMockAPIKeyPrincipal("apikey:1", models.ScopeNewsWrite), news)
+	assert.IsType(t, ForbiddenError{}, err)
+	assert.Nil(t, newsPolicy.CanCreate(getMockAPIKeyPrincipal("apikey:1", models.ScopeNewsWrite), getMockDraft("apikey:1")))
+	assert.Nil(t, newsPolicy.CanCreate(getMockAPIKeyPrincipal("apikey:1", models.ScopeNewsWrite, models.ScopeNewsPublish), news))
+}
+
+func TestCanCreateAsReadingAPIKeyIsForbidden(t *testing.T) {
+	newsPolicy := InitNewsPolicy(new(mockServices.INewsService))
+	err := newsPolicy.CanCreate(getMockAPIKeyPrincipal("apikey:1", models.ScopeNewsRead), getMockDraft("apikey:1"))
+	assert.IsType(t, ForbiddenError{}, err)
+}
+
+func TestCanUpdatePublishingAsWritingAPIKeyIsForbidden(t *testing.T) {
+	mockedNewsService := new(mockServices.INewsService)
+	mockedNewsService.On("GetDetail", mock.Anything, uint(1)).Return(getMockDraft("apikey:1"), nil)
+	newsPolicy := InitNewsPolicy(mockedNewsService)
+	principal := getMockAPIKeyPrincipal("apikey:1", models.ScopeNewsWrite)
+	assert.Nil(t, newsPolicy.CanUpdate(context.Background(), principal, uint(1), getMockDraft("apikey:1")))
+	changes := getMockDraft("apikey:1")
+	changes.Status = models.StatusPublished
+	err := newsPolicy.CanUpdate(context.Background(), principal, uint(1), changes)
+	assert.IsType(t, ForbiddenError{}, err)
+	assert.Nil(t, newsPolicy.CanUpdate(context.Background(), getMockAPIKeyPrincipal("apikey:2", models.ScopeNewsWrite, models.ScopeNewsPublish), uint(1), changes))
+}
+
+func TestAPIKeyPoliciesShouldFollowScopes(t *testing.T) {
+	tagAdmin := getMockAPIKeyPrincipal("apikey:1", models.ScopeTagAdmin)
+	assert.Nil(t, InitTagPolicy().CanManage(tagAdmin))
+	assert.IsType(t, ForbiddenError{}, InitAuthorPolicy().CanManage(tagAdmin))
+	assert.IsType(t, ForbiddenError{}, InitAuditPolicy().CanView(tagAdmin))
+	assert.IsType(t, ForbiddenError{}, InitNewsPolicy(new(mockServices.INewsService)).CanDelete(tagAdmin, uint(1)), "tag:admin should not act as an admin on news")
+	assert.Nil(t, InitAuthorPolicy().CanManage(getMockAPIKeyPrincipal("apikey:2", models.ScopeAuthorAdmin)))
+	assert.Nil(t, InitAuditPolicy().CanView(getMockAPIKeyPrincipal("apikey:3", models.ScopeAuditRead)))
+}
+
+func TestAPIKeyPolicyMachineClientIsForbidden(t *testing.T) {
+	principal := models.Principal{Subject: "apikey:1", AuthMethod: "api_key"}
+	assert.IsType(t, ForbiddenError{}, InitAPIKeyPolicy().CanManage(principal))
+	assert.Nil(t, InitAPIKeyPolicy().CanManage(getMockPrincipal("1", models.RoleAdmin)))
+}
@@ -0,0 +1,62 @@
+package policies
+
+import (
+	"fmt"
+	"news-topic-api/models"
+)
+
+var roleRanks = map[string]int{
+	models.RoleReader: 1,
+	models.RoleWriter: 2,
+	models.RoleEditor: 3,
+	models.RoleAdmin:  4,
+}
+
+//ForbiddenError is returned when a principal is not allowed to perform an action
+type ForbiddenError struct {
+	Reason string
+}
+
+func (f ForbiddenError) Error() string {
+	return f.Reason
+}
+
+func forbidden(format string, args ...interface{}) error {
+	return ForbiddenError{Reason: fmt.Sprintf(format, args...)}
+}
+
+//HasRole reports whether the principal holds the role or a higher one.
+//Principals without any role are readers.
+func HasRole(principal models.Principal, role string) bool {
+	highest := roleRanks[models.RoleReader]
+	for _, granted := range principal.Roles {
+		if roleRanks[granted] > highest {
+			highest = roleRanks[granted]
+		}
+	}
+	return highest >= roleRanks[role]
+}
+
+//isMachineClient reports whether the principal is authorized by api key scopes instead of roles
+func isMachineClient(principal models.Principal) bool {
+	return principal.AuthMethod == "api_key"
+}
+
+//actsAs reports whether the principal may act with the role. Api keys hold no roles,
+//they act with the role only when granted the scope standing for it
+func actsAs(principal models.Principal, role string, scope string) bool {
+	if isMachineClient(principal) {
+		return principal.HasScope(scope)
+	}
+	return HasRole(principal, role)
+}
+
+func requireRole(principal models.Principal, role string, scope string, action string) error {
+	if actsAs(principal, role, scope) {
+		return nil
+	}
+	if isMachineClient(principal) {
+		return forbidden("%s requires the %s scope", action, scope)
+	}
+	return forbidden("%s requires the %s role", action, role)
+}
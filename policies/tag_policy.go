@@ -0,0 +1,24 @@
+package policies
+
+import (
+	"news-topic-api/models"
+)
+
+//ITagPolicy interface for tag policy
+type ITagPolicy interface {
+	CanManage(principal models.Principal) error
+}
+
+//TagPolicy decides who may create, update and delete tags
+type TagPolicy struct {
+}
+
+//InitTagPolicy initializes a tag policy
+func InitTagPolicy() ITagPolicy {
+	return new(TagPolicy)
+}
+
+//CanManage only admins may manage tags
+func (t TagPolicy) CanManage(principal models.Principal) error {
+	return requireRole(principal, models.RoleAdmin, models.ScopeTagAdmin, "managing tags")
+}
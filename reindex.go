@@ -0,0 +1,32 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"news-topic-api/infrastructures"
+	"strings"
+)
+
+const reindexUsage = `Usage: news-topic-api reindex [flags] [table...]
+
+Rebuilds the indexes of the given tables, every application table when none is given.
+REINDEX locks writes to each table while it runs.
+`
+
+//runReindex rebuilds the indexes of the application tables
+func runReindex(args []string) int {
+	flags := newFlagSet("reindex", reindexUsage+"\nTables: "+strings.Join(infrastructures.ReindexTables, ", ")+"\n")
+	if exitCode, ok := parseFlags(flags, args); !ok {
+		return exitCode
+	}
+	_, db, err := openDatabase()
+	if err != nil {
+		return fail(err)
+	}
+	defer infrastructures.CloseDB(db)
+	if err := infrastructures.Reindex(context.Background(), db, flags.Args()); err != nil {
+		return fail(err)
+	}
+	fmt.Println("reindexed")
+	return exitOK
+}
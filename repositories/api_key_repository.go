@@ -0,0 +1,107 @@
+package repositories
+
+import (
+	"context"
+	"gorm.io/gorm"
+	"news-topic-api/models"
+	"time"
+)
+
+//IAPIKeyRepository interface for api key repository
+type IAPIKeyRepository interface {
+	Create(ctx context.Context, apiKey models.APIKey) (models.APIKey, error)
+	GetByID(ctx context.Context, apiKeyID uint) (models.APIKey, error)
+	GetByPrefix(ctx context.Context, prefix string) (models.APIKey, error)
+	List(ctx context.Context) ([]models.APIKey, error)
+	UpdateSecret(ctx context.Context, apiKeyID uint, prefix string, keyHash string) (models.APIKey, error)
+	Revoke(ctx context.Context, apiKeyID uint, revokedAt time.Time) error
+	TouchLastUsed(ctx context.Context, apiKeyID uint, usedAt time.Time) error
+}
+
+//APIKeyRepository ...
+type APIKeyRepository struct{
+	db *gorm.DB
+}
+
+//InitAPIKeyRepository initializes an api key repository given the database handle
+func InitAPIKeyRepository(db *gorm.DB) IAPIKeyRepository {
+	repository := new(APIKeyRepository)
+	repository.db = db
+	return *repository
+}
+
+//Create ...
+func (a APIKeyRepository) Create(ctx context.Context, apiKey models.APIKey) (models.APIKey, error) {
+	db := a.db.WithContext(ctx)
+	err := db.Create(&apiKey).Error
+	return apiKey, translateError(err)
+}
+
+//GetByID ...
+func (a APIKeyRepository) GetByID(ctx context.Context, apiKeyID uint) (models.APIKey, error) {
+	var targetAPIKey models.APIKey
+	db := a.db.WithContext(ctx)
+	err := db.Where("id = ?", apiKeyID).First(&targetAPIKey).Error
+	if err != nil {
+		return models.APIKey{}, translateError(err)
+	}
+	return targetAPIKey, nil
+}
+
+//GetByPrefix retrieve the api key identified by the public prefix of its plaintext
+func (a APIKeyRepository) GetByPrefix(ctx context.Context, prefix string) (models.APIKey, error) {
+	var targetAPIKey models.APIKey
+	db := a.db.WithContext(ctx)
+	err := db.Where("prefix = ?", prefix).First(&targetAPIKey).Error
+	if err != nil {
+		return models.APIKey{}, translateError(err)
+	}
+	return targetAPIKey, nil
+}
+
+//List ...
+func (a APIKeyRepository) List(ctx context.Context) ([]models.APIKey, error) {
+	var apiKeysList []models.APIKey
+	db := a.db.WithContext(ctx)
+	err := db.Order("id DESC").Find(&apiKeysList).Error
+	if err != nil {
+		return []models.APIKey{}, translateError(err)
+	}
+	return apiKeysList, nil
+}
+
+//UpdateSecret replaces the hashed secret of an api key, keeping its scopes
+func (a APIKeyRepository) UpdateSecret(ctx context.Context, apiKeyID uint, prefix string, keyHash string) (models.APIKey, error) {
+	var targetAPIKey models.APIKey
+	db := a.db.WithContext(ctx)
+	err := db.Where("id = ?", apiKeyID).First(&targetAPIKey).Error
+	if err != nil {
+		return models.APIKey{}, translateError(err)
+	}
+	updateData := map[string]interface{} {
+		"prefix": prefix,
+		"key_hash": keyHash,
+	}
+	err = db.Model(&targetAPIKey).Updates(updateData).Error
+	if err != nil {
+		return models.APIKey{}, translateError(err)
+	}
+	return targetAPIKey, nil
+}
+
+//Revoke ...
+func (a APIKeyRepository) Revoke(ctx context.Context, apiKeyID uint, revokedAt time.Time) error {
+	var targetAPIKey models.APIKey
+	db := a.db.WithContext(ctx)
+	err := db.Where("id = ?", apiKeyID).First(&targetAPIKey).Error
+	if err != nil {
+		return translateError(err)
+	}
+	return translateError(db.Model(&targetAPIKey).Update("revoked_at", revokedAt).Error)
+}
+
+//TouchLastUsed records the last time an api key authenticated a request
+func (a APIKeyRepository) TouchLastUsed(ctx context.Context, apiKeyID uint, usedAt time.Time) error {
+	db := a.db.WithContext(ctx)
+	return translateError(db.Model(&models.APIKey{}).Where("id = ?", apiKeyID).UpdateColumn("last_used_at", usedAt).Error)
+}
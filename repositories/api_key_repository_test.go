@@ -0,0 +1,102 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"news-topic-api/models"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+const (
+	insertQueryAPIKeys = "^INSERT INTO \"api_keys\".+$"
+	getQueryAPIKeys    = "^SELECT (.+) FROM \"api_keys\".+$"
+	updateQueryAPIKeys = `^UPDATE "api_keys".*$`
+)
+
+func mockRowAPIKey() *sqlmock.Rows {
+	apiKeyFieldColumns := []string{"id", "name", "owner", "prefix", "key_hash"}
+	rows := sqlmock.NewRows(apiKeyFieldColumns)
+	rows.AddRow("1", "ingestion job", "42", "a1b2c3d4e5f6", "deadbeef")
+	return rows
+}
+
+func TestAPIKeyCreateSuccess(t *testing.T) {
+	t.Parallel()
+	db, testMock, assertion := setUpAPIKey(t)
+	testMock.ExpectQuery(insertQueryAPIKeys).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	apiKeyRepo := InitAPIKeyRepository(db)
+	_, err := apiKeyRepo.Create(context.Background(), models.APIKey{Name: "ingestion job", Scopes: []string{models.ScopeNewsRead}})
+	assertion.Nil(err, "Should be no error")
+}
+
+func TestAPIKeyGetByPrefixSuccess(t *testing.T) {
+	t.Parallel()
+	db, testMock, assertion := setUpAPIKey(t)
+	testMock.ExpectQuery(getQueryAPIKeys).WithArgs("a1b2c3d4e5f6").WillReturnRows(mockRowAPIKey())
+	apiKeyRepo := InitAPIKeyRepository(db)
+	apiKey, err := apiKeyRepo.GetByPrefix(context.Background(), "a1b2c3d4e5f6")
+	assertion.Nil(err, "Should be no error")
+	assertion.Equal("deadbeef", apiKey.KeyHash)
+}
+
+func TestAPIKeyGetByPrefixNotFoundReturnError(t *testing.T) {
+	t.Parallel()
+	db, testMock, assertion := setUpAPIKey(t)
+	testMock.ExpectQuery(getQueryAPIKeys).WillReturnError(fmt.Errorf("record not found"))
+	apiKeyRepo := InitAPIKeyRepository(db)
+	_, err := apiKeyRepo.GetByPrefix(context.Background(), "a1b2c3d4e5f6")
+	assertion.NotNil(err, "Should be an error")
+}
+
+func TestAPIKeyRevokeSuccess(t *testing.T) {
+	t.Parallel()
+	db, testMock, assertion := setUpAPIKey(t)
+	testMock.ExpectQuery(getQueryAPIKeys).WillReturnRows(mockRowAPIKey())
+	testMock.ExpectExec(updateQueryAPIKeys).WillReturnResult(sqlmock.NewResult(1, 1))
+	apiKeyRepo := InitAPIKeyRepository(db)
+	err := apiKeyRepo.Revoke(context.Background(), uint(1), time.Now())
+	assertion.Nil(err, "Should be no error")
+	assertion.Nil(testMock.ExpectationsWereMet())
+}
+
+func TestAPIKeyTouchLastUsedSuccess(t *testing.T) {
+	t.Parallel()
+	db, testMock, assertion := setUpAPIKey(t)
+	testMock.ExpectExec(updateQueryAPIKeys).WillReturnResult(sqlmock.NewResult(1, 1))
+	apiKeyRepo := InitAPIKeyRepository(db)
+	err := apiKeyRepo.TouchLastUsed(context.Background(), uint(1), time.Now())
+	assertion.Nil(err, "Should be no error")
+	assertion.Nil(testMock.ExpectationsWereMet())
+}
+
+func TestAPIKeyTouchLastUsedFailureReturnError(t *testing.T) {
+	t.Parallel()
+	db, testMock, assertion := setUpAPIKey(t)
+	testMock.ExpectExec(updateQueryAPIKeys).WillReturnError(fmt.Errorf("update error"))
+	apiKeyRepo := InitAPIKeyRepository(db)
+	err := apiKeyRepo.TouchLastUsed(context.Background(), uint(1), time.Now())
+	assertion.NotNil(err, "Should be an error")
+}
+
+func setUpAPIKey(t *testing.T) (*gorm.DB, sqlmock.Sqlmock, *assert.Assertions) {
+	db, mock := setUpMockAPIKeyDB()
+	assertions := assert.New(t)
+	return db, mock, assertions
+}
+
+func setUpMockAPIKeyDB() (*gorm.DB, sqlmock.Sqlmock) {
+	mockDB, mock, _ := sqlmock.New()
+	gormMockDB, _ := gorm.Open(postgres.New(postgres.Config{
+		Conn: mockDB,
+	}), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Info),
+	})
+	return gormMockDB, mock
+}
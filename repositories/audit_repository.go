@@ -0,0 +1,153 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"news-topic-api/dto"
+	"news-topic-api/models"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	defaultAuditLimit = 100
+	maxAuditLimit = 1000
+)
+
+//IAuditRepository interface for audit repository
+type IAuditRepository interface {
+	List(ctx context.Context, queryParams map[string]string) ([]models.AuditLog, error)
+}
+
+//AuditRepository ...
+type AuditRepository struct{
+	db *gorm.DB
+}
+
+//InitAuditRepository initializes an audit repository given the database handle
+func InitAuditRepository(db *gorm.DB) IAuditRepository {
+	repository := new(AuditRepository)
+	repository.db = db
+	return *repository
+}
+
+//auditQuery holds the parsed filters of an audit listing, nil bounds are not filtered on
+type auditQuery struct {
+	entityType string
+	entityID *uint
+	actor string
+	from *time.Time
+	to *time.Time
+	limit int
+}
+
+//parseAuditQuery reads the audit filters shared by every audit repository
+func parseAuditQuery(queryParams map[string]string) (auditQuery, error) {
+	query := auditQuery{entityType: queryParams["entity_type"], actor: queryParams["actor"], limit: defaultAuditLimit}
+	if entityID := queryParams["entity_id"]; entityID != "" {
+		id, err := strconv.ParseUint(entityID, 10, 64)
+		if err != nil {
+			return auditQuery{}, InvalidFilterError{Field: "entity_id", Reason: "invalid format for entity_id"}
+		}
+		parsedID := uint(id)
+		query.entityID = &parsedID
+	}
+	if from := queryParams["from"]; from != "" {
+		fromTime, err := time.Parse(time.RFC3339, from)
+		if err != nil {
+			return auditQuery{}, InvalidFilterError{Field: "from", Reason: "invalid format for from, expected RFC 3339"}
+		}
+		query.from = &fromTime
+	}
+	if to := queryParams["to"]; to != "" {
+		toTime, err := time.Parse(time.RFC3339, to)
+		if err != nil {
+			return auditQuery{}, InvalidFilterError{Field: "to", Reason: "invalid format for to, expected RFC 3339"}
+		}
+		query.to = &toTime
+	}
+	if rawLimit := queryParams["limit"]; rawLimit != "" {
+		parsedLimit, err := strconv.Atoi(rawLimit)
+		if err != nil || parsedLimit < 1 {
+			return auditQuery{}, InvalidFilterError{Field: "limit", Reason: "invalid format for limit"}
+		}
+		query.limit = parsedLimit
+		if query.limit > maxAuditLimit {
+			query.limit = maxAuditLimit
+		}
+	}
+	return query, nil
+}
+
+//List retrieve audit entries given filters (entity, actor, time range), newest first
+func (a AuditRepository) List(ctx context.Context, queryParams map[string]string) ([]models.AuditLog, error) {
+	var auditLogs []models.AuditLog
+	query, err := parseAuditQuery(queryParams)
+	if err != nil {
+		return []models.AuditLog{}, err
+	}
+	db := a.db.WithContext(ctx)
+	querySearch := db.Model(&models.AuditLog{})
+	if query.entityType != "" {
+		querySearch = querySearch.Where("entity_type = ?", query.entityType)
+	}
+	if query.entityID != nil {
+		querySearch = querySearch.Where("entity_id = ?", *query.entityID)
+	}
+	if query.actor != "" {
+		querySearch = querySearch.Where("actor = ?", query.actor)
+	}
+	if query.from != nil {
+		querySearch = querySearch.Where("timestamp >= ?", *query.from)
+	}
+	if query.to != nil {
+		querySearch = querySearch.Where("timestamp < ?", *query.to)
+	}
+	err = querySearch.Order("timestamp DESC, id DESC").Limit(query.limit).Find(&auditLogs).Error
+	if err != nil {
+		return []models.AuditLog{}, translateError(err)
+	}
+	return auditLogs, nil
+}
+
+//recordAudit writes a completed audit entry with the caller's transaction
+func recordAudit(tx *gorm.DB, entry models.AuditLog, entityID uint) error {
+	entry.EntityID = entityID
+	entry.Timestamp = time.Now().UTC()
+	return tx.Create(&entry).Error
+}
+
+//recordAuditBatch writes completed audit entries with the caller's transaction, batchSize rows per insert
+func recordAuditBatch(tx *gorm.DB, entries []models.AuditLog, batchSize int) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	timestamp := time.Now().UTC()
+	for i := range entries {
+		entries[i].Timestamp = timestamp
+	}
+	return inBatches(len(entries), batchSize, func(start int, end int) error {
+		batch := entries[start:end]
+		return tx.Create(&batch).Error
+	})
+}
+
+//newsSnapshot captures news for an audit entry in the shape the api answers it
+func newsSnapshot(news models.News) models.JSON {
+	return snapshot(dto.NewNewsResponse(news))
+}
+
+//tagSnapshot captures a tag for an audit entry in the shape the api answers it
+func tagSnapshot(tag models.Tag) models.JSON {
+	return snapshot(dto.NewTagResponse(tag))
+}
+
+func snapshot(entity interface{}) models.JSON {
+	data, err := json.Marshal(entity)
+	if err != nil {
+		return nil
+	}
+	return data
+}
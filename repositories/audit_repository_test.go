@@ -0,0 +1,130 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"news-topic-api/models"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+const (
+	insertQueryAuditLogs = "^INSERT INTO \"audit_logs\".+$"
+	getQueryAuditLogs    = "^SELECT (.+) FROM \"audit_logs\".+$"
+)
+
+func getMockAuditLog(action string, entityType string) models.AuditLog {
+	meta := models.AuditMeta{Actor: "42", RequestID: "req-1", IP: "10.0.0.1"}
+	return models.NewAuditLog(meta, action, entityType)
+}
+
+func getMockListParamsAudit() map[string]string {
+	params := map[string]string {
+		"entity_type": "news",
+		"entity_id": "1",
+		"actor": "42",
+		"from": "2021-05-01T00:00:00Z",
+		"to": "2021-06-01T00:00:00Z",
+	}
+	return params
+}
+
+func TestAuditListSuccess(t *testing.T) {
+	t.Parallel()
+	db, testMock, assertion := setUpAudit(t)
+	testMock.ExpectQuery(getQueryAuditLogs).WillReturnRows(mockRowsAudit())
+	auditRepo := InitAuditRepository(db)
+	auditLogs, err := auditRepo.List(context.Background(), getMockListParamsAudit())
+	assertion.Nil(err, "Should be no error")
+	assertion.Equal(1, len(auditLogs))
+	assertion.Equal(`{"title":"Harga bitcoin anjlok"}`, string(auditLogs[0].After))
+}
+
+func TestAuditListInvalidTimeReturnError(t *testing.T) {
+	t.Parallel()
+	db, _, assertion := setUpAudit(t)
+	auditRepo := InitAuditRepository(db)
+	searchParams := getMockListParamsAudit()
+	searchParams["from"] = "yesterday"
+	_, err := auditRepo.List(context.Background(), searchParams)
+	assertion.NotNil(err, "Should be an error")
+}
+
+func TestAuditListInvalidEntityIDReturnError(t *testing.T) {
+	t.Parallel()
+	db, _, assertion := setUpAudit(t)
+	auditRepo := InitAuditRepository(db)
+	searchParams := getMockListParamsAudit()
+	searchParams["entity_id"] = "abc"
+	_, err := auditRepo.List(context.Background(), searchParams)
+	assertion.NotNil(err, "Should be an error")
+}
+
+func TestAuditListFailureReturnError(t *testing.T) {
+	t.Parallel()
+	db, testMock, assertion := setUpAudit(t)
+	testMock.ExpectQuery(getQueryAuditLogs).WillReturnError(fmt.Errorf("rows not found"))
+	auditRepo := InitAuditRepository(db)
+	_, err := auditRepo.List(context.Background(), map[string]string{})
+	assertion.NotNil(err, "There should be an error")
+}
+
+func TestNewsDeleteRecordsAuditInSameTransaction(t *testing.T) {
+	t.Parallel()
+	db, testMock, assertion := setUpAudit(t)
+	testMock.ExpectBegin()
+	testMock.ExpectQuery(getQueryNews).WillReturnRows(mockRowNews())
+	testMock.ExpectQuery(getQueryTags).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	testMock.ExpectQuery("^SELECT (.+) FROM \"authors\".+$").WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	testMock.ExpectExec(deleteQueryNews).WillReturnResult(sqlmock.NewResult(1, 1))
+	testMock.ExpectQuery(insertQueryAuditLogs).WithArgs(
+		sqlmock.AnyArg(), "42", models.AuditActionDelete, models.AuditEntityNews, uint(1),
+		sqlmock.AnyArg(), nil, "req-1", "10.0.0.1").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	testMock.ExpectCommit()
+	newsRepo := InitNewsRepository(db)
+	err := newsRepo.Delete(context.Background(), uint(1), getMockAuditLog(models.AuditActionDelete, models.AuditEntityNews))
+	assertion.Nil(err, "Should be no error")
+	assertion.Nil(testMock.ExpectationsWereMet())
+}
+
+func TestTagUpdateAuditFailureRollsBack(t *testing.T) {
+	t.Parallel()
+	db, testMock, assertion := setUpAudit(t)
+	testMock.ExpectBegin()
+	testMock.ExpectQuery(getQueryTags).WillReturnRows(mockRowTag())
+	testMock.ExpectExec(updateQueryTags).WillReturnResult(sqlmock.NewResult(1, 1))
+	testMock.ExpectQuery(insertQueryAuditLogs).WillReturnError(fmt.Errorf("insert error"))
+	testMock.ExpectRollback()
+	tagRepo := InitTagRepository(db)
+	_, err := tagRepo.Update(context.Background(), uint(1), getMockTag(), getMockAuditLog(models.AuditActionUpdate, models.AuditEntityTag))
+	assertion.NotNil(err, "Should be an error")
+	assertion.Nil(testMock.ExpectationsWereMet())
+}
+
+func setUpAudit(t *testing.T) (*gorm.DB, sqlmock.Sqlmock, *assert.Assertions) {
+	db, mock := setUpMockAuditDB()
+	assertions := assert.New(t)
+	return db, mock, assertions
+}
+
+func setUpMockAuditDB() (*gorm.DB, sqlmock.Sqlmock) {
+	mockDB, mock, _ := sqlmock.New()
+	gormMockDB, _ := gorm.Open(postgres.New(postgres.Config{
+		Conn: mockDB,
+	}), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Info),
+	})
+	return gormMockDB, mock
+}
+
+func mockRowsAudit() *sqlmock.Rows {
+	auditFieldColumns := []string{"id", "actor", "action", "entity_type", "entity_id", "before", "after", "request_id", "ip"}
+	rows := sqlmock.NewRows(auditFieldColumns)
+	rows.AddRow("1", "42", "create", "news", "1", nil, `{"title":"Harga bitcoin anjlok"}`, "req-1", "10.0.0.1")
+	return rows
+}
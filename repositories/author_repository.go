@@ -0,0 +1,110 @@
+package repositories
+
+import (
+	"context"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"news-topic-api/models"
+)
+
+//IAuthorRepository interface for author repository
+type IAuthorRepository interface {
+	Create(ctx context.Context, author models.Author) (models.Author, error)
+	Update(ctx context.Context, authorID uint, author models.Author) (models.Author, error)
+	Delete(ctx context.Context, authorID uint) (error)
+	GetByID(ctx context.Context, authorID uint) (models.Author, error)
+	List(ctx context.Context) ([]models.Author, error)
+	FindByIDs(ctx context.Context, authorIDs []uint) ([]models.Author, error)
+}
+
+//AuthorRepository ...
+type AuthorRepository struct{
+	db *gorm.DB
+}
+
+//InitAuthorRepository initializes an author repository given the database handle
+func InitAuthorRepository(db *gorm.DB) IAuthorRepository {
+	repository := new(AuthorRepository)
+	repository.db = db
+	return *repository
+}
+
+//Create ...
+func (a AuthorRepository) Create(ctx context.Context, author models.Author) (models.Author, error) {
+	db := a.db.WithContext(ctx)
+	err := db.Create(&author).Error
+	return author, translateError(err)
+}
+
+//Update ...
+func (a AuthorRepository) Update(ctx context.Context, authorID uint, author models.Author) (models.Author, error) {
+	var targetAuthor models.Author
+	db := a.db.WithContext(ctx)
+	err := db.Where("id = ?", authorID).First(&targetAuthor).Error
+	if err != nil {
+		return models.Author{}, translateError(err)
+	}
+	updateData := map[string]interface{} {
+		"name": author.Name,
+		"bio": author.Bio,
+		"avatar": author.Avatar,
+	}
+	err = db.Model(&targetAuthor).Omit("created_at").Updates(updateData).Error
+	if err != nil {
+		return models.Author{}, translateError(err)
+	}
+	return targetAuthor, nil
+}
+
+//Delete ...
+func (a AuthorRepository) Delete(ctx context.Context, authorID uint) (error) {
+	var targetAuthor models.Author
+	db := a.db.WithContext(ctx)
+	err := db.Where("id = ?", authorID).First(&targetAuthor).Error
+	if err != nil {
+		return translateError(err)
+	}
+	err = db.Delete(&targetAuthor).Error
+	if err != nil {
+		return translateError(err)
+	}
+	return nil
+}
+
+//GetByID ...
+func (a AuthorRepository) GetByID(ctx context.Context, authorID uint) (models.Author, error) {
+	var targetAuthor models.Author
+	db := a.db.WithContext(ctx)
+	err := db.Where("id = ?", authorID).First(&targetAuthor).Error
+	if err != nil {
+		return models.Author{}, translateError(err)
+	}
+	return targetAuthor, nil
+}
+
+//List ...
+func (a AuthorRepository) List(ctx context.Context) ([]models.Author, error) {
+	var authorsList []models.Author
+	db := a.db.WithContext(ctx)
+	querySearch := db.Table("authors")
+	err := querySearch.Order("id DESC").Find(&authorsList).Error
+	if err != nil {
+		return []models.Author{}, translateError(err)
+	}
+	return authorsList, nil
+}
+
+//FindByIDs returns the authors with the given ids, ids without an author are left out. Within a transaction the
+//authors stay locked against deletion until it ends
+func (a AuthorRepository) FindByIDs(ctx context.Context, authorIDs []uint) ([]models.Author, error) {
+	var authors []models.Author
+	if len(authorIDs) == 0 {
+		return authors, nil
+	}
+	db := a.db.WithContext(ctx)
+	err := db.Clauses(clause.Locking{Strength: "SHARE"}).Where("id IN ?", authorIDs).Find(&authors).Error
+	if err != nil {
+		return []models.Author{}, translateError(err)
+	}
+	return authors, nil
+}
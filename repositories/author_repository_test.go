@@ -0,0 +1,187 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"news-topic-api/models"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+const (
+	insertQueryAuthors = "^INSERT INTO \"authors\".+$"
+	updateQueryAuthors = `^UPDATE "authors".*WHERE "id" = .*$`
+	getQueryAuthors    = "^SELECT (.+) FROM \"authors\".+$"
+	deleteQueryAuthors = `^UPDATE "authors".*WHERE "authors"."id" = .*$`
+)
+
+func getMockAuthor() models.Author {
+	entity := models.Author{
+		Name: "Budi Santoso",
+		Bio: "Jurnalis ekonomi dan teknologi",
+		Avatar: "google.com/avatar.png",
+	}
+	return entity
+}
+
+func TestAuthorCreateSuccess(t *testing.T) {
+	t.Parallel()
+	db, testMock, assertion := setUpAuthor(t)
+	testMock.ExpectQuery(insertQueryAuthors).WillReturnRows(sqlmock.NewRows([]string{"1", "1"})).WillReturnError(nil)
+	mockAuthor := getMockAuthor()
+	authorRepo := InitAuthorRepository(db)
+	response, err := authorRepo.Create(context.Background(), mockAuthor)
+	assertion.Nil(err, "Should be no error")
+	assertion.NotNil(response, "Response should be not nil")
+}
+
+func TestAuthorCreateFailed(t *testing.T) {
+	t.Parallel()
+	db, testMock, assertion := setUpAuthor(t)
+	testMock.ExpectQuery(insertQueryAuthors).WillReturnRows(sqlmock.NewRows([]string{"0", "1"})).WillReturnError(errors.New("Insertion error"))
+	mockAuthor := getMockAuthor()
+	authorRepo := InitAuthorRepository(db)
+	_, err := authorRepo.Create(context.Background(), mockAuthor)
+	assertion.NotNil(err, "Should be an error")
+	testMock.ExpectationsWereMet()
+}
+
+func TestAuthorUpdateSuccess(t *testing.T) {
+	t.Parallel()
+	db, testMock, assertion := setUpAuthor(t)
+	returnRow := mockRowAuthor()
+	mockUpdateData := getMockAuthor()
+	testMock.ExpectQuery(getQueryAuthors).WillReturnRows(returnRow)
+	testMock.ExpectExec(updateQueryAuthors).WithArgs(sqlmock.AnyArg(),sqlmock.AnyArg(),sqlmock.AnyArg(),sqlmock.AnyArg(),sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(1, 1))
+	authorRepo := InitAuthorRepository(db)
+	_, err := authorRepo.Update(context.Background(), uint(1), mockUpdateData)
+	assertion.Nil(err, "Should be no error")
+	testMock.ExpectationsWereMet()
+}
+
+func TestAuthorUpdateIDNotFoundReturnError(t *testing.T) {
+	t.Parallel()
+	db, testMock, assertion := setUpAuthor(t)
+	returnRow := mockRowAuthor()
+	mockUpdateData := getMockAuthor()
+	testMock.ExpectQuery(getQueryAuthors).WillReturnRows(returnRow).WillReturnError(fmt.Errorf("record not found"))
+	authorRepo := InitAuthorRepository(db)
+	_, err := authorRepo.Update(context.Background(), uint(1), mockUpdateData)
+	assertion.NotNil(err, "Should be an error")
+	testMock.ExpectationsWereMet()
+}
+
+func TestAuthorUpdateFailureReturnError(t *testing.T) {
+	t.Parallel()
+	db, testMock, assertion := setUpAuthor(t)
+	returnRow := mockRowAuthor()
+	mockUpdateData := getMockAuthor()
+	testMock.ExpectQuery(getQueryAuthors).WillReturnRows(returnRow)
+	testMock.ExpectExec(updateQueryAuthors).WithArgs(sqlmock.AnyArg(),sqlmock.AnyArg(),sqlmock.AnyArg(),sqlmock.AnyArg(),sqlmock.AnyArg()).WillReturnError(fmt.Errorf("update error"))
+	authorRepo := InitAuthorRepository(db)
+	_, err := authorRepo.Update(context.Background(), uint(1), mockUpdateData)
+	assertion.NotNil(err, "Should be an error")
+	testMock.ExpectationsWereMet()
+}
+
+func TestAuthorDeleteSuccess(t *testing.T) {
+	t.Parallel()
+	db, testMock, assertion := setUpAuthor(t)
+	returnRow := mockRowAuthor()
+	testMock.ExpectQuery(getQueryAuthors).WillReturnRows(returnRow)
+	testMock.ExpectExec(deleteQueryAuthors).WithArgs(sqlmock.AnyArg(),sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(1, 1))
+	authorRepo := InitAuthorRepository(db)
+	err := authorRepo.Delete(context.Background(), uint(1))
+	assertion.Nil(err, "Should be no error")
+	testMock.ExpectationsWereMet()
+}
+
+func TestAuthorDeleteIDNotFoundReturnError(t *testing.T) {
+	t.Parallel()
+	db, testMock, assertion := setUpAuthor(t)
+	returnRow := mockRowAuthor()
+	testMock.ExpectQuery(getQueryAuthors).WillReturnRows(returnRow).WillReturnError(fmt.Errorf("record not found"))
+	authorRepo := InitAuthorRepository(db)
+	err := authorRepo.Delete(context.Background(), uint(1))
+	assertion.NotNil(err, "Should be an error")
+	testMock.ExpectationsWereMet()
+}
+
+func TestAuthorGetByIDSuccess(t *testing.T) {
+	t.Parallel()
+	db, testMock, assertion := setUpAuthor(t)
+	returnRow := mockRowAuthor()
+	testMock.ExpectQuery(getQueryAuthors).WillReturnRows(returnRow)
+	authorRepo := InitAuthorRepository(db)
+	author, err := authorRepo.GetByID(context.Background(), uint(1))
+	assertion.Nil(err, "Should be no error")
+	assertion.Equal("Budi Santoso", author.Name)
+}
+
+func TestAuthorGetByIDFailed(t *testing.T) {
+	t.Parallel()
+	db, testMock, assertion := setUpAuthor(t)
+	testMock.ExpectQuery(getQueryAuthors).WillReturnError(fmt.Errorf("record not found"))
+	authorRepo := InitAuthorRepository(db)
+	_, err := authorRepo.GetByID(context.Background(), uint(1))
+	assertion.NotNil(err, "There should be error")
+}
+
+func TestAuthorListSuccess(t *testing.T) {
+	t.Parallel()
+	db, testMock, assertion := setUpAuthor(t)
+	returnRow := mockRowAuthor()
+	testMock.ExpectQuery(getQueryAuthors).WillReturnRows(returnRow)
+	authorRepo := InitAuthorRepository(db)
+	authors, err := authorRepo.List(context.Background())
+	assertion.Equal(len(authors), 1)
+	assertion.Nil(err, "Should be no error")
+}
+
+func TestAuthorListFailureReturnError(t *testing.T) {
+	t.Parallel()
+	db, testMock, assertion := setUpAuthor(t)
+	testMock.ExpectQuery(getQueryAuthors).WillReturnError(fmt.Errorf("rows not found"))
+	authorRepo := InitAuthorRepository(db)
+	_, err := authorRepo.List(context.Background())
+	assertion.NotNil(err, "There should be an error")
+}
+
+func TestAuthorFindByIDsFailureReturnError(t *testing.T) {
+	t.Parallel()
+	db, testMock, assertion := setUpAuthor(t)
+	testMock.ExpectQuery(`^SELECT \* FROM "authors" WHERE id IN .+ FOR SHARE$`).WillReturnError(fmt.Errorf("connection reset"))
+	authorRepo := InitAuthorRepository(db)
+	_, err := authorRepo.FindByIDs(context.Background(), []uint{1})
+	assertion.NotNil(err, "Should be an error")
+	assertion.Nil(testMock.ExpectationsWereMet())
+}
+
+func setUpAuthor(t *testing.T) (*gorm.DB, sqlmock.Sqlmock, *assert.Assertions) {
+	db, mock := setUpMockAuthorDB()
+	assertions := assert.New(t)
+	return db, mock, assertions
+}
+
+func setUpMockAuthorDB() (*gorm.DB, sqlmock.Sqlmock) {
+	mockDB, mock, _ := sqlmock.New()
+	gormMockDB, _ := gorm.Open(postgres.New(postgres.Config{
+		Conn: mockDB,
+	}), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Info),
+	})
+	return gormMockDB, mock
+}
+
+func mockRowAuthor() *sqlmock.Rows {
+	authorFieldColumns := []string{"id","name","bio","avatar"}
+	rows := sqlmock.NewRows(authorFieldColumns)
+	rows.AddRow("1", "Budi Santoso", "Jurnalis ekonomi dan teknologi", "google.com/avatar.png")
+	return rows
+}
@@ -0,0 +1,18 @@
+package repositories
+
+//inBatches calls fn with the bounds of consecutive batches of at most size items out of total
+func inBatches(total int, size int, fn func(start int, end int) error) error {
+	if size < 1 {
+		size = total
+	}
+	for start := 0; start < total; start += size {
+		end := start + size
+		if end > total {
+			end = total
+		}
+		if err := fn(start, end); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,608 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"news-topic-api/migrations"
+	"news-topic-api/models"
+	"os"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+//conformanceBackend gives fresh repositories of one storage backend, every implementation of the
+//news, tag, author, api key and audit repositories must pass the conformance suite
+type conformanceBackend struct {
+	name  string
+	fresh func(t *testing.T) conformanceFixture
+}
+
+type conformanceFixture struct {
+	news               INewsRepository
+	tags               ITagRepository
+	authors            IAuthorRepository
+	apiKeys            IAPIKeyRepository
+	audit              IAuditRepository
+	transactionManager ITransactionManager
+}
+
+//conformanceBackends returns the memory backend, and postgres when TEST_DATABASE_URL names a database
+//the suite may wipe. CI must always check postgres
+func conformanceBackends(t *testing.T) []conformanceBackend {
+	backends := []conformanceBackend{{name: "memory", fresh: freshMemoryFixture}}
+	url := os.Getenv("TEST_DATABASE_URL")
+	if url == "" {
+		if os.Getenv("CI") != "" {
+			t.Fatal("TEST_DATABASE_URL is not set in CI, the postgres repositories would not be checked")
+		}
+		t.Log("TEST_DATABASE_URL is not set, the postgres repositories are not checked")
+		return backends
+	}
+	db, err := gorm.Open(postgres.Open(url), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("unable to open the test database: %s", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("unable to open the test database: %s", err)
+	}
+	embedded, err := migrations.Embedded()
+	if err != nil {
+		t.Fatalf("unable to read the migrations: %s", err)
+	}
+	if _, err := migrations.InitMigrator(sqlDB, embedded).Up(context.Background()); err != nil {
+		t.Fatalf("unable to migrate the test database: %s", err)
+	}
+	t.Cleanup(func() { sqlDB.Close() })
+	return append(backends, conformanceBackend{name: "postgres", fresh: func(t *testing.T) conformanceFixture {
+		err := db.Exec("TRUNCATE news, tags, authors, news_tag, news_author, api_keys, audit_logs RESTART IDENTITY CASCADE").Error
+		if err != nil {
+			t.Fatalf("unable to empty the test database: %s", err)
+		}
+		return conformanceFixture{
+			news:               InitNewsRepository(db),
+			tags:               InitTagRepository(db),
+			authors:            InitAuthorRepository(db),
+			apiKeys:            InitAPIKeyRepository(db),
+			audit:              InitAuditRepository(db),
+			transactionManager: InitTransactionManager(db),
+		}
+	}})
+}
+
+func freshMemoryFixture(t *testing.T) conformanceFixture {
+	store := InitMemoryStore()
+	return conformanceFixture{
+		news:               InitMemoryNewsRepository(store),
+		tags:               InitMemoryTagRepository(store),
+		authors:            InitMemoryAuthorRepository(store),
+		apiKeys:            InitMemoryAPIKeyRepository(store),
+		audit:              InitMemoryAuditRepository(store),
+		transactionManager: InitMemoryTransactionManager(store),
+	}
+}
+
+//runConformance runs check against every backend, the backends share one database so they do not run in parallel
+func runConformance(t *testing.T, check func(t *testing.T, fixture conformanceFixture)) {
+	for _, backend := range conformanceBackends(t) {
+		backend := backend
+		t.Run(backend.name, func(t *testing.T) {
+			check(t, backend.fresh(t))
+		})
+	}
+}
+
+func conformanceTag(t *testing.T, fixture conformanceFixture, name string) models.Tag {
+	tag, err := fixture.tags.Create(context.Background(), models.Tag{Name: name}, getMockAuditLog(models.AuditActionCreate, models.AuditEntityTag))
+	if err != nil {
+		t.Fatalf("unable to create tag %s: %s", name, err)
+	}
+	return tag
+}
+
+func conformanceAuthor(t *testing.T, fixture conformanceFixture, name string) models.Author {
+	author, err := fixture.authors.Create(context.Background(), models.Author{Name: name})
+	if err != nil {
+		t.Fatalf("unable to create author %s: %s", name, err)
+	}
+	return author
+}
+
+func conformanceNews(t *testing.T, fixture conformanceFixture, title string, status string, tags []models.Tag, authors []models.Author) models.News {
+	news := models.News{
+		Title:     title,
+		Thumbnail: "https://google.com/thumbnail.png",
+		Summary:   "summary",
+		Content:   "content",
+		Topic:     "economy",
+		Status:    status,
+		Tags:      tags,
+		Authors:   authors,
+	}
+	created, err := fixture.news.Create(context.Background(), news, getMockAuditLog(models.AuditActionCreate, models.AuditEntityNews))
+	if err != nil {
+		t.Fatalf("unable to create news %s: %s", title, err)
+	}
+	return created
+}
+
+func newsTitles(newsList []models.News) []string {
+	titles := []string{}
+	for _, news := range newsList {
+		titles = append(titles, news.Title)
+	}
+	return titles
+}
+
+func tagNames(tags []models.Tag) []string {
+	names := []string{}
+	for _, tag := range tags {
+		names = append(names, tag.Name)
+	}
+	return names
+}
+
+func TestConformanceTagsShouldListLatestFirst(t *testing.T) {
+	runConformance(t, func(t *testing.T, fixture conformanceFixture) {
+		conformanceTag(t, fixture, "economy")
+		conformanceTag(t, fixture, "crypto")
+		tags, err := fixture.tags.List(context.Background())
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"crypto", "economy"}, tagNames(tags))
+		assert.True(t, tags[0].ID > tags[1].ID, "ids should grow")
+		assert.False(t, tags[0].CreatedAt.IsZero(), "created_at should be set")
+	})
+}
+
+func TestConformanceTagNamesShouldStayUniqueAfterDelete(t *testing.T) {
+	runConformance(t, func(t *testing.T, fixture conformanceFixture) {
+		ctx := context.Background()
+		crypto := conformanceTag(t, fixture, "crypto")
+		economy := conformanceTag(t, fixture, "economy")
+		_, err := fixture.tags.Create(ctx, models.Tag{Name: "crypto"}, getMockAuditLog(models.AuditActionCreate, models.AuditEntityTag))
+		assert.True(t, errors.Is(err, ErrDuplicate), "creating a taken name should fail with ErrDuplicate, got %v", err)
+		_, err = fixture.tags.Update(ctx, economy.ID, models.Tag{Name: "crypto"}, getMockAuditLog(models.AuditActionUpdate, models.AuditEntityTag))
+		assert.True(t, errors.Is(err, ErrDuplicate), "renaming to a taken name should fail with ErrDuplicate, got %v", err)
+		assert.Nil(t, fixture.tags.Delete(ctx, crypto.ID, getMockAuditLog(models.AuditActionDelete, models.AuditEntityTag)))
+		_, err = fixture.tags.Create(ctx, models.Tag{Name: "crypto"}, getMockAuditLog(models.AuditActionCreate, models.AuditEntityTag))
+		assert.True(t, errors.Is(err, ErrDuplicate), "the name of a deleted tag should stay taken, got %v", err)
+	})
+}
+
+func TestConformanceDeletedTagShouldBeHidden(t *testing.T) {
+	runConformance(t, func(t *testing.T, fixture conformanceFixture) {
+		ctx := context.Background()
+		crypto := conformanceTag(t, fixture, "crypto")
+		economy := conformanceTag(t, fixture, "economy")
+		news := conformanceNews(t, fixture, "bitcoin", models.StatusPublished, []models.Tag{crypto, economy}, nil)
+		assert.Nil(t, fixture.tags.Delete(ctx, crypto.ID, getMockAuditLog(models.AuditActionDelete, models.AuditEntityTag)))
+
+		err := fixture.tags.Delete(ctx, crypto.ID, getMockAuditLog(models.AuditActionDelete, models.AuditEntityTag))
+		assert.True(t, errors.Is(err, ErrNotFound), "deleting twice should fail with ErrNotFound, got %v", err)
+		_, err = fixture.tags.Update(ctx, crypto.ID, models.Tag{Name: "coins"}, getMockAuditLog(models.AuditActionUpdate, models.AuditEntityTag))
+		assert.True(t, errors.Is(err, ErrNotFound), "updating a deleted tag should fail with ErrNotFound, got %v", err)
+		tags, err := fixture.tags.List(ctx)
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"economy"}, tagNames(tags))
+		found, err := fixture.tags.FindByIDs(ctx, []uint{crypto.ID, economy.ID})
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"economy"}, tagNames(found))
+		detail, err := fixture.news.GetByID(ctx, news.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"economy"}, tagNames(detail.Tags), "the news should no longer load the deleted tag")
+	})
+}
+
+func TestConformanceNewsShouldLoadTagsAndAuthors(t *testing.T) {
+	runConformance(t, func(t *testing.T, fixture conformanceFixture) {
+		crypto := conformanceTag(t, fixture, "crypto")
+		economy := conformanceTag(t, fixture, "economy")
+		author := conformanceAuthor(t, fixture, "Satoshi")
+		created := conformanceNews(t, fixture, "bitcoin", models.StatusDraft, []models.Tag{crypto, economy}, []models.Author{author})
+		assert.NotZero(t, created.ID)
+		news, err := fixture.news.GetByID(context.Background(), created.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, "bitcoin", news.Title)
+		assert.Equal(t, models.StatusDraft, news.Status)
+		assert.ElementsMatch(t, []string{"crypto", "economy"}, tagNames(news.Tags))
+		assert.Len(t, news.Authors, 1)
+		assert.Equal(t, author.ID, news.Authors[0].ID)
+
+		_, err = fixture.news.GetByID(context.Background(), created.ID+100)
+		assert.True(t, errors.Is(err, ErrNotFound), "a missing news should fail with ErrNotFound, got %v", err)
+	})
+}
+
+func TestConformanceNewsListShouldHonorFilters(t *testing.T) {
+	runConformance(t, func(t *testing.T, fixture conformanceFixture) {
+		ctx := context.Background()
+		crypto := conformanceTag(t, fixture, "crypto")
+		economy := conformanceTag(t, fixture, "economy")
+		author := conformanceAuthor(t, fixture, "Satoshi")
+		conformanceNews(t, fixture, "bitcoin", models.StatusPublished, []models.Tag{crypto}, []models.Author{author})
+		conformanceNews(t, fixture, "inflation", models.StatusPublished, []models.Tag{economy}, nil)
+		conformanceNews(t, fixture, "ethereum", models.StatusDraft, []models.Tag{crypto, economy}, nil)
+
+		cases := []struct {
+			params map[string]string
+			titles []string
+		}{
+			{map[string]string{}, []string{"ethereum", "inflation", "bitcoin"}},
+			{map[string]string{"status": models.StatusPublished}, []string{"inflation", "bitcoin"}},
+			{map[string]string{"topic": "economy", "status": models.StatusDraft}, []string{"ethereum"}},
+			{map[string]string{"topic": "sport"}, []string{}},
+			{map[string]string{"tag": itoa(crypto.ID)}, []string{"ethereum", "bitcoin"}},
+			{map[string]string{"tag": itoa(economy.ID), "status": models.StatusPublished}, []string{"inflation"}},
+			{map[string]string{"author": itoa(author.ID)}, []string{"bitcoin"}},
+		}
+		for _, c := range cases {
+			newsList, err := fixture.news.List(ctx, c.params)
+			assert.Nil(t, err)
+			assert.Equal(t, c.titles, newsTitles(newsList), "filters %v", c.params)
+		}
+
+		_, err := fixture.news.List(ctx, map[string]string{"author": "satoshi"})
+		var invalidFilter InvalidFilterError
+		assert.True(t, errors.As(err, &invalidFilter), "a malformed filter should fail with InvalidFilterError, got %v", err)
+		assert.Equal(t, "author", invalidFilter.Field)
+	})
+}
+
+func TestConformanceDeletedNewsShouldBeHidden(t *testing.T) {
+	runConformance(t, func(t *testing.T, fixture conformanceFixture) {
+		ctx := context.Background()
+		bitcoin := conformanceNews(t, fixture, "bitcoin", models.StatusPublished, nil, nil)
+		conformanceNews(t, fixture, "inflation", models.StatusPublished, nil, nil)
+		conformanceNews(t, fixture, "ethereum", models.StatusDraft, nil, nil)
+		assert.Nil(t, fixture.news.Delete(ctx, bitcoin.ID, getMockAuditLog(models.AuditActionDelete, models.AuditEntityNews)))
+
+		_, err := fixture.news.GetByID(ctx, bitcoin.ID)
+		assert.True(t, errors.Is(err, ErrNotFound), "a deleted news should fail with ErrNotFound, got %v", err)
+		err = fixture.news.Delete(ctx, bitcoin.ID, getMockAuditLog(models.AuditActionDelete, models.AuditEntityNews))
+		assert.True(t, errors.Is(err, ErrNotFound), "deleting twice should fail with ErrNotFound, got %v", err)
+		_, err = fixture.news.Update(ctx, bitcoin.ID, models.News{Title: "btc"}, getMockAuditLog(models.AuditActionUpdate, models.AuditEntityNews))
+		assert.True(t, errors.Is(err, ErrNotFound), "updating a deleted news should fail with ErrNotFound, got %v", err)
+		newsList, err := fixture.news.List(ctx, map[string]string{})
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"ethereum", "inflation"}, newsTitles(newsList))
+		counts, err := fixture.news.CountByStatus(ctx)
+		assert.Nil(t, err)
+		assert.Equal(t, map[string]int64{models.StatusPublished: 1, models.StatusDraft: 1}, counts)
+	})
+}
+
+func TestConformanceNewsUpdateShouldReplaceTags(t *testing.T) {
+	runConformance(t, func(t *testing.T, fixture conformanceFixture) {
+		ctx := context.Background()
+		crypto := conformanceTag(t, fixture, "crypto")
+		economy := conformanceTag(t, fixture, "economy")
+		news := conformanceNews(t, fixture, "bitcoin", models.StatusDraft, []models.Tag{crypto}, nil)
+		news.Title = "bitcoin halving"
+		news.Status = models.StatusPublished
+		news.Tags = []models.Tag{economy}
+		updated, err := fixture.news.Update(ctx, news.ID, news, getMockAuditLog(models.AuditActionUpdate, models.AuditEntityNews))
+		assert.Nil(t, err)
+		assert.Equal(t, "bitcoin halving", updated.Title)
+		assert.Equal(t, []string{"economy"}, tagNames(updated.Tags))
+		newsList, err := fixture.news.List(ctx, map[string]string{"tag": itoa(crypto.ID)})
+		assert.Nil(t, err)
+		assert.Empty(t, newsList, "the news should have left the replaced tag")
+	})
+}
+
+func TestConformanceTagMergeShouldMoveNews(t *testing.T) {
+	runConformance(t, func(t *testing.T, fixture conformanceFixture) {
+		ctx := context.Background()
+		crypto := conformanceTag(t, fixture, "crypto")
+		btc := conformanceTag(t, fixture, "btc")
+		coins := conformanceTag(t, fixture, "coins")
+		conformanceNews(t, fixture, "bitcoin", models.StatusPublished, []models.Tag{btc}, nil)
+		conformanceNews(t, fixture, "ethereum", models.StatusPublished, []models.Tag{crypto, coins}, nil)
+
+		_, err := fixture.tags.Merge(ctx, crypto.ID, []uint{btc.ID, coins.ID + 100}, getMockAuditLog(models.AuditActionMerge, models.AuditEntityTag))
+		assert.True(t, errors.Is(err, ErrNotFound), "merging a missing tag should fail with ErrNotFound, got %v", err)
+		target, err := fixture.tags.Merge(ctx, crypto.ID, []uint{btc.ID, coins.ID}, getMockAuditLog(models.AuditActionMerge, models.AuditEntityTag))
+		assert.Nil(t, err)
+		assert.Equal(t, crypto.ID, target.ID)
+		tags, err := fixture.tags.List(ctx)
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"crypto"}, tagNames(tags))
+		newsList, err := fixture.news.List(ctx, map[string]string{"tag": itoa(crypto.ID)})
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"ethereum", "bitcoin"}, newsTitles(newsList))
+		assert.Equal(t, []string{"crypto"}, tagNames(newsList[0].Tags), "a news linked to the target and a source should keep one link")
+	})
+}
+
+func TestConformanceCreateBatchShouldStoreEveryRow(t *testing.T) {
+	runConformance(t, func(t *testing.T, fixture conformanceFixture) {
+		ctx := context.Background()
+		tags, err := fixture.tags.CreateBatch(ctx, []models.Tag{{Name: "crypto"}, {Name: "economy"}, {Name: "sport"}}, 2, getMockAuditLog(models.AuditActionCreate, models.AuditEntityTag))
+		assert.Nil(t, err)
+		assert.Len(t, tags, 3)
+		for _, tag := range tags {
+			assert.NotZero(t, tag.ID)
+		}
+		newsList := []models.News{
+			{Title: "bitcoin", Thumbnail: "https://google.com/a.png", Content: "content", Status: models.StatusPublished, Tags: []models.Tag{tags[0]}},
+			{Title: "inflation", Thumbnail: "https://google.com/b.png", Content: "content", Status: models.StatusPublished, Tags: []models.Tag{tags[1]}},
+		}
+		created, err := fixture.news.CreateBatch(ctx, newsList, 1, getMockAuditLog(models.AuditActionCreate, models.AuditEntityNews))
+		assert.Nil(t, err)
+		assert.Len(t, created, 2)
+		listed, err := fixture.news.List(ctx, map[string]string{"tag": itoa(tags[1].ID)})
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"inflation"}, newsTitles(listed))
+
+		_, err = fixture.tags.CreateBatch(ctx, []models.Tag{{Name: "weather"}, {Name: "sport"}}, 2, getMockAuditLog(models.AuditActionCreate, models.AuditEntityTag))
+		assert.True(t, errors.Is(err, ErrDuplicate), "a batch with a taken name should fail with ErrDuplicate, got %v", err)
+		all, err := fixture.tags.List(ctx)
+		assert.Nil(t, err)
+		assert.Len(t, all, 3, "a failed batch should store none of its rows")
+	})
+}
+
+func TestConformanceTransactionShouldRollBack(t *testing.T) {
+	runConformance(t, func(t *testing.T, fixture conformanceFixture) {
+		ctx := context.Background()
+		err := fixture.transactionManager.WithinTransaction(ctx, func(uow IUnitOfWork) error {
+			tag, err := uow.Tags().Create(ctx, models.Tag{Name: "crypto"}, getMockAuditLog(models.AuditActionCreate, models.AuditEntityTag))
+			if err != nil {
+				return err
+			}
+			found, err := uow.Tags().FindByIDs(ctx, []uint{tag.ID})
+			assert.Nil(t, err)
+			assert.Len(t, found, 1, "the transaction should see its own writes")
+			return assert.AnError
+		})
+		assert.Equal(t, assert.AnError, err)
+		tags, err := fixture.tags.List(ctx)
+		assert.Nil(t, err)
+		assert.Empty(t, tags, "the rolled back tag should not be stored")
+		auditLogs, err := fixture.audit.List(ctx, map[string]string{})
+		assert.Nil(t, err)
+		assert.Empty(t, auditLogs, "the audit entry of the rolled back tag should not be stored")
+
+		err = fixture.transactionManager.WithinTransaction(ctx, func(uow IUnitOfWork) error {
+			tag, err := uow.Tags().Create(ctx, models.Tag{Name: "crypto"}, getMockAuditLog(models.AuditActionCreate, models.AuditEntityTag))
+			if err != nil {
+				return err
+			}
+			_, err = uow.News().Create(ctx, models.News{Title: "bitcoin", Status: models.StatusDraft, Tags: []models.Tag{tag}},
+				getMockAuditLog(models.AuditActionCreate, models.AuditEntityNews))
+			return err
+		})
+		assert.Nil(t, err)
+		newsList, err := fixture.news.List(ctx, map[string]string{})
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"bitcoin"}, newsTitles(newsList))
+		assert.Equal(t, []string{"crypto"}, tagNames(newsList[0].Tags))
+	})
+}
+
+func TestConformanceDeletedAuthorShouldBeHidden(t *testing.T) {
+	runConformance(t, func(t *testing.T, fixture conformanceFixture) {
+		ctx := context.Background()
+		satoshi := conformanceAuthor(t, fixture, "Satoshi")
+		vitalik := conformanceAuthor(t, fixture, "Vitalik")
+		news := conformanceNews(t, fixture, "bitcoin", models.StatusPublished, nil, []models.Author{satoshi, vitalik})
+		renamed, err := fixture.authors.Update(ctx, satoshi.ID, models.Author{Name: "Satoshi Nakamoto", Bio: "inventor"})
+		assert.Nil(t, err)
+		assert.Equal(t, "Satoshi Nakamoto", renamed.Name)
+		assert.Nil(t, fixture.authors.Delete(ctx, vitalik.ID))
+
+		authors, err := fixture.authors.List(ctx)
+		assert.Nil(t, err)
+		assert.Len(t, authors, 1)
+		assert.Equal(t, "Satoshi Nakamoto", authors[0].Name)
+		_, err = fixture.authors.GetByID(ctx, vitalik.ID)
+		assert.True(t, errors.Is(err, ErrNotFound), "a deleted author should not be found, got %v", err)
+		assert.True(t, errors.Is(fixture.authors.Delete(ctx, vitalik.ID), ErrNotFound))
+		found, err := fixture.authors.FindByIDs(ctx, []uint{vitalik.ID, satoshi.ID})
+		assert.Nil(t, err)
+		assert.Len(t, found, 1)
+		detail, err := fixture.news.GetByID(ctx, news.ID)
+		assert.Nil(t, err)
+		assert.Len(t, detail.Authors, 1, "the news should no longer load the deleted author")
+		assert.Equal(t, "Satoshi Nakamoto", detail.Authors[0].Name, "the news should load the renamed author")
+	})
+}
+
+func TestConformanceDeletedNewsAndTagsShouldBeRestored(t *testing.T) {
+	runConformance(t, func(t *testing.T, fixture conformanceFixture) {
+		ctx := context.Background()
+		crypto := conformanceTag(t, fixture, "crypto")
+		news := conformanceNews(t, fixture, "bitcoin", models.StatusPublished, []models.Tag{crypto}, nil)
+		_, err := fixture.news.Restore(ctx, news.ID, getMockAuditLog(models.AuditActionRestore, models.AuditEntityNews))
+		assert.True(t, errors.Is(err, ErrNotFound), "live news should not be restored, got %v", err)
+		_, err = fixture.tags.Restore(ctx, crypto.ID+100, getMockAuditLog(models.AuditActionRestore, models.AuditEntityTag))
+		assert.True(t, errors.Is(err, ErrNotFound), "a missing tag should not be restored, got %v", err)
+
+		assert.Nil(t, fixture.news.Delete(ctx, news.ID, getMockAuditLog(models.AuditActionDelete, models.AuditEntityNews)))
+		assert.Nil(t, fixture.tags.Delete(ctx, crypto.ID, getMockAuditLog(models.AuditActionDelete, models.AuditEntityTag)))
+		restoredNews, err := fixture.news.Restore(ctx, news.ID, getMockAuditLog(models.AuditActionRestore, models.AuditEntityNews))
+		assert.Nil(t, err)
+		assert.Equal(t, "bitcoin", restoredNews.Title)
+		assert.Empty(t, restoredNews.Tags, "the deleted tag should not be loaded")
+		restoredTag, err := fixture.tags.Restore(ctx, crypto.ID, getMockAuditLog(models.AuditActionRestore, models.AuditEntityTag))
+		assert.Nil(t, err)
+		assert.Equal(t, "crypto", restoredTag.Name)
+
+		detail, err := fixture.news.GetByID(ctx, news.ID)
+		assert.Nil(t, err)
+		if assert.Len(t, detail.Tags, 1, "the restored news should keep its link to the restored tag") {
+			assert.Equal(t, crypto.ID, detail.Tags[0].ID)
+		}
+		tags, err := fixture.tags.List(ctx)
+		assert.Nil(t, err)
+		assert.Len(t, tags, 1)
+		auditLogs, err := fixture.audit.List(ctx, map[string]string{})
+		assert.Nil(t, err)
+		assert.Equal(t, []string{
+			"tag create " + itoa(crypto.ID),
+			"news create " + itoa(news.ID),
+			"news delete " + itoa(news.ID),
+			"tag delete " + itoa(crypto.ID),
+			"news restore " + itoa(news.ID),
+			"tag restore " + itoa(crypto.ID),
+		}, auditedActions(auditLogs), "restores should be audited, refused ones not at all")
+		assert.Contains(t, string(auditLogs[0].After), `"name":"crypto"`)
+	})
+}
+
+func TestConformanceAPIKeysShouldBeFoundByPrefix(t *testing.T) {
+	runConformance(t, func(t *testing.T, fixture conformanceFixture) {
+		ctx := context.Background()
+		created, err := fixture.apiKeys.Create(ctx, models.APIKey{Name: "ingestion", Owner: "1", Prefix: "ntk_a", KeyHash: "hash-a",
+			Scopes: []string{models.ScopeNewsRead}})
+		assert.Nil(t, err)
+		assert.NotZero(t, created.ID)
+		_, err = fixture.apiKeys.Create(ctx, models.APIKey{Name: "copy", Owner: "1", Prefix: "ntk_a", KeyHash: "hash-b", Scopes: []string{models.ScopeNewsRead}})
+		assert.True(t, errors.Is(err, ErrDuplicate), "a taken prefix should fail with ErrDuplicate, got %v", err)
+
+		rotated, err := fixture.apiKeys.UpdateSecret(ctx, created.ID, "ntk_b", "hash-b")
+		assert.Nil(t, err)
+		assert.Equal(t, "ntk_b", rotated.Prefix)
+		_, err = fixture.apiKeys.GetByPrefix(ctx, "ntk_a")
+		assert.True(t, errors.Is(err, ErrNotFound), "the old prefix should no longer be found, got %v", err)
+		found, err := fixture.apiKeys.GetByPrefix(ctx, "ntk_b")
+		assert.Nil(t, err)
+		assert.Equal(t, "hash-b", found.KeyHash)
+		assert.Equal(t, []string{models.ScopeNewsRead}, []string(found.Scopes))
+
+		usedAt := time.Date(2021, 5, 1, 10, 0, 0, 0, time.UTC)
+		assert.Nil(t, fixture.apiKeys.TouchLastUsed(ctx, created.ID, usedAt))
+		assert.Nil(t, fixture.apiKeys.TouchLastUsed(ctx, created.ID+100, usedAt), "touching a missing key should be ignored")
+		assert.Nil(t, fixture.apiKeys.Revoke(ctx, created.ID, usedAt.Add(time.Hour)))
+		assert.True(t, errors.Is(fixture.apiKeys.Revoke(ctx, created.ID+100, usedAt), ErrNotFound))
+		revoked, err := fixture.apiKeys.GetByID(ctx, created.ID)
+		assert.Nil(t, err)
+		assert.True(t, usedAt.Equal(*revoked.LastUsedAt))
+		assert.True(t, usedAt.Add(time.Hour).Equal(*revoked.RevokedAt))
+		apiKeys, err := fixture.apiKeys.List(ctx)
+		assert.Nil(t, err)
+		assert.Len(t, apiKeys, 1, "revoked keys should still be listed")
+	})
+}
+
+//auditedActions lists the entity type, action and entity id of the audit entries, oldest first
+func auditedActions(auditLogs []models.AuditLog) []string {
+	actions := make([]string, 0, len(auditLogs))
+	for i := len(auditLogs) - 1; i >= 0; i-- {
+		actions = append(actions, auditLogs[i].EntityType+" "+auditLogs[i].Action+" "+itoa(auditLogs[i].EntityID))
+	}
+	return actions
+}
+
+func TestConformanceEveryMutationShouldBeAudited(t *testing.T) {
+	runConformance(t, func(t *testing.T, fixture conformanceFixture) {
+		ctx := context.Background()
+		crypto := conformanceTag(t, fixture, "crypto")
+		batch, err := fixture.tags.CreateBatch(ctx, []models.Tag{{Name: "btc"}, {Name: "coins"}}, 1, getMockAuditLog(models.AuditActionCreate, models.AuditEntityTag))
+		assert.Nil(t, err)
+		_, err = fixture.tags.Update(ctx, crypto.ID, models.Tag{Name: "cryptocurrency"}, getMockAuditLog(models.AuditActionUpdate, models.AuditEntityTag))
+		assert.Nil(t, err)
+		news := conformanceNews(t, fixture, "bitcoin", models.StatusDraft, []models.Tag{batch[0]}, nil)
+		changes := news
+		changes.Status = models.StatusPublished
+		_, err = fixture.news.Update(ctx, news.ID, changes, getMockAuditLog(models.AuditActionUpdate, models.AuditEntityNews))
+		assert.Nil(t, err)
+		_, err = fixture.tags.Merge(ctx, crypto.ID, []uint{batch[0].ID}, getMockAuditLog(models.AuditActionMerge, models.AuditEntityTag))
+		assert.Nil(t, err)
+		assert.Nil(t, fixture.news.Delete(ctx, news.ID, getMockAuditLog(models.AuditActionDelete, models.AuditEntityNews)))
+		assert.Nil(t, fixture.tags.Delete(ctx, batch[1].ID, getMockAuditLog(models.AuditActionDelete, models.AuditEntityTag)))
+		assert.True(t, errors.Is(fixture.tags.Delete(ctx, batch[1].ID, getMockAuditLog(models.AuditActionDelete, models.AuditEntityTag)), ErrNotFound))
+
+		auditLogs, err := fixture.audit.List(ctx, map[string]string{})
+		assert.Nil(t, err)
+		assert.Equal(t, []string{
+			"tag create " + itoa(crypto.ID),
+			"tag create " + itoa(batch[0].ID),
+			"tag create " + itoa(batch[1].ID),
+			"tag update " + itoa(crypto.ID),
+			"news create " + itoa(news.ID),
+			"news update " + itoa(news.ID),
+			"tag merge " + itoa(batch[0].ID),
+			"news delete " + itoa(news.ID),
+			"tag delete " + itoa(batch[1].ID),
+		}, auditedActions(auditLogs), "every successful mutation should be audited once, failed ones not at all")
+		for _, entry := range auditLogs {
+			assert.Equal(t, "42", entry.Actor)
+			assert.Equal(t, "req-1", entry.RequestID)
+			assert.False(t, entry.Timestamp.IsZero())
+		}
+		updates, err := fixture.audit.List(ctx, map[string]string{"entity_type": models.AuditEntityNews, "entity_id": itoa(news.ID), "limit": "2"})
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"news update " + itoa(news.ID), "news delete " + itoa(news.ID)}, auditedActions(updates))
+		assert.Contains(t, string(updates[1].Before), `"status":"draft"`)
+		assert.Contains(t, string(updates[1].After), `"status":"published"`)
+		for _, entry := range auditLogs {
+			for _, state := range []models.JSON{entry.Before, entry.After} {
+				if state == nil {
+					continue
+				}
+				var fields map[string]interface{}
+				assert.Nil(t, json.Unmarshal(state, &fields))
+				assert.Contains(t, fields, "id", "snapshots should have the shape of the api responses")
+				for _, gormField := range []string{"ID", "CreatedAt", "UpdatedAt", "DeletedAt"} {
+					assert.NotContains(t, fields, gormField)
+				}
+			}
+		}
+	})
+}
+
+func TestConformanceCancelledContextShouldFail(t *testing.T) {
+	runConformance(t, func(t *testing.T, fixture conformanceFixture) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err := fixture.tags.Create(ctx, models.Tag{Name: "crypto"}, getMockAuditLog(models.AuditActionCreate, models.AuditEntityTag))
+		assert.NotNil(t, err, "a write with a cancelled context should fail")
+		_, err = fixture.news.List(ctx, map[string]string{})
+		assert.NotNil(t, err, "a read with a cancelled context should fail")
+		tags, err := fixture.tags.List(context.Background())
+		assert.Nil(t, err)
+		assert.Empty(t, tags)
+	})
+}
+
+func TestConformanceConcurrentWritesShouldGetDistinctIDs(t *testing.T) {
+	runConformance(t, func(t *testing.T, fixture conformanceFixture) {
+		ctx := context.Background()
+		ids := make(chan uint, 20)
+		var wg sync.WaitGroup
+		for i := 0; i < cap(ids); i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				tag, err := fixture.tags.Create(ctx, models.Tag{Name: "tag-" + strconv.Itoa(i)}, getMockAuditLog(models.AuditActionCreate, models.AuditEntityTag))
+				assert.Nil(t, err)
+				_, err = fixture.news.List(ctx, map[string]string{"tag": itoa(tag.ID)})
+				assert.Nil(t, err)
+				ids <- tag.ID
+			}(i)
+		}
+		wg.Wait()
+		close(ids)
+		seen := map[uint]bool{}
+		for id := range ids {
+			assert.False(t, seen[id], "id %d was given twice", id)
+			seen[id] = true
+		}
+		tags, err := fixture.tags.List(ctx)
+		assert.Nil(t, err)
+		assert.Len(t, tags, cap(ids))
+	})
+}
+
+func itoa(id uint) string {
+	return strconv.FormatUint(uint64(id), 10)
+}
@@ -0,0 +1,89 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"net"
+	"strings"
+
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+)
+
+var (
+	//ErrNotFound is returned when the record an operation targets does not exist
+	ErrNotFound = errors.New("record not found")
+	//ErrDuplicate is returned when a write breaks a unique constraint
+	ErrDuplicate = errors.New("duplicate record")
+	//ErrUnavailable is returned when the database can not be reached or refuses connections
+	ErrUnavailable = errors.New("database unavailable")
+)
+
+//InvalidFilterError is returned when a list filter can not be parsed, field names the filter
+type InvalidFilterError struct {
+	Field  string
+	Reason string
+}
+
+func (i InvalidFilterError) Error() string {
+	return i.Reason
+}
+
+//dbError keeps the driver error behind a translated one for the logs, errors.Is matches the translation
+type dbError struct {
+	kind  error
+	cause error
+}
+
+func (d dbError) Error() string {
+	return d.kind.Error() + ": " + d.cause.Error()
+}
+
+func (d dbError) Unwrap() error {
+	return d.kind
+}
+
+//translateError maps gorm and driver errors to ErrNotFound, ErrDuplicate and ErrUnavailable,
+//context errors, errors already translated and errors without a meaning for the caller are kept
+func translateError(err error) error {
+	if err == nil {
+		return nil
+	}
+	var translated dbError
+	if errors.As(err, &translated) || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return err
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return dbError{kind: ErrNotFound, cause: err}
+	}
+	if state := sqlState(err); state != "" {
+		switch {
+		case state == "23505":
+			return dbError{kind: ErrDuplicate, cause: err}
+		// connection exceptions, too many connections and the server shutting down or starting up
+		case strings.HasPrefix(state, "08"), state == "53300", state == "57P01", state == "57P02", state == "57P03":
+			return dbError{kind: ErrUnavailable, cause: err}
+		}
+		return err
+	}
+	var netError net.Error
+	if errors.Is(err, driver.ErrBadConn) || errors.Is(err, sql.ErrConnDone) || errors.As(err, &netError) {
+		return dbError{kind: ErrUnavailable, cause: err}
+	}
+	return err
+}
+
+//sqlState returns the SQLSTATE code reported by the pgx or pq driver, or an empty string
+func sqlState(err error) string {
+	var pgxError interface{ SQLState() string }
+	if errors.As(err, &pgxError) {
+		return pgxError.SQLState()
+	}
+	var pqError *pq.Error
+	if errors.As(err, &pqError) {
+		return string(pqError.Code)
+	}
+	return ""
+}
@@ -0,0 +1,53 @@
+package repositories
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+//pgxError stands for the pgconn.PgError returned by the pgx driver
+type pgxError struct {
+	code string
+}
+
+func (p pgxError) Error() string {
+	return "ERROR: duplicate key value violates unique constraint \"tags_name_key\" (SQLSTATE " + p.code + ")"
+}
+
+func (p pgxError) SQLState() string {
+	return p.code
+}
+
+func TestTranslateErrorRecordNotFound(t *testing.T) {
+	assert.True(t, errors.Is(translateError(gorm.ErrRecordNotFound), ErrNotFound))
+}
+
+func TestTranslateErrorUniqueViolation(t *testing.T) {
+	assert.True(t, errors.Is(translateError(pgxError{code: "23505"}), ErrDuplicate))
+	assert.True(t, errors.Is(translateError(&pq.Error{Code: "23505"}), ErrDuplicate))
+}
+
+func TestTranslateErrorConnectionFailures(t *testing.T) {
+	assert.True(t, errors.Is(translateError(pgxError{code: "57P01"}), ErrUnavailable))
+	assert.True(t, errors.Is(translateError(&pq.Error{Code: "08006"}), ErrUnavailable))
+	assert.True(t, errors.Is(translateError(driver.ErrBadConn), ErrUnavailable))
+	dialError := &net.OpError{Op: "dial", Net: "tcp", Err: fmt.Errorf("connection refused")}
+	assert.True(t, errors.Is(translateError(fmt.Errorf("failed to connect: %w", dialError)), ErrUnavailable))
+}
+
+func TestTranslateErrorKeepsOtherErrors(t *testing.T) {
+	syntaxError := pgxError{code: "42601"}
+	assert.Equal(t, syntaxError, translateError(syntaxError))
+	assert.Equal(t, context.DeadlineExceeded, translateError(context.DeadlineExceeded))
+	translated := translateError(gorm.ErrRecordNotFound)
+	assert.Equal(t, translated, translateError(translated), "translating twice should change nothing")
+	assert.Nil(t, translateError(nil))
+}
@@ -0,0 +1,176 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"news-topic-api/models"
+	"sort"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+//MemoryAPIKeyRepository keeps the api keys in a MemoryStore, their prefixes stay unique even among deleted keys
+type MemoryAPIKeyRepository struct {
+	store *MemoryStore
+}
+
+//InitMemoryAPIKeyRepository initializes an api key repository given the memory store
+func InitMemoryAPIKeyRepository(store *MemoryStore) IAPIKeyRepository {
+	repository := new(MemoryAPIKeyRepository)
+	repository.store = store
+	return *repository
+}
+
+//Create ...
+func (a MemoryAPIKeyRepository) Create(ctx context.Context, apiKey models.APIKey) (models.APIKey, error) {
+	err := a.store.write(ctx, func(data *memoryData) error {
+		if data.prefixTaken(apiKey.Prefix, 0) {
+			return ErrDuplicate
+		}
+		if apiKey.ID == 0 {
+			data.lastAPIKeyID++
+			apiKey.ID = data.lastAPIKeyID
+		} else if _, found := data.apiKeys[apiKey.ID]; found {
+			return ErrDuplicate
+		} else if apiKey.ID > data.lastAPIKeyID {
+			data.lastAPIKeyID = apiKey.ID
+		}
+		now := a.store.now()
+		apiKey.CreatedAt, apiKey.UpdatedAt = now, now
+		apiKey.Scopes = append(pq.StringArray(nil), apiKey.Scopes...)
+		data.apiKeys[apiKey.ID] = apiKey
+		return nil
+	})
+	if err != nil {
+		return models.APIKey{}, err
+	}
+	return copyAPIKey(apiKey), nil
+}
+
+//GetByID ...
+func (a MemoryAPIKeyRepository) GetByID(ctx context.Context, apiKeyID uint) (models.APIKey, error) {
+	var targetAPIKey models.APIKey
+	err := a.store.read(ctx, func(data *memoryData) error {
+		current, found := data.liveAPIKey(apiKeyID)
+		if !found {
+			return fmt.Errorf("%w: api key %d", ErrNotFound, apiKeyID)
+		}
+		targetAPIKey = copyAPIKey(current)
+		return nil
+	})
+	if err != nil {
+		return models.APIKey{}, err
+	}
+	return targetAPIKey, nil
+}
+
+//GetByPrefix retrieve the api key identified by the public prefix of its plaintext
+func (a MemoryAPIKeyRepository) GetByPrefix(ctx context.Context, prefix string) (models.APIKey, error) {
+	var targetAPIKey models.APIKey
+	err := a.store.read(ctx, func(data *memoryData) error {
+		for _, apiKey := range data.apiKeys {
+			if apiKey.Prefix == prefix && !apiKey.DeletedAt.Valid {
+				targetAPIKey = copyAPIKey(apiKey)
+				return nil
+			}
+		}
+		return fmt.Errorf("%w: api key %s", ErrNotFound, prefix)
+	})
+	if err != nil {
+		return models.APIKey{}, err
+	}
+	return targetAPIKey, nil
+}
+
+//List returns the api keys that are not deleted, the latest first
+func (a MemoryAPIKeyRepository) List(ctx context.Context) ([]models.APIKey, error) {
+	apiKeysList := []models.APIKey{}
+	err := a.store.read(ctx, func(data *memoryData) error {
+		for _, apiKey := range data.apiKeys {
+			if !apiKey.DeletedAt.Valid {
+				apiKeysList = append(apiKeysList, copyAPIKey(apiKey))
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return []models.APIKey{}, err
+	}
+	sort.Slice(apiKeysList, func(i, j int) bool { return apiKeysList[i].ID > apiKeysList[j].ID })
+	return apiKeysList, nil
+}
+
+//UpdateSecret replaces the hashed secret of an api key, keeping its scopes
+func (a MemoryAPIKeyRepository) UpdateSecret(ctx context.Context, apiKeyID uint, prefix string, keyHash string) (models.APIKey, error) {
+	var targetAPIKey models.APIKey
+	err := a.store.write(ctx, func(data *memoryData) error {
+		current, found := data.liveAPIKey(apiKeyID)
+		if !found {
+			return fmt.Errorf("%w: api key %d", ErrNotFound, apiKeyID)
+		}
+		if data.prefixTaken(prefix, apiKeyID) {
+			return ErrDuplicate
+		}
+		current.Prefix = prefix
+		current.KeyHash = keyHash
+		current.UpdatedAt = a.store.now()
+		data.apiKeys[apiKeyID] = current
+		targetAPIKey = copyAPIKey(current)
+		return nil
+	})
+	if err != nil {
+		return models.APIKey{}, err
+	}
+	return targetAPIKey, nil
+}
+
+//Revoke ...
+func (a MemoryAPIKeyRepository) Revoke(ctx context.Context, apiKeyID uint, revokedAt time.Time) error {
+	return a.store.write(ctx, func(data *memoryData) error {
+		current, found := data.liveAPIKey(apiKeyID)
+		if !found {
+			return fmt.Errorf("%w: api key %d", ErrNotFound, apiKeyID)
+		}
+		current.RevokedAt = &revokedAt
+		current.UpdatedAt = a.store.now()
+		data.apiKeys[apiKeyID] = current
+		return nil
+	})
+}
+
+//TouchLastUsed records the last time an api key authenticated a request, a missing key is ignored
+func (a MemoryAPIKeyRepository) TouchLastUsed(ctx context.Context, apiKeyID uint, usedAt time.Time) error {
+	return a.store.write(ctx, func(data *memoryData) error {
+		if current, found := data.liveAPIKey(apiKeyID); found {
+			current.LastUsedAt = &usedAt
+			data.apiKeys[apiKeyID] = current
+		}
+		return nil
+	})
+}
+
+//liveAPIKey returns the api key with the id unless it does not exist or is deleted
+func (d *memoryData) liveAPIKey(apiKeyID uint) (models.APIKey, bool) {
+	apiKey, found := d.apiKeys[apiKeyID]
+	if !found || apiKey.DeletedAt.Valid {
+		return models.APIKey{}, false
+	}
+	return apiKey, true
+}
+
+//prefixTaken reports whether another api key than apiKeyID, deleted or not, uses the prefix
+func (d *memoryData) prefixTaken(prefix string, apiKeyID uint) bool {
+	for id, apiKey := range d.apiKeys {
+		if id != apiKeyID && apiKey.Prefix == prefix {
+			return true
+		}
+	}
+	return false
+}
+
+//copyAPIKey returns the api key with scopes of its own, so callers can not change the stored ones
+func copyAPIKey(apiKey models.APIKey) models.APIKey {
+	apiKey.Scopes = append(pq.StringArray(nil), apiKey.Scopes...)
+	return apiKey
+}
@@ -0,0 +1,66 @@
+package repositories
+
+import (
+	"context"
+	"news-topic-api/models"
+	"sort"
+)
+
+//MemoryAuditRepository reads the audit entries the memory repositories record in a MemoryStore
+type MemoryAuditRepository struct {
+	store *MemoryStore
+}
+
+//InitMemoryAuditRepository initializes an audit repository given the memory store
+func InitMemoryAuditRepository(store *MemoryStore) IAuditRepository {
+	repository := new(MemoryAuditRepository)
+	repository.store = store
+	return *repository
+}
+
+//List retrieve audit entries given filters (entity, actor, time range), newest first
+func (a MemoryAuditRepository) List(ctx context.Context, queryParams map[string]string) ([]models.AuditLog, error) {
+	query, err := parseAuditQuery(queryParams)
+	if err != nil {
+		return []models.AuditLog{}, err
+	}
+	auditLogs := []models.AuditLog{}
+	err = a.store.read(ctx, func(data *memoryData) error {
+		for _, entry := range data.auditLogs {
+			if query.matches(entry) {
+				auditLogs = append(auditLogs, entry)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return []models.AuditLog{}, err
+	}
+	sort.Slice(auditLogs, func(i, j int) bool {
+		if !auditLogs[i].Timestamp.Equal(auditLogs[j].Timestamp) {
+			return auditLogs[i].Timestamp.After(auditLogs[j].Timestamp)
+		}
+		return auditLogs[i].ID > auditLogs[j].ID
+	})
+	if len(auditLogs) > query.limit {
+		auditLogs = auditLogs[:query.limit]
+	}
+	return auditLogs, nil
+}
+
+//matches reports whether the entry passes every filter of the query
+func (q auditQuery) matches(entry models.AuditLog) bool {
+	switch {
+	case q.entityType != "" && entry.EntityType != q.entityType:
+		return false
+	case q.entityID != nil && entry.EntityID != *q.entityID:
+		return false
+	case q.actor != "" && entry.Actor != q.actor:
+		return false
+	case q.from != nil && entry.Timestamp.Before(*q.from):
+		return false
+	case q.to != nil && !entry.Timestamp.Before(*q.to):
+		return false
+	}
+	return true
+}
@@ -0,0 +1,129 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"news-topic-api/models"
+	"sort"
+)
+
+//MemoryAuthorRepository keeps the authors in a MemoryStore
+type MemoryAuthorRepository struct {
+	store *MemoryStore
+}
+
+//InitMemoryAuthorRepository initializes an author repository given the memory store
+func InitMemoryAuthorRepository(store *MemoryStore) IAuthorRepository {
+	repository := new(MemoryAuthorRepository)
+	repository.store = store
+	return *repository
+}
+
+//Create ...
+func (a MemoryAuthorRepository) Create(ctx context.Context, author models.Author) (models.Author, error) {
+	err := a.store.write(ctx, func(data *memoryData) error {
+		if _, found := data.authors[author.ID]; found && author.ID != 0 {
+			return ErrDuplicate
+		}
+		author = data.insertAuthor(author, a.store.now())
+		return nil
+	})
+	if err != nil {
+		return models.Author{}, err
+	}
+	return author, nil
+}
+
+//Update ...
+func (a MemoryAuthorRepository) Update(ctx context.Context, authorID uint, author models.Author) (models.Author, error) {
+	var targetAuthor models.Author
+	err := a.store.write(ctx, func(data *memoryData) error {
+		current, found := data.liveAuthor(authorID)
+		if !found {
+			return fmt.Errorf("%w: author %d", ErrNotFound, authorID)
+		}
+		current.Name = author.Name
+		current.Bio = author.Bio
+		current.Avatar = author.Avatar
+		current.UpdatedAt = a.store.now()
+		data.authors[authorID] = current
+		targetAuthor = current
+		return nil
+	})
+	if err != nil {
+		return models.Author{}, err
+	}
+	return targetAuthor, nil
+}
+
+//Delete soft deletes the author, the news keep their link to it but no longer load it
+func (a MemoryAuthorRepository) Delete(ctx context.Context, authorID uint) error {
+	return a.store.write(ctx, func(data *memoryData) error {
+		current, found := data.liveAuthor(authorID)
+		if !found {
+			return fmt.Errorf("%w: author %d", ErrNotFound, authorID)
+		}
+		current.DeletedAt = softDeleted(a.store.now())
+		data.authors[authorID] = current
+		return nil
+	})
+}
+
+//GetByID ...
+func (a MemoryAuthorRepository) GetByID(ctx context.Context, authorID uint) (models.Author, error) {
+	var targetAuthor models.Author
+	err := a.store.read(ctx, func(data *memoryData) error {
+		current, found := data.liveAuthor(authorID)
+		if !found {
+			return fmt.Errorf("%w: author %d", ErrNotFound, authorID)
+		}
+		targetAuthor = current
+		return nil
+	})
+	if err != nil {
+		return models.Author{}, err
+	}
+	return targetAuthor, nil
+}
+
+//List returns the authors that are not deleted, the latest first
+func (a MemoryAuthorRepository) List(ctx context.Context) ([]models.Author, error) {
+	authorsList := []models.Author{}
+	err := a.store.read(ctx, func(data *memoryData) error {
+		for _, author := range data.authors {
+			if !author.DeletedAt.Valid {
+				authorsList = append(authorsList, author)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return []models.Author{}, err
+	}
+	sort.Slice(authorsList, func(i, j int) bool { return authorsList[i].ID > authorsList[j].ID })
+	return authorsList, nil
+}
+
+//FindByIDs returns the authors with the given ids, ids without an author are left out. Within a transaction of the
+//memory store no other write can delete them until it ends
+func (a MemoryAuthorRepository) FindByIDs(ctx context.Context, authorIDs []uint) ([]models.Author, error) {
+	var authors []models.Author
+	if len(authorIDs) == 0 {
+		return authors, nil
+	}
+	err := a.store.read(ctx, func(data *memoryData) error {
+		seen := map[uint]bool{}
+		for _, authorID := range authorIDs {
+			if author, found := data.liveAuthor(authorID); found && !seen[authorID] {
+				seen[authorID] = true
+				authors = append(authors, author)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return []models.Author{}, err
+	}
+	sort.Slice(authors, func(i, j int) bool { return authors[i].ID < authors[j].ID })
+	return authors, nil
+}
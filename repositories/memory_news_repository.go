@@ -0,0 +1,237 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"news-topic-api/models"
+	"sort"
+	"strconv"
+
+	"gorm.io/gorm"
+)
+
+//MemoryNewsRepository keeps the news in a MemoryStore and records the audit entries it is given with each write
+type MemoryNewsRepository struct {
+	store *MemoryStore
+}
+
+//InitMemoryNewsRepository initializes a news repository given the memory store
+func InitMemoryNewsRepository(store *MemoryStore) INewsRepository {
+	repository := new(MemoryNewsRepository)
+	repository.store = store
+	return *repository
+}
+
+//Create stores the news and links its tags and authors
+func (n MemoryNewsRepository) Create(ctx context.Context, news models.News, audit models.AuditLog) (models.News, error) {
+	err := n.store.write(ctx, func(data *memoryData) error {
+		now := n.store.now()
+		created, err := data.insertNews(news, now)
+		if err != nil {
+			return err
+		}
+		news = created
+		audit.After = newsSnapshot(news)
+		data.recordAudit(audit, news.ID, now)
+		return nil
+	})
+	if err != nil {
+		return models.News{}, err
+	}
+	return news, nil
+}
+
+//CreateBatch stores the news with their tag and author links all at once, the batch size has no meaning in memory
+func (n MemoryNewsRepository) CreateBatch(ctx context.Context, newsList []models.News, batchSize int, audit models.AuditLog) ([]models.News, error) {
+	if len(newsList) == 0 {
+		return newsList, nil
+	}
+	created := make([]models.News, len(newsList))
+	err := n.store.write(ctx, func(data *memoryData) error {
+		now := n.store.now()
+		for i, news := range newsList {
+			inserted, err := data.insertNews(news, now)
+			if err != nil {
+				return err
+			}
+			created[i] = inserted
+		}
+		for _, news := range created {
+			entry := audit
+			entry.After = newsSnapshot(news)
+			data.recordAudit(entry, news.ID, now)
+		}
+		return nil
+	})
+	if err != nil {
+		return []models.News{}, err
+	}
+	return created, nil
+}
+
+//Update updates the news and replaces its tags and authors
+func (n MemoryNewsRepository) Update(ctx context.Context, newsID uint, news models.News, audit models.AuditLog) (models.News, error) {
+	var targetNews models.News
+	err := n.store.write(ctx, func(data *memoryData) error {
+		current, found := data.liveNews(newsID)
+		if !found {
+			return fmt.Errorf("%w: news %d", ErrNotFound, newsID)
+		}
+		now := n.store.now()
+		audit.Before = newsSnapshot(data.withAssociations(current))
+		current.Title = news.Title
+		current.Thumbnail = news.Thumbnail
+		current.Summary = news.Summary
+		current.Content = news.Content
+		current.Topic = news.Topic
+		current.Status = news.Status
+		current.UpdatedAt = now
+		current.Tags, current.Authors = nil, nil
+		data.news[newsID] = current
+		if err := data.linkTags(newsID, news.Tags, now); err != nil {
+			return err
+		}
+		data.linkAuthors(newsID, news.Authors, now)
+		targetNews = data.withAssociations(current)
+		audit.After = newsSnapshot(targetNews)
+		data.recordAudit(audit, newsID, now)
+		return nil
+	})
+	if err != nil {
+		return models.News{}, err
+	}
+	return targetNews, nil
+}
+
+//Delete soft deletes the news, its links are kept like the rows of news_tag and news_author
+func (n MemoryNewsRepository) Delete(ctx context.Context, newsID uint, audit models.AuditLog) error {
+	return n.store.write(ctx, func(data *memoryData) error {
+		current, found := data.liveNews(newsID)
+		if !found {
+			return fmt.Errorf("%w: news %d", ErrNotFound, newsID)
+		}
+		now := n.store.now()
+		audit.Before = newsSnapshot(data.withAssociations(current))
+		current.DeletedAt = softDeleted(now)
+		data.news[newsID] = current
+		data.recordAudit(audit, newsID, now)
+		return nil
+	})
+}
+
+//Restore brings back soft deleted news with the links it kept, news that is not deleted is not found
+func (n MemoryNewsRepository) Restore(ctx context.Context, newsID uint, audit models.AuditLog) (models.News, error) {
+	var targetNews models.News
+	err := n.store.write(ctx, func(data *memoryData) error {
+		current, found := data.deletedNews(newsID)
+		if !found {
+			return fmt.Errorf("%w: news %d", ErrNotFound, newsID)
+		}
+		now := n.store.now()
+		current.DeletedAt = gorm.DeletedAt{}
+		current.UpdatedAt = now
+		data.news[newsID] = current
+		targetNews = data.withAssociations(current)
+		audit.After = newsSnapshot(targetNews)
+		data.recordAudit(audit, newsID, now)
+		return nil
+	})
+	if err != nil {
+		return models.News{}, err
+	}
+	return targetNews, nil
+}
+
+//GetByID returns the news with its tags and authors
+func (n MemoryNewsRepository) GetByID(ctx context.Context, newsID uint) (models.News, error) {
+	var targetNews models.News
+	err := n.store.read(ctx, func(data *memoryData) error {
+		current, found := data.liveNews(newsID)
+		if !found {
+			return fmt.Errorf("%w: news %d", ErrNotFound, newsID)
+		}
+		targetNews = data.withAssociations(current)
+		return nil
+	})
+	if err != nil {
+		return models.News{}, err
+	}
+	return targetNews, nil
+}
+
+//List retrieve list of news given filters (topic, status, tag and author), the latest first
+func (n MemoryNewsRepository) List(ctx context.Context, queryParams map[string]string) ([]models.News, error) {
+	filters := []func(data *memoryData, news models.News) bool{}
+	if tag := queryParams["tag"]; tag != "" {
+		tagID, err := strconv.Atoi(tag)
+		if err != nil {
+			return []models.News{}, InvalidFilterError{Field: "tag", Reason: "invalid format for tag"}
+		}
+		filters = append(filters, func(data *memoryData, news models.News) bool {
+			for _, linked := range data.newsTags[news.ID] {
+				if linked == uint(tagID) {
+					return true
+				}
+			}
+			return false
+		})
+	}
+	if author := queryParams["author"]; author != "" {
+		authorID, err := strconv.Atoi(author)
+		if err != nil {
+			return []models.News{}, InvalidFilterError{Field: "author", Reason: "invalid format for author"}
+		}
+		filters = append(filters, func(data *memoryData, news models.News) bool {
+			for _, linked := range data.newsAuthors[news.ID] {
+				if linked == uint(authorID) {
+					return true
+				}
+			}
+			return false
+		})
+	}
+	if topic := queryParams["topic"]; topic != "" {
+		filters = append(filters, func(data *memoryData, news models.News) bool { return news.Topic == topic })
+	}
+	if status := queryParams["status"]; status != "" {
+		filters = append(filters, func(data *memoryData, news models.News) bool { return news.Status == status })
+	}
+	newsList := []models.News{}
+	err := n.store.read(ctx, func(data *memoryData) error {
+	next:
+		for _, news := range data.news {
+			if news.DeletedAt.Valid {
+				continue
+			}
+			for _, matches := range filters {
+				if !matches(data, news) {
+					continue next
+				}
+			}
+			newsList = append(newsList, data.withAssociations(news))
+		}
+		return nil
+	})
+	if err != nil {
+		return []models.News{}, err
+	}
+	sort.Slice(newsList, func(i, j int) bool { return newsList[i].ID > newsList[j].ID })
+	return newsList, nil
+}
+
+//CountByStatus counts the news that are not deleted, grouped by status
+func (n MemoryNewsRepository) CountByStatus(ctx context.Context) (map[string]int64, error) {
+	counts := map[string]int64{}
+	err := n.store.read(ctx, func(data *memoryData) error {
+		for _, news := range data.news {
+			if !news.DeletedAt.Valid {
+				counts[news.Status]++
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return map[string]int64{}, err
+	}
+	return counts, nil
+}
@@ -0,0 +1,368 @@
+package repositories
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"news-topic-api/models"
+
+	"gorm.io/gorm"
+)
+
+//MemoryStore keeps news, tags, authors, api keys and the audit log in memory with the semantics of their Postgres
+//tables: rows are soft deleted, tag names and key prefixes stay unique even among deleted rows and links to deleted
+//tags and authors are kept but not loaded.
+//It is safe for concurrent use, writes are serialized and transactions see their own writes only
+type MemoryStore struct {
+	//writeMu serializes writes, a transaction holds it until it commits or rolls back
+	writeMu sync.Mutex
+	//mu guards data, readers never wait for a transaction, only for the commit swapping data
+	mu   sync.RWMutex
+	data memoryData
+	now  func() time.Time
+}
+
+type memoryData struct {
+	lastNewsID   uint
+	lastTagID    uint
+	lastAuthorID uint
+	lastAPIKeyID uint
+	lastAuditID  uint
+	news         map[uint]models.News
+	tags         map[uint]models.Tag
+	authors      map[uint]models.Author
+	apiKeys      map[uint]models.APIKey
+	newsTags     map[uint][]uint
+	newsAuthors  map[uint][]uint
+	auditLogs    []models.AuditLog
+}
+
+//InitMemoryStore initializes an empty store
+func InitMemoryStore() *MemoryStore {
+	store := new(MemoryStore)
+	store.data = memoryData{
+		news:        map[uint]models.News{},
+		tags:        map[uint]models.Tag{},
+		authors:     map[uint]models.Author{},
+		apiKeys:     map[uint]models.APIKey{},
+		newsTags:    map[uint][]uint{},
+		newsAuthors: map[uint][]uint{},
+	}
+	store.now = time.Now
+	return store
+}
+
+//read runs fn with the committed data
+func (s *MemoryStore) read(ctx context.Context, fn func(data *memoryData) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return fn(&s.data)
+}
+
+//write runs fn on a copy of the data that replaces it when fn returns nil, so a failed write leaves no trace
+func (s *MemoryStore) write(ctx context.Context, fn func(data *memoryData) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	return s.commit(fn)
+}
+
+func (s *MemoryStore) commit(fn func(data *memoryData) error) error {
+	s.mu.RLock()
+	draft := s.data.clone()
+	s.mu.RUnlock()
+	if err := fn(&draft); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	s.data = draft
+	s.mu.Unlock()
+	return nil
+}
+
+func (d memoryData) clone() memoryData {
+	cloned := memoryData{
+		lastNewsID:   d.lastNewsID,
+		lastTagID:    d.lastTagID,
+		lastAuthorID: d.lastAuthorID,
+		lastAPIKeyID: d.lastAPIKeyID,
+		lastAuditID:  d.lastAuditID,
+		news:         make(map[uint]models.News, len(d.news)),
+		tags:         make(map[uint]models.Tag, len(d.tags)),
+		authors:      make(map[uint]models.Author, len(d.authors)),
+		apiKeys:      make(map[uint]models.APIKey, len(d.apiKeys)),
+		newsTags:     make(map[uint][]uint, len(d.newsTags)),
+		newsAuthors:  make(map[uint][]uint, len(d.newsAuthors)),
+		auditLogs:    append([]models.AuditLog(nil), d.auditLogs...),
+	}
+	for id, news := range d.news {
+		cloned.news[id] = news
+	}
+	for id, tag := range d.tags {
+		cloned.tags[id] = tag
+	}
+	for id, author := range d.authors {
+		cloned.authors[id] = author
+	}
+	for id, apiKey := range d.apiKeys {
+		cloned.apiKeys[id] = apiKey
+	}
+	for id, tagIDs := range d.newsTags {
+		cloned.newsTags[id] = append([]uint(nil), tagIDs...)
+	}
+	for id, authorIDs := range d.newsAuthors {
+		cloned.newsAuthors[id] = append([]uint(nil), authorIDs...)
+	}
+	return cloned
+}
+
+//liveNews returns the news with the id unless it does not exist or is deleted
+func (d *memoryData) liveNews(newsID uint) (models.News, bool) {
+	news, found := d.news[newsID]
+	if !found || news.DeletedAt.Valid {
+		return models.News{}, false
+	}
+	return news, true
+}
+
+//liveTag returns the tag with the id unless it does not exist or is deleted
+func (d *memoryData) liveTag(tagID uint) (models.Tag, bool) {
+	tag, found := d.tags[tagID]
+	if !found || tag.DeletedAt.Valid {
+		return models.Tag{}, false
+	}
+	return tag, true
+}
+
+//deletedNews returns the news with the id only if it is soft deleted
+func (d *memoryData) deletedNews(newsID uint) (models.News, bool) {
+	news, found := d.news[newsID]
+	if !found || !news.DeletedAt.Valid {
+		return models.News{}, false
+	}
+	return news, true
+}
+
+//deletedTag returns the tag with the id only if it is soft deleted
+func (d *memoryData) deletedTag(tagID uint) (models.Tag, bool) {
+	tag, found := d.tags[tagID]
+	if !found || !tag.DeletedAt.Valid {
+		return models.Tag{}, false
+	}
+	return tag, true
+}
+
+//liveAuthor returns the author with the id unless it does not exist or is deleted
+func (d *memoryData) liveAuthor(authorID uint) (models.Author, bool) {
+	author, found := d.authors[authorID]
+	if !found || author.DeletedAt.Valid {
+		return models.Author{}, false
+	}
+	return author, true
+}
+
+//withAssociations returns the news with its live tags and authors, like loadAssociations
+func (d *memoryData) withAssociations(news models.News) models.News {
+	news.Tags = []models.Tag{}
+	for _, tagID := range d.newsTags[news.ID] {
+		if tag, found := d.liveTag(tagID); found {
+			news.Tags = append(news.Tags, tag)
+		}
+	}
+	news.Authors = []models.Author{}
+	for _, authorID := range d.newsAuthors[news.ID] {
+		if author, found := d.liveAuthor(authorID); found {
+			news.Authors = append(news.Authors, author)
+		}
+	}
+	return news
+}
+
+//insertTag stores a new tag, failing with ErrDuplicate when its name is taken
+func (d *memoryData) insertTag(tag models.Tag, now time.Time) (models.Tag, error) {
+	for _, existing := range d.tags {
+		if existing.Name == tag.Name {
+			return models.Tag{}, ErrDuplicate
+		}
+	}
+	if tag.ID == 0 {
+		d.lastTagID++
+		tag.ID = d.lastTagID
+	} else if _, found := d.tags[tag.ID]; found {
+		return models.Tag{}, ErrDuplicate
+	} else if tag.ID > d.lastTagID {
+		d.lastTagID = tag.ID
+	}
+	if tag.CreatedAt.IsZero() {
+		tag.CreatedAt = now
+	}
+	if tag.UpdatedAt.IsZero() {
+		tag.UpdatedAt = now
+	}
+	d.tags[tag.ID] = tag
+	return tag, nil
+}
+
+//insertNews stores a new news with its tag and author links and returns it as loaded back
+func (d *memoryData) insertNews(news models.News, now time.Time) (models.News, error) {
+	if news.ID == 0 {
+		d.lastNewsID++
+		news.ID = d.lastNewsID
+	} else if _, found := d.news[news.ID]; found {
+		return models.News{}, ErrDuplicate
+	} else if news.ID > d.lastNewsID {
+		d.lastNewsID = news.ID
+	}
+	if news.CreatedAt.IsZero() {
+		news.CreatedAt = now
+	}
+	if news.UpdatedAt.IsZero() {
+		news.UpdatedAt = now
+	}
+	if err := d.linkTags(news.ID, news.Tags, now); err != nil {
+		return models.News{}, err
+	}
+	d.linkAuthors(news.ID, news.Authors, now)
+	news.Tags, news.Authors = nil, nil
+	d.news[news.ID] = news
+	return d.withAssociations(news), nil
+}
+
+//linkTags replaces the tags of the news, new tags are inserted and unknown ids are kept like the
+//upsert of the Postgres association
+func (d *memoryData) linkTags(newsID uint, tags []models.Tag, now time.Time) error {
+	tagIDs := []uint{}
+	seen := map[uint]bool{}
+	for _, tag := range tags {
+		if _, found := d.tags[tag.ID]; !found || tag.ID == 0 {
+			inserted, err := d.insertTag(tag, now)
+			if err != nil {
+				return err
+			}
+			tag = inserted
+		}
+		if !seen[tag.ID] {
+			seen[tag.ID] = true
+			tagIDs = append(tagIDs, tag.ID)
+		}
+	}
+	d.newsTags[newsID] = tagIDs
+	return nil
+}
+
+//recordAudit completes the audit entry and stores it with the write it describes, like recordAudit does with a transaction
+func (d *memoryData) recordAudit(entry models.AuditLog, entityID uint, now time.Time) {
+	d.lastAuditID++
+	entry.ID = d.lastAuditID
+	entry.EntityID = entityID
+	entry.Timestamp = now.UTC()
+	d.auditLogs = append(d.auditLogs, entry)
+}
+
+//insertAuthor stores a new author
+func (d *memoryData) insertAuthor(author models.Author, now time.Time) models.Author {
+	if author.ID == 0 {
+		d.lastAuthorID++
+		author.ID = d.lastAuthorID
+	} else if author.ID > d.lastAuthorID {
+		d.lastAuthorID = author.ID
+	}
+	if author.CreatedAt.IsZero() {
+		author.CreatedAt = now
+	}
+	if author.UpdatedAt.IsZero() {
+		author.UpdatedAt = now
+	}
+	d.authors[author.ID] = author
+	return author
+}
+
+//linkAuthors replaces the authors of the news, unknown authors are inserted like the upsert of the Postgres association
+func (d *memoryData) linkAuthors(newsID uint, authors []models.Author, now time.Time) {
+	authorIDs := []uint{}
+	seen := map[uint]bool{}
+	for _, author := range authors {
+		if _, found := d.authors[author.ID]; !found || author.ID == 0 {
+			author = d.insertAuthor(author, now)
+		}
+		if !seen[author.ID] {
+			seen[author.ID] = true
+			authorIDs = append(authorIDs, author.ID)
+		}
+	}
+	d.newsAuthors[newsID] = authorIDs
+}
+
+//sortedIDs returns the ids of the map in ascending order
+func sortedIDs(ids map[uint]bool) []uint {
+	sorted := make([]uint, 0, len(ids))
+	for id := range ids {
+		sorted = append(sorted, id)
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	return sorted
+}
+
+func softDeleted(now time.Time) gorm.DeletedAt {
+	return gorm.DeletedAt{Time: now, Valid: true}
+}
+
+//MemoryTransactionManager runs the calls of a unit of work on a MemoryStore atomically
+type MemoryTransactionManager struct {
+	store *MemoryStore
+}
+
+//InitMemoryTransactionManager initializes a transaction manager over the store
+func InitMemoryTransactionManager(store *MemoryStore) ITransactionManager {
+	transactionManager := new(MemoryTransactionManager)
+	transactionManager.store = store
+	return *transactionManager
+}
+
+//WithinTransaction runs fn on a copy of the store that replaces it when fn returns nil,
+//other writers wait until then while readers keep seeing the data committed before
+func (m MemoryTransactionManager) WithinTransaction(ctx context.Context, fn func(uow IUnitOfWork) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	m.store.writeMu.Lock()
+	defer m.store.writeMu.Unlock()
+	return m.store.commit(func(data *memoryData) error {
+		tx := &MemoryStore{data: *data, now: m.store.now}
+		if err := fn(memoryUnitOfWork{tx: tx}); err != nil {
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		*data = tx.data
+		return nil
+	})
+}
+
+type memoryUnitOfWork struct {
+	tx *MemoryStore
+}
+
+func (u memoryUnitOfWork) News() INewsRepository {
+	return InitMemoryNewsRepository(u.tx)
+}
+
+func (u memoryUnitOfWork) Tags() ITagRepository {
+	return InitMemoryTagRepository(u.tx)
+}
+
+func (u memoryUnitOfWork) Authors() IAuthorRepository {
+	return InitMemoryAuthorRepository(u.tx)
+}
+
+func (u memoryUnitOfWork) Audit() IAuditRepository {
+	return InitMemoryAuditRepository(u.tx)
+}
@@ -0,0 +1,234 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"news-topic-api/models"
+	"sort"
+
+	"gorm.io/gorm"
+)
+
+//MemoryTagRepository keeps the tags in a MemoryStore and records the audit entries it is given with each write
+type MemoryTagRepository struct {
+	store *MemoryStore
+}
+
+//InitMemoryTagRepository initializes a tag repository given the memory store
+func InitMemoryTagRepository(store *MemoryStore) ITagRepository {
+	repository := new(MemoryTagRepository)
+	repository.store = store
+	return *repository
+}
+
+//Create stores the tag, its name must not be used by any tag, deleted ones included
+func (t MemoryTagRepository) Create(ctx context.Context, tag models.Tag, audit models.AuditLog) (models.Tag, error) {
+	err := t.store.write(ctx, func(data *memoryData) error {
+		now := t.store.now()
+		created, err := data.insertTag(tag, now)
+		if err != nil {
+			return err
+		}
+		tag = created
+		audit.After = tagSnapshot(tag)
+		data.recordAudit(audit, tag.ID, now)
+		return nil
+	})
+	if err != nil {
+		return models.Tag{}, err
+	}
+	return tag, nil
+}
+
+//Update renames the tag
+func (t MemoryTagRepository) Update(ctx context.Context, tagID uint, tag models.Tag, audit models.AuditLog) (models.Tag, error) {
+	var targetTag models.Tag
+	err := t.store.write(ctx, func(data *memoryData) error {
+		current, found := data.liveTag(tagID)
+		if !found {
+			return fmt.Errorf("%w: tag %d", ErrNotFound, tagID)
+		}
+		for id, existing := range data.tags {
+			if id != tagID && existing.Name == tag.Name {
+				return ErrDuplicate
+			}
+		}
+		now := t.store.now()
+		audit.Before = tagSnapshot(current)
+		current.Name = tag.Name
+		current.UpdatedAt = now
+		data.tags[tagID] = current
+		targetTag = current
+		audit.After = tagSnapshot(targetTag)
+		data.recordAudit(audit, tagID, now)
+		return nil
+	})
+	if err != nil {
+		return models.Tag{}, err
+	}
+	return targetTag, nil
+}
+
+//Delete soft deletes the tag, the news keep their link to it but no longer load it
+func (t MemoryTagRepository) Delete(ctx context.Context, tagID uint, audit models.AuditLog) error {
+	return t.store.write(ctx, func(data *memoryData) error {
+		current, found := data.liveTag(tagID)
+		if !found {
+			return fmt.Errorf("%w: tag %d", ErrNotFound, tagID)
+		}
+		now := t.store.now()
+		audit.Before = tagSnapshot(current)
+		current.DeletedAt = softDeleted(now)
+		data.tags[tagID] = current
+		data.recordAudit(audit, tagID, now)
+		return nil
+	})
+}
+
+//Restore brings back a soft deleted tag, its name stayed taken while deleted
+func (t MemoryTagRepository) Restore(ctx context.Context, tagID uint, audit models.AuditLog) (models.Tag, error) {
+	var targetTag models.Tag
+	err := t.store.write(ctx, func(data *memoryData) error {
+		current, found := data.deletedTag(tagID)
+		if !found {
+			return fmt.Errorf("%w: tag %d", ErrNotFound, tagID)
+		}
+		now := t.store.now()
+		current.DeletedAt = gorm.DeletedAt{}
+		current.UpdatedAt = now
+		data.tags[tagID] = current
+		targetTag = current
+		audit.After = tagSnapshot(targetTag)
+		data.recordAudit(audit, tagID, now)
+		return nil
+	})
+	if err != nil {
+		return models.Tag{}, err
+	}
+	return targetTag, nil
+}
+
+//CreateBatch stores the tags all at once, the batch size has no meaning in memory
+func (t MemoryTagRepository) CreateBatch(ctx context.Context, tags []models.Tag, batchSize int, audit models.AuditLog) ([]models.Tag, error) {
+	if len(tags) == 0 {
+		return tags, nil
+	}
+	created := make([]models.Tag, len(tags))
+	err := t.store.write(ctx, func(data *memoryData) error {
+		now := t.store.now()
+		for i, tag := range tags {
+			inserted, err := data.insertTag(tag, now)
+			if err != nil {
+				return err
+			}
+			created[i] = inserted
+		}
+		for _, tag := range created {
+			entry := audit
+			entry.After = tagSnapshot(tag)
+			data.recordAudit(entry, tag.ID, now)
+		}
+		return nil
+	})
+	if err != nil {
+		return []models.Tag{}, err
+	}
+	return created, nil
+}
+
+//Merge moves the news of the source tags to the target tag and deletes the sources at once
+func (t MemoryTagRepository) Merge(ctx context.Context, targetID uint, sourceIDs []uint, audit models.AuditLog) (models.Tag, error) {
+	var targetTag models.Tag
+	err := t.store.write(ctx, func(data *memoryData) error {
+		target, found := data.liveTag(targetID)
+		if !found {
+			return fmt.Errorf("%w: tag %d", ErrNotFound, targetID)
+		}
+		sources := map[uint]bool{}
+		for _, sourceID := range sourceIDs {
+			if _, found := data.liveTag(sourceID); found {
+				sources[sourceID] = true
+			}
+		}
+		if len(sources) != len(sourceIDs) {
+			return fmt.Errorf("%w: only %d of the %d tags to merge exist", ErrNotFound, len(sources), len(sourceIDs))
+		}
+		for newsID, tagIDs := range data.newsTags {
+			merged := []uint{}
+			linked, hasTarget := false, false
+			for _, tagID := range tagIDs {
+				if sources[tagID] {
+					linked = true
+					continue
+				}
+				hasTarget = hasTarget || tagID == targetID
+				merged = append(merged, tagID)
+			}
+			if !linked {
+				continue
+			}
+			if !hasTarget {
+				merged = append(merged, targetID)
+			}
+			data.newsTags[newsID] = merged
+		}
+		now := t.store.now()
+		for _, sourceID := range sortedIDs(sources) {
+			source := data.tags[sourceID]
+			entry := audit
+			entry.Before = tagSnapshot(source)
+			entry.After = tagSnapshot(target)
+			source.DeletedAt = softDeleted(now)
+			data.tags[sourceID] = source
+			data.recordAudit(entry, sourceID, now)
+		}
+		targetTag = target
+		return nil
+	})
+	if err != nil {
+		return models.Tag{}, err
+	}
+	return targetTag, nil
+}
+
+//FindByIDs returns the tags with the given ids, ids without a tag are left out. Within a transaction of the
+//memory store no other write can delete them until it ends
+func (t MemoryTagRepository) FindByIDs(ctx context.Context, tagIDs []uint) ([]models.Tag, error) {
+	var tags []models.Tag
+	if len(tagIDs) == 0 {
+		return tags, nil
+	}
+	err := t.store.read(ctx, func(data *memoryData) error {
+		seen := map[uint]bool{}
+		for _, tagID := range tagIDs {
+			if tag, found := data.liveTag(tagID); found && !seen[tagID] {
+				seen[tagID] = true
+				tags = append(tags, tag)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return []models.Tag{}, err
+	}
+	sort.Slice(tags, func(i, j int) bool { return tags[i].ID < tags[j].ID })
+	return tags, nil
+}
+
+//List returns the tags that are not deleted, the latest first
+func (t MemoryTagRepository) List(ctx context.Context) ([]models.Tag, error) {
+	tagsList := []models.Tag{}
+	err := t.store.read(ctx, func(data *memoryData) error {
+		for _, tag := range data.tags {
+			if !tag.DeletedAt.Valid {
+				tagsList = append(tagsList, tag)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return []models.Tag{}, err
+	}
+	sort.Slice(tagsList, func(i, j int) bool { return tagsList[i].ID > tagsList[j].ID })
+	return tagsList, nil
+}
@@ -1,99 +1,212 @@
 package repositories
 
 import (
-	"news-topic-api/infrastructures"
+	"context"
 	"news-topic-api/models"
 	"strconv"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 //INewsRepository interface for news repository
 type INewsRepository interface {
-	Create(news models.News) (models.News, error)
-	Update(newsID uint, news models.News) (models.News, error)
-	Delete(newsID uint) (error)
-	GetByID(penyitaanID uint) (models.News, error)
-	List(queryParams map[string]string) ([]models.News, error)
+	Create(ctx context.Context, news models.News, audit models.AuditLog) (models.News, error)
+	Update(ctx context.Context, newsID uint, news models.News, audit models.AuditLog) (models.News, error)
+	Delete(ctx context.Context, newsID uint, audit models.AuditLog) (error)
+	Restore(ctx context.Context, newsID uint, audit models.AuditLog) (models.News, error)
+	GetByID(ctx context.Context, penyitaanID uint) (models.News, error)
+	List(ctx context.Context, queryParams map[string]string) ([]models.News, error)
+	CountByStatus(ctx context.Context) (map[string]int64, error)
+	CreateBatch(ctx context.Context, newsList []models.News, batchSize int, audit models.AuditLog) ([]models.News, error)
 }
 
 //NewsRepository ...
 type NewsRepository struct{
+	db *gorm.DB
+}
+
+//InitNewsRepository initializes a news repository given the database handle
+func InitNewsRepository(db *gorm.DB) INewsRepository {
+	repository := new(NewsRepository)
+	repository.db = db
+	return *repository
 }
 
-//Create ...
-func (n NewsRepository) Create(news models.News) (models.News, error) {
-	db := infrastructures.GetDB()
-	err := db.Create(&news).Error
-	db.Model(&news).Association("Tags").Find(&news.Tags)
+//Create inserts the news and its audit entry in one transaction
+func (n NewsRepository) Create(ctx context.Context, news models.News, audit models.AuditLog) (models.News, error) {
+	db := n.db.WithContext(ctx)
+	err := inTransaction(db, func(tx *gorm.DB) error {
+		if err := tx.Create(&news).Error; err != nil {
+			return err
+		}
+		if err := loadAssociations(tx, &news); err != nil {
+			return err
+		}
+		audit.After = newsSnapshot(news)
+		return recordAudit(tx, audit, news.ID)
+	})
 	return news, err
 }
 
-//Update ...
-func (n NewsRepository) Update(newsID uint, news models.News) (models.News, error) {
-	var targetNews models.News
-	db := infrastructures.GetDB()
-	err := db.Where("id = ?", newsID).First(&targetNews).Error
-	if err != nil {
-		return models.News{}, err
+//CreateBatch inserts the news with their tag and author links batchSize rows at a time and audits each of them,
+//all in one transaction, the tags and authors must already exist
+func (n NewsRepository) CreateBatch(ctx context.Context, newsList []models.News, batchSize int, audit models.AuditLog) ([]models.News, error) {
+	if len(newsList) == 0 {
+		return newsList, nil
 	}
-	updateData := map[string]interface{} {
-		"title": news.Title,
-		"thumbnail": news.Thumbnail,
-		"summary": news.Summary,
-		"content": news.Content,
-		"topic": news.Topic,
-		"status": news.Status,
+	db := n.db.WithContext(ctx)
+	err := inTransaction(db, func(tx *gorm.DB) error {
+		err := inBatches(len(newsList), batchSize, func(start int, end int) error {
+			batch := newsList[start:end]
+			return tx.Create(&batch).Error
+		})
+		if err != nil {
+			return err
+		}
+		entries := make([]models.AuditLog, len(newsList))
+		for i, news := range newsList {
+			entries[i] = audit
+			entries[i].EntityID = news.ID
+			entries[i].After = newsSnapshot(news)
+		}
+		return recordAuditBatch(tx, entries, batchSize)
+	})
+	if err != nil {
+		return []models.News{}, err
 	}
-	err = db.Model(&targetNews).Omit("created_at").Updates(updateData).Error
+	return newsList, nil
+}
+
+//Update updates the news and records its previous and new state in one transaction
+func (n NewsRepository) Update(ctx context.Context, newsID uint, news models.News, audit models.AuditLog) (models.News, error) {
+	var targetNews models.News
+	db := n.db.WithContext(ctx)
+	err := inTransaction(db, func(tx *gorm.DB) error {
+		err := tx.Where("id = ?", newsID).First(&targetNews).Error
+		if err != nil {
+			return err
+		}
+		if err := loadAssociations(tx, &targetNews); err != nil {
+			return err
+		}
+		audit.Before = newsSnapshot(targetNews)
+		updateData := map[string]interface{} {
+			"title": news.Title,
+			"thumbnail": news.Thumbnail,
+			"summary": news.Summary,
+			"content": news.Content,
+			"topic": news.Topic,
+			"status": news.Status,
+		}
+		// the associations loaded for the audit snapshot are replaced below, not saved with the update
+		err = tx.Model(&targetNews).Omit(clause.Associations, "created_at").Updates(updateData).Error
+		if err != nil {
+			return err
+		}
+		if err := tx.Model(&targetNews).Association("Tags").Replace(news.Tags); err != nil {
+			return err
+		}
+		if err := tx.Model(&targetNews).Association("Authors").Replace(news.Authors); err != nil {
+			return err
+		}
+		if err := loadAssociations(tx, &targetNews); err != nil {
+			return err
+		}
+		audit.After = newsSnapshot(targetNews)
+		return recordAudit(tx, audit, targetNews.ID)
+	})
 	if err != nil {
 		return models.News{}, err
 	}
-	db.Model(&targetNews).Association("Tags").Replace(news.Tags)
-	db.Model(&targetNews).Association("Tags").Find(&targetNews.Tags)
 	return targetNews, nil
 }
 
-//Delete ...
-func (n NewsRepository) Delete(newsID uint) (error) {
+//Delete soft deletes the news and records its last state in one transaction
+func (n NewsRepository) Delete(ctx context.Context, newsID uint, audit models.AuditLog) (error) {
 	var targetNews models.News
-	db := infrastructures.GetDB()
-	err := db.Where("id = ?", newsID).First(&targetNews).Error
-	if err != nil {
-		return err
-	}
-	err = db.Delete(&targetNews).Error
+	db := n.db.WithContext(ctx)
+	return inTransaction(db, func(tx *gorm.DB) error {
+		err := tx.Where("id = ?", newsID).First(&targetNews).Error
+		if err != nil {
+			return err
+		}
+		if err := loadAssociations(tx, &targetNews); err != nil {
+			return err
+		}
+		audit.Before = newsSnapshot(targetNews)
+		err = tx.Delete(&targetNews).Error
+		if err != nil {
+			return err
+		}
+		return recordAudit(tx, audit, targetNews.ID)
+	})
+}
+
+//Restore brings back soft deleted news with the tags and authors it kept and records it in one transaction,
+//news that is not deleted is not found
+func (n NewsRepository) Restore(ctx context.Context, newsID uint, audit models.AuditLog) (models.News, error) {
+	var targetNews models.News
+	db := n.db.WithContext(ctx)
+	err := inTransaction(db, func(tx *gorm.DB) error {
+		err := tx.Unscoped().Where("id = ? AND deleted_at IS NOT NULL", newsID).First(&targetNews).Error
+		if err != nil {
+			return err
+		}
+		err = tx.Unscoped().Model(&targetNews).Update("deleted_at", nil).Error
+		if err != nil {
+			return err
+		}
+		targetNews.DeletedAt = gorm.DeletedAt{}
+		if err := loadAssociations(tx, &targetNews); err != nil {
+			return err
+		}
+		audit.After = newsSnapshot(targetNews)
+		return recordAudit(tx, audit, targetNews.ID)
+	})
 	if err != nil {
-		return err
+		return models.News{}, err
 	}
-	return nil
+	return targetNews, nil
 }
 
 //GetByID ...
-func (n NewsRepository) GetByID(newsID uint) (models.News, error) {
+func (n NewsRepository) GetByID(ctx context.Context, newsID uint) (models.News, error) {
 	var targetNews models.News
-	db := infrastructures.GetDB()
+	db := n.db.WithContext(ctx)
 	queryByID := db.Where("id = ?", newsID)
 	err := queryByID.First(&targetNews).Error
 	if err != nil {
-		return models.News{}, err
+		return models.News{}, translateError(err)
+	}
+	if err := loadAssociations(db, &targetNews); err != nil {
+		return models.News{}, translateError(err)
 	}
-	db.Model(&targetNews).Association("Tags").Find(&targetNews.Tags)
 	return targetNews, nil
 }
 
 //List retrieve list of news given filters (topic, status, etc)
-func (n NewsRepository) List(queryParams map[string]string) ([]models.News, error) {
+func (n NewsRepository) List(ctx context.Context, queryParams map[string]string) ([]models.News, error) {
 	var newsList []models.News
-	db := infrastructures.GetDB()
+	db := n.db.WithContext(ctx)
 	querySearch := db.Table("news")
 	status := queryParams["status"]
 	topic := queryParams["topic"]
 	tag := queryParams["tag"]
+	author := queryParams["author"]
 	if tag != "" {
 		tagID, err := strconv.Atoi(tag)
 		if err != nil {
-			return []models.News{}, err
+			return []models.News{}, InvalidFilterError{Field: "tag", Reason: "invalid format for tag"}
 		}
-		querySearch = querySearch.Joins("JOIN news_tag ON news_tag.news_id = news.id AND news.id = ?", uint(tagID))
+		querySearch = querySearch.Joins("JOIN news_tag ON news_tag.news_id = news.id AND news_tag.tag_id = ?", uint(tagID))
+	}
+	if author != "" {
+		authorID, err := strconv.Atoi(author)
+		if err != nil {
+			return []models.News{}, InvalidFilterError{Field: "author", Reason: "invalid format for author"}
+		}
+		querySearch = querySearch.Joins("JOIN news_author ON news_author.news_id = news.id AND news_author.author_id = ?", uint(authorID))
 	}
 	if topic != "" {
 		querySearch = querySearch.Where("topic = ?",topic)
@@ -103,10 +216,38 @@ func (n NewsRepository) List(queryParams map[string]string) ([]models.News, erro
 	}
 	err := querySearch.Order("id DESC").Find(&newsList).Error
 	if err != nil {
-		return []models.News{}, err
+		return []models.News{}, translateError(err)
 	}
 	for i := range newsList {
-		db.Model(&newsList[i]).Association("Tags").Find(&newsList[i].Tags)
+		if err := loadAssociations(db, &newsList[i]); err != nil {
+			return []models.News{}, translateError(err)
+		}
 	}
 	return newsList, nil
-}
\ No newline at end of file
+}
+
+//CountByStatus counts the news that are not deleted, grouped by status
+func (n NewsRepository) CountByStatus(ctx context.Context) (map[string]int64, error) {
+	var rows []struct {
+		Status string
+		Count  int64
+	}
+	db := n.db.WithContext(ctx)
+	err := db.Model(&models.News{}).Select("status, count(*) AS count").Group("status").Scan(&rows).Error
+	if err != nil {
+		return map[string]int64{}, translateError(err)
+	}
+	counts := map[string]int64{}
+	for _, row := range rows {
+		counts[row.Status] = row.Count
+	}
+	return counts, nil
+}
+
+//loadAssociations reads the tags and authors of the news
+func loadAssociations(db *gorm.DB, news *models.News) error {
+	if err := db.Model(news).Association("Tags").Find(&news.Tags); err != nil {
+		return err
+	}
+	return db.Model(news).Association("Authors").Find(&news.Authors)
+}
@@ -1,6 +1,7 @@
 package repositories
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"news-topic-api/models"
@@ -8,7 +9,6 @@ import (
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/stretchr/testify/assert"
-	"news-topic-api/infrastructures"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
@@ -19,8 +19,12 @@ const (
 	updateQueryNews      = `^UPDATE "news".*WHERE "id" = .*$`
 	getQueryNews         = "^SELECT (.+) FROM \"news\".+$"
 	deleteQueryNews = `^UPDATE "news".*WHERE "news"."id" = .*$`
+	getDeletedQueryNews = `^SELECT (.+) FROM "news" WHERE id = .+ AND deleted_at IS NOT NULL .+$`
+	restoreQueryNews = `^UPDATE "news" SET "deleted_at"=\$1,"updated_at"=\$2 WHERE "id" = \$3$`
 	insertQueryTag = "^INSERT INTO \"tags\".+$"
 	insertQueryTagNews = "^INSERT INTO \"news_tag\".+$"
+	getQueryTagsOfNews = `^SELECT (.+) FROM "tags" JOIN "news_tag" .+$`
+	getQueryAuthorsOfNews = `^SELECT (.+) FROM "authors" JOIN "news_author" .+$`
 )
 
 func getMockNews() models.News {
@@ -53,170 +57,305 @@ func getMockListParamsNews() map[string]string {
 }
 
 func TestNewsCreateSuccess(t *testing.T) {
-	testMock, assertion := setUpNews(t)
+	t.Parallel()
+	db, testMock, assertion := setUpNews(t)
+	testMock.ExpectBegin()
 	testMock.ExpectQuery(insertQueryNews).WillReturnRows(sqlmock.NewRows([]string{"1", "1"})).WillReturnError(nil)
 	testMock.ExpectQuery(insertQueryTag).WillReturnRows(sqlmock.NewRows([]string{"1", "1"})).WillReturnError(nil)
 	testMock.ExpectExec(insertQueryTagNews).WithArgs(sqlmock.AnyArg(),sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(1, 1))
+	expectAssociations(testMock)
+	testMock.ExpectQuery(insertQueryAuditLogs).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	testMock.ExpectCommit()
 	mockNews := getMockNews()
-	newsRepo := new(NewsRepository)
-	response, err := newsRepo.Create(mockNews)
+	newsRepo := InitNewsRepository(db)
+	response, err := newsRepo.Create(context.Background(), mockNews, getMockAuditLog(models.AuditActionCreate, models.AuditEntityNews))
 	assertion.Nil(err, "Should be no error")
 	assertion.NotNil(response, "Response should be not nil")
 }
 
 func TestNewsCreateFailed(t *testing.T) {
-	testMock, assertion := setUpNews(t)
+	t.Parallel()
+	db, testMock, assertion := setUpNews(t)
+	testMock.ExpectBegin()
 	testMock.ExpectQuery(insertQueryNews).WillReturnRows(sqlmock.NewRows([]string{"0", "1"})).WillReturnError(errors.New("Insertion error"))
+	testMock.ExpectRollback()
 	mockNews := getMockNews()
-	newsRepo := new(NewsRepository)
-	_, err := newsRepo.Create(mockNews)
+	newsRepo := InitNewsRepository(db)
+	_, err := newsRepo.Create(context.Background(), mockNews, getMockAuditLog(models.AuditActionCreate, models.AuditEntityNews))
 	assertion.NotNil(err, "Should be an error")
 	testMock.ExpectationsWereMet()
 }
 
 func TestNewsUpdateSuccess(t *testing.T) {
-	testMock, assertion := setUpNews(t)
+	t.Parallel()
+	db, testMock, assertion := setUpNews(t)
+	testMock.ExpectBegin()
 	returnRow := mockRowNews()
 	mockUpdateData := getMockNews()
 	testMock.ExpectQuery(getQueryNews).WillReturnRows(returnRow)
+	expectAssociations(testMock)
 	testMock.ExpectExec(updateQueryNews).WithArgs(
 		sqlmock.AnyArg(),sqlmock.AnyArg(),sqlmock.AnyArg(),sqlmock.AnyArg(),sqlmock.AnyArg(),sqlmock.AnyArg(),sqlmock.AnyArg(),
 		sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(1, 1))
-	newsRepo := new(NewsRepository)
-	_, err := newsRepo.Update(uint(1), mockUpdateData)
+	expectReplaceAssociations(testMock)
+	expectAssociations(testMock)
+	testMock.ExpectQuery(insertQueryAuditLogs).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	testMock.ExpectCommit()
+	newsRepo := InitNewsRepository(db)
+	_, err := newsRepo.Update(context.Background(), uint(1), mockUpdateData, getMockAuditLog(models.AuditActionUpdate, models.AuditEntityNews))
 	assertion.Nil(err, "Should be no error")
-	testMock.ExpectationsWereMet()
+	assertion.Nil(testMock.ExpectationsWereMet())
+}
+
+func TestNewsUpdateReplaceTagsFailureRollsBack(t *testing.T) {
+	t.Parallel()
+	db, testMock, assertion := setUpNews(t)
+	testMock.ExpectBegin()
+	testMock.ExpectQuery(getQueryNews).WillReturnRows(mockRowNews())
+	expectAssociations(testMock)
+	testMock.ExpectExec(updateQueryNews).WillReturnResult(sqlmock.NewResult(1, 1))
+	testMock.ExpectExec(`^UPDATE "news" SET "updated_at"`).WillReturnResult(sqlmock.NewResult(0, 1))
+	testMock.ExpectQuery(insertQueryTag).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	testMock.ExpectExec(insertQueryTagNews).WillReturnError(fmt.Errorf("foreign key violation"))
+	testMock.ExpectRollback()
+	newsRepo := InitNewsRepository(db)
+	_, err := newsRepo.Update(context.Background(), uint(1), getMockNews(), getMockAuditLog(models.AuditActionUpdate, models.AuditEntityNews))
+	assertion.NotNil(err, "Should be an error")
+	assertion.Nil(testMock.ExpectationsWereMet())
+}
+
+func TestNewsGetByIDAssociationFailureReturnError(t *testing.T) {
+	t.Parallel()
+	db, testMock, assertion := setUpNews(t)
+	testMock.ExpectQuery(getQueryNews).WillReturnRows(mockRowNews())
+	testMock.ExpectQuery(getQueryTagsOfNews).WillReturnError(fmt.Errorf("connection reset"))
+	newsRepo := InitNewsRepository(db)
+	_, err := newsRepo.GetByID(context.Background(), uint(1))
+	assertion.NotNil(err, "Should be an error")
+	assertion.Nil(testMock.ExpectationsWereMet())
 }
 
 func TestNewsUpdateIDNotFoundReturnError(t *testing.T) {
-	testMock, assertion := setUpNews(t)
+	t.Parallel()
+	db, testMock, assertion := setUpNews(t)
+	testMock.ExpectBegin()
 	returnRow := mockRowNews()
 	mockUpdateData := getMockNews()
 	testMock.ExpectQuery(getQueryNews).WillReturnRows(returnRow).WillReturnError(fmt.Errorf("record not found"))
-	newsRepo := new(NewsRepository)
-	_, err := newsRepo.Update(uint(1), mockUpdateData)
+	testMock.ExpectRollback()
+	newsRepo := InitNewsRepository(db)
+	_, err := newsRepo.Update(context.Background(), uint(1), mockUpdateData, getMockAuditLog(models.AuditActionUpdate, models.AuditEntityNews))
 	assertion.NotNil(err, "Should be an error")
 	testMock.ExpectationsWereMet()
 }
 
 
 func TestNewsUpdateFailureReturnError(t *testing.T) {
-	testMock, assertion := setUpNews(t)
+	t.Parallel()
+	db, testMock, assertion := setUpNews(t)
+	testMock.ExpectBegin()
 	returnRow := mockRowNews()
 	mockUpdateData := getMockNews()
 	testMock.ExpectQuery(getQueryNews).WillReturnRows(returnRow)
 	testMock.ExpectExec(updateQueryNews).WithArgs(
 		sqlmock.AnyArg(),sqlmock.AnyArg(),sqlmock.AnyArg(),sqlmock.AnyArg(),sqlmock.AnyArg(),sqlmock.AnyArg(),sqlmock.AnyArg(),
 		sqlmock.AnyArg(),sqlmock.AnyArg()).WillReturnError(fmt.Errorf("update error"))
-	newsRepo := new(NewsRepository)
-	_, err := newsRepo.Update(uint(1), mockUpdateData)
+	testMock.ExpectRollback()
+	newsRepo := InitNewsRepository(db)
+	_, err := newsRepo.Update(context.Background(), uint(1), mockUpdateData, getMockAuditLog(models.AuditActionUpdate, models.AuditEntityNews))
 	assertion.NotNil(err, "Should be an error")
 	testMock.ExpectationsWereMet()
 }
 
 func TestNewsDeleteSuccess(t *testing.T) {
-	testMock, assertion := setUpNews(t)
+	t.Parallel()
+	db, testMock, assertion := setUpNews(t)
+	testMock.ExpectBegin()
 	returnRow := mockRowNews()
 	testMock.ExpectQuery(getQueryNews).WillReturnRows(returnRow)
+	expectAssociations(testMock)
 	testMock.ExpectExec(deleteQueryNews).WithArgs(sqlmock.AnyArg(),sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(1, 1))
-	newsRepo := new(NewsRepository)
-	err := newsRepo.Delete(uint(1))
+	testMock.ExpectQuery(insertQueryAuditLogs).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	testMock.ExpectCommit()
+	newsRepo := InitNewsRepository(db)
+	err := newsRepo.Delete(context.Background(), uint(1), getMockAuditLog(models.AuditActionDelete, models.AuditEntityNews))
 	assertion.Nil(err, "Should be no error")
 	testMock.ExpectationsWereMet()
 }
 
 func TestNewsDeleteIDNotFoundReturnError(t *testing.T) {
-	testMock, assertion := setUpNews(t)
+	t.Parallel()
+	db, testMock, assertion := setUpNews(t)
+	testMock.ExpectBegin()
 	returnRow := mockRowNews()
 	testMock.ExpectQuery(getQueryNews).WillReturnRows(returnRow).WillReturnError(fmt.Errorf("record not found"))
-	newsRepo := new(NewsRepository)
-	err := newsRepo.Delete(uint(1))
+	testMock.ExpectRollback()
+	newsRepo := InitNewsRepository(db)
+	err := newsRepo.Delete(context.Background(), uint(1), getMockAuditLog(models.AuditActionDelete, models.AuditEntityNews))
 	assertion.NotNil(err, "Should be an error")
 	testMock.ExpectationsWereMet()
 }
 
 
 func TestNewsDeleteFailureReturnError(t *testing.T) {
-	testMock, assertion := setUpNews(t)
+	t.Parallel()
+	db, testMock, assertion := setUpNews(t)
+	testMock.ExpectBegin()
 	returnRow := mockRowNews()
 	testMock.ExpectQuery(getQueryNews).WillReturnRows(returnRow)
 	testMock.ExpectExec(deleteQueryNews).WithArgs(sqlmock.AnyArg(),sqlmock.AnyArg()).WillReturnError(fmt.Errorf("delete error"))
-	newsRepo := new(NewsRepository)
-	err := newsRepo.Delete(uint(1))
+	testMock.ExpectRollback()
+	newsRepo := InitNewsRepository(db)
+	err := newsRepo.Delete(context.Background(), uint(1), getMockAuditLog(models.AuditActionDelete, models.AuditEntityNews))
 	assertion.NotNil(err, "Should be an error")
 	testMock.ExpectationsWereMet()
 }
 
 
+func TestNewsRestoreSuccess(t *testing.T) {
+	t.Parallel()
+	db, testMock, assertion := setUpNews(t)
+	testMock.ExpectBegin()
+	testMock.ExpectQuery(getDeletedQueryNews).WillReturnRows(mockRowNews())
+	testMock.ExpectExec(restoreQueryNews).WithArgs(nil, sqlmock.AnyArg(), sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(1, 1))
+	expectAssociations(testMock)
+	testMock.ExpectQuery(insertQueryAuditLogs).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	testMock.ExpectCommit()
+	newsRepo := InitNewsRepository(db)
+	news, err := newsRepo.Restore(context.Background(), uint(1), getMockAuditLog(models.AuditActionRestore, models.AuditEntityNews))
+	assertion.Nil(err, "Should be no error")
+	assertion.Len(news.Tags, 1)
+	assertion.Nil(testMock.ExpectationsWereMet())
+}
+
+func TestNewsRestoreNotDeletedReturnErrNotFound(t *testing.T) {
+	t.Parallel()
+	db, testMock, assertion := setUpNews(t)
+	testMock.ExpectBegin()
+	testMock.ExpectQuery(getDeletedQueryNews).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	testMock.ExpectRollback()
+	newsRepo := InitNewsRepository(db)
+	_, err := newsRepo.Restore(context.Background(), uint(1), getMockAuditLog(models.AuditActionRestore, models.AuditEntityNews))
+	assertion.True(errors.Is(err, ErrNotFound), "Should be ErrNotFound")
+	assertion.Nil(testMock.ExpectationsWereMet())
+}
+
 func TestNewsGetByIDSuccess(t *testing.T) {
-	testMock, assertion := setUpNews(t)
+	t.Parallel()
+	db, testMock, assertion := setUpNews(t)
 	returnRow := mockRowNews()
 	testMock.ExpectQuery(getQueryNews).WillReturnRows(returnRow)
-	newsRepo := new(NewsRepository)
-	news, err := newsRepo.GetByID(uint(1))
+	expectAssociations(testMock)
+	newsRepo := InitNewsRepository(db)
+	news, err := newsRepo.GetByID(context.Background(), uint(1))
 	assertion.NotNil(news, "Entity is returned")
 	assertion.Nil(err, "Should be no error")
+	assertion.Len(news.Tags, 1)
+	assertion.Nil(testMock.ExpectationsWereMet())
 }
 
+func TestNewsGetByIDMissingReturnErrNotFound(t *testing.T) {
+	t.Parallel()
+	db, testMock, assertion := setUpNews(t)
+	testMock.ExpectQuery(getQueryNews).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	newsRepo := InitNewsRepository(db)
+	_, err := newsRepo.GetByID(context.Background(), uint(1))
+	assertion.True(errors.Is(err, ErrNotFound), "Should be a not found error")
+	assertion.Nil(testMock.ExpectationsWereMet())
+}
 func TestNewsGetByIDFailed(t *testing.T) {
-	testMock, assertion := setUpNews(t)
+	t.Parallel()
+	db, testMock, assertion := setUpNews(t)
 	_ = mockRowNews()
 	testMock.ExpectQuery(getQueryNews).WillReturnError(fmt.Errorf("record not found"))
-	newsRepo := new(NewsRepository)
-	_, err := newsRepo.GetByID(uint(1))
+	newsRepo := InitNewsRepository(db)
+	_, err := newsRepo.GetByID(context.Background(), uint(1))
 	assertion.NotNil(err, "There should be error")
 }
 
 func TestNewsListSuccess (t *testing.T) {
-	testMock, assertion := setUpNews(t)
+	t.Parallel()
+	db, testMock, assertion := setUpNews(t)
 	returnRows := mockRowsNews()
 	testMock.ExpectQuery(getQueryNews).WillReturnRows(returnRows)
-	newsRepo := new(NewsRepository)
+	expectAssociations(testMock)
+	expectAssociations(testMock)
+	newsRepo := InitNewsRepository(db)
 	searchParams := getMockListParamsNews()
-	news, err := newsRepo.List(searchParams)
+	news, err := newsRepo.List(context.Background(), searchParams)
 	assertion.Equal(len(news), 2)
 	assertion.NotNil(news, "Entities are returned")
 	assertion.Nil(err, "Should be no error")
+	assertion.Nil(testMock.ExpectationsWereMet())
 }
 
 func TestNewsListTagNotIntReturnError (t *testing.T) {
-	testMock, assertion := setUpNews(t)
+	t.Parallel()
+	db, testMock, assertion := setUpNews(t)
 	returnRows := mockRowsNews()
 	testMock.ExpectQuery(getQueryNews).WillReturnRows(returnRows)
-	newsRepo := new(NewsRepository)
+	newsRepo := InitNewsRepository(db)
 	searchParams := getMockListParamsNews()
 	searchParams["tag"] = "asdadadasdadadasdasd"
-	_, err := newsRepo.List(searchParams)
+	_, err := newsRepo.List(context.Background(), searchParams)
 	assertion.NotNil(err, "Should be no error")
 }
 
 func TestNewsListFailureReturnError (t *testing.T) {
-	testMock, assertion := setUpNews(t)
+	t.Parallel()
+	db, testMock, assertion := setUpNews(t)
 	_ = mockRowsNews()
 	testMock.ExpectQuery(getQueryNews).WillReturnError(fmt.Errorf("rows not found"))
-	newsRepo := new(NewsRepository)
+	newsRepo := InitNewsRepository(db)
 	searchParams := getMockListParamsNews()
-	_, err := newsRepo.List(searchParams)
+	_, err := newsRepo.List(context.Background(), searchParams)
 	assertion.NotNil(err, "There should be an error")
 }
 
 
 
-func setUpNews(t *testing.T) (sqlmock.Sqlmock, *assert.Assertions) {
-	mock := setUpMockNewsDB()
+func TestNewsCreateBatchSuccess(t *testing.T) {
+	t.Parallel()
+	db, testMock, assertion := setUpNews(t)
+	testMock.ExpectBegin()
+	testMock.ExpectQuery(insertQueryNews).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1).AddRow(2))
+	testMock.ExpectQuery(insertQueryTag).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	testMock.ExpectExec(insertQueryTagNews).WillReturnResult(sqlmock.NewResult(0, 2))
+	testMock.ExpectQuery(insertQueryAuditLogs).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1).AddRow(2))
+	testMock.ExpectCommit()
+	newsRepo := InitNewsRepository(db)
+	newsList, err := newsRepo.CreateBatch(context.Background(), getMockNewsList().Data, 10, getMockAuditLog(models.AuditActionCreate, models.AuditEntityNews))
+	assertion.Nil(err, "Should be no error")
+	assertion.Len(newsList, 2)
+	assertion.Nil(testMock.ExpectationsWereMet())
+}
+
+func TestNewsCreateBatchFailureRollsBack(t *testing.T) {
+	t.Parallel()
+	db, testMock, assertion := setUpNews(t)
+	testMock.ExpectBegin()
+	testMock.ExpectQuery(insertQueryNews).WillReturnError(errors.New("Insertion error"))
+	testMock.ExpectRollback()
+	newsRepo := InitNewsRepository(db)
+	_, err := newsRepo.CreateBatch(context.Background(), getMockNewsList().Data, 10, getMockAuditLog(models.AuditActionCreate, models.AuditEntityNews))
+	assertion.NotNil(err, "Should be an error")
+	assertion.Nil(testMock.ExpectationsWereMet())
+}
+
+func setUpNews(t *testing.T) (*gorm.DB, sqlmock.Sqlmock, *assert.Assertions) {
+	db, mock := setUpMockNewsDB()
 	assertions := assert.New(t)
-	return mock, assertions
+	return db, mock, assertions
 }
 
-func setUpMockNewsDB() sqlmock.Sqlmock {
+func setUpMockNewsDB() (*gorm.DB, sqlmock.Sqlmock) {
 	mockDB, mock, _ := sqlmock.New()
 	gormMockDB, _ := gorm.Open(postgres.New(postgres.Config{
 		Conn: mockDB,
 	}), &gorm.Config{
 		Logger: logger.Default.LogMode(logger.Info),
 	})
-	infrastructures.SetDB(gormMockDB)
-	return mock
+	return gormMockDB, mock
 }
 
 func mockRowsNews() *sqlmock.Rows {
@@ -227,9 +366,66 @@ func mockRowsNews() *sqlmock.Rows {
 	return rows
 }
 
+//expectAssociations expects the tags and authors of one news to be read
+func expectAssociations(testMock sqlmock.Sqlmock) {
+	testMock.ExpectQuery(getQueryTagsOfNews).WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow("1", "bitcoin"))
+	testMock.ExpectQuery(getQueryAuthorsOfNews).WillReturnRows(sqlmock.NewRows([]string{"id", "name"}))
+}
+
+//expectReplaceAssociations expects the tags of getMockNews to replace the tags of the news and its authors to be cleared
+func expectReplaceAssociations(testMock sqlmock.Sqlmock) {
+	testMock.ExpectExec(`^UPDATE "news" SET "updated_at"`).WillReturnResult(sqlmock.NewResult(0, 1))
+	testMock.ExpectQuery(insertQueryTag).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	testMock.ExpectExec(insertQueryTagNews).WillReturnResult(sqlmock.NewResult(0, 1))
+	testMock.ExpectExec(`^DELETE FROM "news_tag" WHERE .+$`).WillReturnResult(sqlmock.NewResult(0, 0))
+	testMock.ExpectExec(`^UPDATE "news" SET "updated_at"`).WillReturnResult(sqlmock.NewResult(0, 1))
+	testMock.ExpectExec(`^DELETE FROM "news_author" WHERE .+$`).WillReturnResult(sqlmock.NewResult(0, 0))
+}
+
 func mockRowNews() *sqlmock.Rows {
 	newsFieldColumns := []string{"id","title","thumbnail","summary","content", "topic", "status"}
 	rows := sqlmock.NewRows(newsFieldColumns)
 	rows.AddRow("1", "Harga bitcoin anjlok", "google.com/thumbnail.png", "Harga bitcoin sempat menurun namun dogecoin justru naik", "Dikarenakan cuitan Elon Musk, nilai bitcoin sempat mengalami penurunan", "bitcoin", "draft")
 	return rows
 }
+
+func TestNewsListAuthorNotIntReturnError (t *testing.T) {
+	t.Parallel()
+	db, _, assertion := setUpNews(t)
+	newsRepo := InitNewsRepository(db)
+	searchParams := getMockListParamsNews()
+	searchParams["author"] = "budi"
+	_, err := newsRepo.List(context.Background(), searchParams)
+	assertion.NotNil(err, "Should be an error")
+}
+
+func TestNewsCountByStatusSuccess(t *testing.T) {
+	t.Parallel()
+	db, testMock, assertion := setUpNews(t)
+	rows := sqlmock.NewRows([]string{"status", "count"}).AddRow("draft", 3).AddRow("published", 5)
+	testMock.ExpectQuery(`^SELECT status, count\(\*\) AS count FROM "news" WHERE "news"."deleted_at" IS NULL GROUP BY "status"$`).WillReturnRows(rows)
+	newsRepo := InitNewsRepository(db)
+	counts, err := newsRepo.CountByStatus(context.Background())
+	assertion.Nil(err, "Should be no error")
+	assertion.Equal(map[string]int64{"draft": 3, "published": 5}, counts)
+	assertion.Nil(testMock.ExpectationsWereMet())
+}
+
+func TestNewsCountByStatusFailed(t *testing.T) {
+	t.Parallel()
+	db, testMock, assertion := setUpNews(t)
+	testMock.ExpectQuery(`^SELECT status, count`).WillReturnError(errors.New("connection refused"))
+	newsRepo := InitNewsRepository(db)
+	_, err := newsRepo.CountByStatus(context.Background())
+	assertion.NotNil(err, "Should be an error")
+}
+func TestNewsCountByStatusCancelledContextSkipsQuery(t *testing.T) {
+	t.Parallel()
+	db, testMock, assertion := setUpNews(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	newsRepo := InitNewsRepository(db)
+	_, err := newsRepo.CountByStatus(ctx)
+	assertion.True(errors.Is(err, context.Canceled), "Should be the context error")
+	assertion.Nil(testMock.ExpectationsWereMet())
+}
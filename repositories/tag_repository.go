@@ -1,71 +1,216 @@
 package repositories
 
 import (
-	"news-topic-api/infrastructures"
+	"context"
+	"fmt"
 	"news-topic-api/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 //ITagRepository interface for tag repository
 type ITagRepository interface {
-	Create(tag models.Tag) (models.Tag, error)
-	Update(tagID uint, tag models.Tag) (models.Tag, error)
-	Delete(tagID uint) (error)
-	List() ([]models.Tag, error)
+	Create(ctx context.Context, tag models.Tag, audit models.AuditLog) (models.Tag, error)
+	Update(ctx context.Context, tagID uint, tag models.Tag, audit models.AuditLog) (models.Tag, error)
+	Delete(ctx context.Context, tagID uint, audit models.AuditLog) (error)
+	Restore(ctx context.Context, tagID uint, audit models.AuditLog) (models.Tag, error)
+	List(ctx context.Context) ([]models.Tag, error)
+	Merge(ctx context.Context, targetID uint, sourceIDs []uint, audit models.AuditLog) (models.Tag, error)
+	CreateBatch(ctx context.Context, tags []models.Tag, batchSize int, audit models.AuditLog) ([]models.Tag, error)
+	FindByIDs(ctx context.Context, tagIDs []uint) ([]models.Tag, error)
 }
 
 //TagRepository ...
 type TagRepository struct{
+	db *gorm.DB
+}
+
+//InitTagRepository initializes a tag repository given the database handle
+func InitTagRepository(db *gorm.DB) ITagRepository {
+	repository := new(TagRepository)
+	repository.db = db
+	return *repository
 }
 
-//Create ...
-func (t TagRepository) Create(tag models.Tag) (models.Tag, error) {
-	db := infrastructures.GetDB()
-	err := db.Create(&tag).Error
+//Create inserts the tag and its audit entry in one transaction
+func (t TagRepository) Create(ctx context.Context, tag models.Tag, audit models.AuditLog) (models.Tag, error) {
+	db := t.db.WithContext(ctx)
+	err := inTransaction(db, func(tx *gorm.DB) error {
+		if err := tx.Create(&tag).Error; err != nil {
+			return err
+		}
+		audit.After = tagSnapshot(tag)
+		return recordAudit(tx, audit, tag.ID)
+	})
 	return tag, err
 }
 
-//Update ...
-func (t TagRepository) Update(tagID uint, tag models.Tag) (models.Tag, error) {
+//Update updates the tag and records its previous and new state in one transaction
+func (t TagRepository) Update(ctx context.Context, tagID uint, tag models.Tag, audit models.AuditLog) (models.Tag, error) {
 	var targetTag models.Tag
-	db := infrastructures.GetDB()
-	err := db.Where("id = ?", tagID).First(&targetTag).Error
+	db := t.db.WithContext(ctx)
+	err := inTransaction(db, func(tx *gorm.DB) error {
+		err := tx.Where("id = ?", tagID).First(&targetTag).Error
+		if err != nil {
+			return err
+		}
+		audit.Before = tagSnapshot(targetTag)
+		updateData := map[string]interface{} {
+			"name": tag.Name,
+		}
+		err = tx.Model(&targetTag).Omit("created_at").Updates(updateData).Error
+		if err != nil {
+			return err
+		}
+		audit.After = tagSnapshot(targetTag)
+		return recordAudit(tx, audit, targetTag.ID)
+	})
 	if err != nil {
 		return models.Tag{}, err
 	}
-	updateData := map[string]interface{} {
-		"name": tag.Name,
-	}
-	err = db.Model(&targetTag).Omit("created_at").Updates(updateData).Error
+	return targetTag, nil
+}
+
+//Delete soft deletes the tag and records its last state in one transaction
+func (t TagRepository) Delete(ctx context.Context, tagID uint, audit models.AuditLog) (error) {
+	var targetTag models.Tag
+	db := t.db.WithContext(ctx)
+	return inTransaction(db, func(tx *gorm.DB) error {
+		err := tx.Where("id = ?", tagID).First(&targetTag).Error
+		if err != nil {
+			return err
+		}
+		audit.Before = tagSnapshot(targetTag)
+		err = tx.Delete(&targetTag).Error
+		if err != nil {
+			return err
+		}
+		return recordAudit(tx, audit, targetTag.ID)
+	})
+}
+
+//Restore brings back a soft deleted tag and records it in one transaction, a tag that is not deleted is not found.
+//Tag names stay taken while deleted, so the restored name can not clash
+func (t TagRepository) Restore(ctx context.Context, tagID uint, audit models.AuditLog) (models.Tag, error) {
+	var targetTag models.Tag
+	db := t.db.WithContext(ctx)
+	err := inTransaction(db, func(tx *gorm.DB) error {
+		err := tx.Unscoped().Where("id = ? AND deleted_at IS NOT NULL", tagID).First(&targetTag).Error
+		if err != nil {
+			return err
+		}
+		err = tx.Unscoped().Model(&targetTag).Update("deleted_at", nil).Error
+		if err != nil {
+			return err
+		}
+		targetTag.DeletedAt = gorm.DeletedAt{}
+		audit.After = tagSnapshot(targetTag)
+		return recordAudit(tx, audit, targetTag.ID)
+	})
 	if err != nil {
 		return models.Tag{}, err
 	}
 	return targetTag, nil
 }
 
-//Delete ...
-func (t TagRepository) Delete(tagID uint) (error) {
-	var targetTag models.Tag
-	db := infrastructures.GetDB()
-	err := db.Where("id = ?", tagID).First(&targetTag).Error
+//CreateBatch inserts the tags batchSize rows at a time and audits each of them, all in one transaction
+func (t TagRepository) CreateBatch(ctx context.Context, tags []models.Tag, batchSize int, audit models.AuditLog) ([]models.Tag, error) {
+	if len(tags) == 0 {
+		return tags, nil
+	}
+	db := t.db.WithContext(ctx)
+	err := inTransaction(db, func(tx *gorm.DB) error {
+		err := inBatches(len(tags), batchSize, func(start int, end int) error {
+			batch := tags[start:end]
+			return tx.Create(&batch).Error
+		})
+		if err != nil {
+			return err
+		}
+		entries := make([]models.AuditLog, len(tags))
+		for i, tag := range tags {
+			entries[i] = audit
+			entries[i].EntityID = tag.ID
+			entries[i].After = tagSnapshot(tag)
+		}
+		return recordAuditBatch(tx, entries, batchSize)
+	})
 	if err != nil {
-		return err
+		return []models.Tag{}, err
 	}
-	err = db.Delete(&targetTag).Error
+	return tags, nil
+}
+
+//Merge moves the news of the source tags to the target tag and deletes the sources in one transaction,
+//each merged tag is audited with its last state before and the target after
+func (t TagRepository) Merge(ctx context.Context, targetID uint, sourceIDs []uint, audit models.AuditLog) (models.Tag, error) {
+	var targetTag models.Tag
+	db := t.db.WithContext(ctx)
+	err := inTransaction(db, func(tx *gorm.DB) error {
+		err := tx.Where("id = ?", targetID).First(&targetTag).Error
+		if err != nil {
+			return err
+		}
+		var sourceTags []models.Tag
+		err = tx.Where("id IN ?", sourceIDs).Find(&sourceTags).Error
+		if err != nil {
+			return err
+		}
+		if len(sourceTags) != len(sourceIDs) {
+			return fmt.Errorf("%w: only %d of the %d tags to merge exist", ErrNotFound, len(sourceTags), len(sourceIDs))
+		}
+		err = tx.Exec("INSERT INTO news_tag (news_id, tag_id) SELECT news_id, ? FROM news_tag WHERE tag_id IN ? ON CONFLICT DO NOTHING", targetID, sourceIDs).Error
+		if err != nil {
+			return err
+		}
+		err = tx.Exec("DELETE FROM news_tag WHERE tag_id IN ?", sourceIDs).Error
+		if err != nil {
+			return err
+		}
+		err = tx.Delete(&sourceTags).Error
+		if err != nil {
+			return err
+		}
+		for _, sourceTag := range sourceTags {
+			entry := audit
+			entry.Before = tagSnapshot(sourceTag)
+			entry.After = tagSnapshot(targetTag)
+			if err := recordAudit(tx, entry, sourceTag.ID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
 	if err != nil {
-		return err
+		return models.Tag{}, err
 	}
-	return nil
+	return targetTag, nil
 }
 
+//FindByIDs returns the tags with the given ids, ids without a tag are left out. Within a transaction the tags
+//stay locked against deletion until it ends
+func (t TagRepository) FindByIDs(ctx context.Context, tagIDs []uint) ([]models.Tag, error) {
+	var tags []models.Tag
+	if len(tagIDs) == 0 {
+		return tags, nil
+	}
+	db := t.db.WithContext(ctx)
+	err := db.Clauses(clause.Locking{Strength: "SHARE"}).Where("id IN ?", tagIDs).Find(&tags).Error
+	if err != nil {
+		return []models.Tag{}, translateError(err)
+	}
+	return tags, nil
+}
 
 //List ...
-func (t TagRepository) List() ([]models.Tag, error) {
+func (t TagRepository) List(ctx context.Context) ([]models.Tag, error) {
 	var tagsList []models.Tag
-	db := infrastructures.GetDB()
+	db := t.db.WithContext(ctx)
 	querySearch := db.Table("tags")
 	err := querySearch.Order("id DESC").Find(&tagsList).Error
 	if err != nil {
-		return []models.Tag{}, err
+		return []models.Tag{}, translateError(err)
 	}
 	return tagsList, nil
 }
@@ -1,14 +1,15 @@
 package repositories
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"news-topic-api/models"
 	"testing"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
 	"github.com/stretchr/testify/assert"
-	"news-topic-api/infrastructures"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
@@ -19,6 +20,10 @@ const (
 	updateQueryTags      = `^UPDATE "tags".*WHERE "id" = .*$`
 	getQueryTags         = "^SELECT (.+) FROM \"tags\".+$"
 	deleteQueryTags = `^UPDATE "tags".*WHERE "tags"."id" = .*$`
+	getDeletedQueryTags = `^SELECT (.+) FROM "tags" WHERE id = .+ AND deleted_at IS NOT NULL .+$`
+	restoreQueryTags = `^UPDATE "tags" SET "deleted_at"=\$1,"updated_at"=\$2 WHERE "id" = \$3$`
+	copyQueryNewsTag    = `^INSERT INTO news_tag \(news_id, tag_id\) SELECT news_id, .* ON CONFLICT DO NOTHING$`
+	deleteQueryNewsTag  = `^DELETE FROM news_tag WHERE tag_id IN .*$`
 
 )
 func getMockTag() models.Tag {
@@ -29,131 +34,293 @@ func getMockTag() models.Tag {
 }
 
 func TestTagCreateSuccess(t *testing.T) {
-	testMock, assertion := setUpTag(t)
+	t.Parallel()
+	db, testMock, assertion := setUpTag(t)
+	testMock.ExpectBegin()
 	testMock.ExpectQuery(insertQueryTags).WillReturnRows(sqlmock.NewRows([]string{"1", "1"})).WillReturnError(nil)
+	testMock.ExpectQuery(insertQueryAuditLogs).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	testMock.ExpectCommit()
 	mockTag := getMockTag()
-	tagRepo := new(TagRepository)
-	response, err := tagRepo.Create(mockTag)
+	tagRepo := InitTagRepository(db)
+	response, err := tagRepo.Create(context.Background(), mockTag, getMockAuditLog(models.AuditActionCreate, models.AuditEntityTag))
 	assertion.Nil(err, "Should be no error")
 	assertion.NotNil(response, "Response should be not nil")
 }
 
+func TestTagCreateDuplicateNameReturnErrDuplicate(t *testing.T) {
+	t.Parallel()
+	db, testMock, assertion := setUpTag(t)
+	testMock.ExpectBegin()
+	testMock.ExpectQuery(insertQueryTags).WillReturnError(&pq.Error{Code: "23505", Constraint: "tags_name_key"})
+	testMock.ExpectRollback()
+	tagRepo := InitTagRepository(db)
+	_, err := tagRepo.Create(context.Background(), getMockTag(), getMockAuditLog(models.AuditActionCreate, models.AuditEntityTag))
+	assertion.True(errors.Is(err, ErrDuplicate), "Should be a duplicate error")
+	assertion.Nil(testMock.ExpectationsWereMet())
+}
 func TestTagCreateFailed(t *testing.T) {
-	testMock, assertion := setUpTag(t)
+	t.Parallel()
+	db, testMock, assertion := setUpTag(t)
+	testMock.ExpectBegin()
 	testMock.ExpectQuery(insertQueryTags).WillReturnRows(sqlmock.NewRows([]string{"0", "1"})).WillReturnError(errors.New("Insertion error"))
+	testMock.ExpectRollback()
 	mockTag := getMockTag()
-	tagRepo := new(TagRepository)
-	_, err := tagRepo.Create(mockTag)
+	tagRepo := InitTagRepository(db)
+	_, err := tagRepo.Create(context.Background(), mockTag, getMockAuditLog(models.AuditActionCreate, models.AuditEntityTag))
 	assertion.NotNil(err, "Should be an error")
 	testMock.ExpectationsWereMet()
 }
 
 func TestTagUpdateSuccess(t *testing.T) {
-	testMock, assertion := setUpTag(t)
+	t.Parallel()
+	db, testMock, assertion := setUpTag(t)
+	testMock.ExpectBegin()
 	returnRow := mockRowTag()
 	mockUpdateData := getMockTag()
 	testMock.ExpectQuery(getQueryTags).WillReturnRows(returnRow)
 	testMock.ExpectExec(updateQueryTags).WithArgs(sqlmock.AnyArg(),sqlmock.AnyArg(),sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(1, 1))
-	tagRepo := new(TagRepository)
-	_, err := tagRepo.Update(uint(1), mockUpdateData)
+	testMock.ExpectQuery(insertQueryAuditLogs).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	testMock.ExpectCommit()
+	tagRepo := InitTagRepository(db)
+	_, err := tagRepo.Update(context.Background(), uint(1), mockUpdateData, getMockAuditLog(models.AuditActionUpdate, models.AuditEntityTag))
 	assertion.Nil(err, "Should be no error")
 	testMock.ExpectationsWereMet()
 }
 
 func TestTagUpdateIDNotFoundReturnError(t *testing.T) {
-	testMock, assertion := setUpTag(t)
+	t.Parallel()
+	db, testMock, assertion := setUpTag(t)
+	testMock.ExpectBegin()
 	returnRow := mockRowTag()
 	mockUpdateData := getMockTag()
 	testMock.ExpectQuery(getQueryTags).WillReturnRows(returnRow).WillReturnError(fmt.Errorf("record not found"))
-	tagRepo := new(TagRepository)
-	_, err := tagRepo.Update(uint(1), mockUpdateData)
+	testMock.ExpectRollback()
+	tagRepo := InitTagRepository(db)
+	_, err := tagRepo.Update(context.Background(), uint(1), mockUpdateData, getMockAuditLog(models.AuditActionUpdate, models.AuditEntityTag))
 	assertion.NotNil(err, "Should be an error")
 	testMock.ExpectationsWereMet()
 }
 
 
 func TestTagUpdateFailureReturnError(t *testing.T) {
-	testMock, assertion := setUpTag(t)
+	t.Parallel()
+	db, testMock, assertion := setUpTag(t)
+	testMock.ExpectBegin()
 	returnRow := mockRowTag()
 	mockUpdateData := getMockTag()
 	testMock.ExpectQuery(getQueryTags).WillReturnRows(returnRow)
 	testMock.ExpectExec(updateQueryTags).WithArgs(sqlmock.AnyArg(),sqlmock.AnyArg(),sqlmock.AnyArg()).WillReturnError(fmt.Errorf("update error"))
-	tagRepo := new(TagRepository)
-	_, err := tagRepo.Update(uint(1), mockUpdateData)
+	testMock.ExpectRollback()
+	tagRepo := InitTagRepository(db)
+	_, err := tagRepo.Update(context.Background(), uint(1), mockUpdateData, getMockAuditLog(models.AuditActionUpdate, models.AuditEntityTag))
 	assertion.NotNil(err, "Should be an error")
 	testMock.ExpectationsWereMet()
 }
 
 func TestTagDeleteSuccess(t *testing.T) {
-	testMock, assertion := setUpTag(t)
+	t.Parallel()
+	db, testMock, assertion := setUpTag(t)
+	testMock.ExpectBegin()
 	returnRow := mockRowTag()
 	testMock.ExpectQuery(getQueryTags).WillReturnRows(returnRow)
 	testMock.ExpectExec(deleteQueryTags).WithArgs(sqlmock.AnyArg(),sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(1, 1))
-	tagRepo := new(TagRepository)
-	err := tagRepo.Delete(uint(1))
+	testMock.ExpectQuery(insertQueryAuditLogs).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	testMock.ExpectCommit()
+	tagRepo := InitTagRepository(db)
+	err := tagRepo.Delete(context.Background(), uint(1), getMockAuditLog(models.AuditActionDelete, models.AuditEntityTag))
 	assertion.Nil(err, "Should be no error")
 	testMock.ExpectationsWereMet()
 }
 
 func TestTagDeleteIDNotFoundReturnError(t *testing.T) {
-	testMock, assertion := setUpTag(t)
+	t.Parallel()
+	db, testMock, assertion := setUpTag(t)
+	testMock.ExpectBegin()
 	returnRow := mockRowTag()
 	testMock.ExpectQuery(getQueryTags).WillReturnRows(returnRow).WillReturnError(fmt.Errorf("record not found"))
-	tagRepo := new(TagRepository)
-	err := tagRepo.Delete(uint(1))
+	testMock.ExpectRollback()
+	tagRepo := InitTagRepository(db)
+	err := tagRepo.Delete(context.Background(), uint(1), getMockAuditLog(models.AuditActionDelete, models.AuditEntityTag))
 	assertion.NotNil(err, "Should be an error")
 	testMock.ExpectationsWereMet()
 }
 
 
 func TestTagDeleteFailureReturnError(t *testing.T) {
-	testMock, assertion := setUpTag(t)
+	t.Parallel()
+	db, testMock, assertion := setUpTag(t)
+	testMock.ExpectBegin()
 	returnRow := mockRowTag()
 	testMock.ExpectQuery(getQueryTags).WillReturnRows(returnRow)
 	testMock.ExpectExec(deleteQueryTags).WithArgs(sqlmock.AnyArg(),sqlmock.AnyArg()).WillReturnError(fmt.Errorf("delete error"))
-	tagRepo := new(TagRepository)
-	err := tagRepo.Delete(uint(1))
+	testMock.ExpectRollback()
+	tagRepo := InitTagRepository(db)
+	err := tagRepo.Delete(context.Background(), uint(1), getMockAuditLog(models.AuditActionDelete, models.AuditEntityTag))
 	assertion.NotNil(err, "Should be an error")
 	testMock.ExpectationsWereMet()
 }
 
+func TestTagRestoreSuccess(t *testing.T) {
+	t.Parallel()
+	db, testMock, assertion := setUpTag(t)
+	testMock.ExpectBegin()
+	testMock.ExpectQuery(getDeletedQueryTags).WillReturnRows(mockRowTag())
+	testMock.ExpectExec(restoreQueryTags).WithArgs(nil, sqlmock.AnyArg(), sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(1, 1))
+	testMock.ExpectQuery(insertQueryAuditLogs).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	testMock.ExpectCommit()
+	tagRepo := InitTagRepository(db)
+	tag, err := tagRepo.Restore(context.Background(), uint(1), getMockAuditLog(models.AuditActionRestore, models.AuditEntityTag))
+	assertion.Nil(err, "Should be no error")
+	assertion.Equal("cryptocurrency", tag.Name)
+	assertion.Nil(testMock.ExpectationsWereMet())
+}
+
+func TestTagRestoreNotDeletedReturnErrNotFound(t *testing.T) {
+	t.Parallel()
+	db, testMock, assertion := setUpTag(t)
+	testMock.ExpectBegin()
+	testMock.ExpectQuery(getDeletedQueryTags).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	testMock.ExpectRollback()
+	tagRepo := InitTagRepository(db)
+	_, err := tagRepo.Restore(context.Background(), uint(1), getMockAuditLog(models.AuditActionRestore, models.AuditEntityTag))
+	assertion.True(errors.Is(err, ErrNotFound), "Should be ErrNotFound")
+	assertion.Nil(testMock.ExpectationsWereMet())
+}
+
 func TestTagListSuccess (t *testing.T) {
-	testMock, assertion := setUpTag(t)
+	t.Parallel()
+	db, testMock, assertion := setUpTag(t)
 	returnRow := mockRowTag()
 	testMock.ExpectQuery(getQueryTags).WillReturnRows(returnRow)
-	tagRepo := new(TagRepository)
-	tags, err := tagRepo.List()
+	tagRepo := InitTagRepository(db)
+	tags, err := tagRepo.List(context.Background())
 	assertion.Equal(len(tags), 1)
 	assertion.NotNil(tags, "Entities are returned")
 	assertion.Nil(err, "Should be no error")
 }
 
 func TestTagListFailureReturnError (t *testing.T) {
-	testMock, assertion := setUpTag(t)
+	t.Parallel()
+	db, testMock, assertion := setUpTag(t)
 	_ = mockRowTag()
 	testMock.ExpectQuery(getQueryTags).WillReturnError(fmt.Errorf("rows not found"))
-	tagRepo := new(TagRepository)
-	_, err := tagRepo.List()
+	tagRepo := InitTagRepository(db)
+	_, err := tagRepo.List(context.Background())
 	assertion.NotNil(err, "There should be an error")
 }
 
 
+func TestTagCreateBatchSuccess(t *testing.T) {
+	t.Parallel()
+	db, testMock, assertion := setUpTag(t)
+	testMock.ExpectBegin()
+	testMock.ExpectQuery(insertQueryTags).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1).AddRow(2))
+	testMock.ExpectQuery(insertQueryTags).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+	testMock.ExpectQuery(insertQueryAuditLogs).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1).AddRow(2))
+	testMock.ExpectQuery(insertQueryAuditLogs).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+	testMock.ExpectCommit()
+	tagRepo := InitTagRepository(db)
+	tags, err := tagRepo.CreateBatch(context.Background(), []models.Tag{{Name: "a"}, {Name: "b"}, {Name: "c"}}, 2, getMockAuditLog(models.AuditActionCreate, models.AuditEntityTag))
+	assertion.Nil(err, "Should be no error")
+	assertion.Equal(uint(3), tags[2].ID)
+	assertion.Nil(testMock.ExpectationsWereMet())
+}
+
+func TestTagCreateBatchFailureRollsBack(t *testing.T) {
+	t.Parallel()
+	db, testMock, assertion := setUpTag(t)
+	testMock.ExpectBegin()
+	testMock.ExpectQuery(insertQueryTags).WillReturnError(errors.New("Insertion error"))
+	testMock.ExpectRollback()
+	tagRepo := InitTagRepository(db)
+	_, err := tagRepo.CreateBatch(context.Background(), []models.Tag{{Name: "a"}}, 2, getMockAuditLog(models.AuditActionCreate, models.AuditEntityTag))
+	assertion.NotNil(err, "Should be an error")
+	assertion.Nil(testMock.ExpectationsWereMet())
+}
+
+func TestTagFindByIDsSuccess(t *testing.T) {
+	t.Parallel()
+	db, testMock, assertion := setUpTag(t)
+	testMock.ExpectQuery(`^SELECT \* FROM "tags" WHERE id IN \(\$1,\$2\) AND "tags"."deleted_at" IS NULL FOR SHARE$`).
+		WithArgs(1, 2).WillReturnRows(mockRowTag())
+	tagRepo := InitTagRepository(db)
+	tags, err := tagRepo.FindByIDs(context.Background(), []uint{1, 2})
+	assertion.Nil(err, "Should be no error")
+	assertion.Len(tags, 1)
+	assertion.Nil(testMock.ExpectationsWereMet())
+}
+
+func TestTagFindByIDsWithoutIDsSkipsQuery(t *testing.T) {
+	t.Parallel()
+	db, testMock, assertion := setUpTag(t)
+	tagRepo := InitTagRepository(db)
+	tags, err := tagRepo.FindByIDs(context.Background(), []uint{})
+	assertion.Nil(err, "Should be no error")
+	assertion.Empty(tags)
+	assertion.Nil(testMock.ExpectationsWereMet())
+}
+
+func TestTagMergeSuccess(t *testing.T) {
+	t.Parallel()
+	db, testMock, assertion := setUpTag(t)
+	testMock.ExpectBegin()
+	testMock.ExpectQuery(getQueryTags).WillReturnRows(mockRowTag())
+	testMock.ExpectQuery(getQueryTags).WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow("2", "crypto").AddRow("3", "bitcoin"))
+	testMock.ExpectExec(copyQueryNewsTag).WillReturnResult(sqlmock.NewResult(0, 4))
+	testMock.ExpectExec(deleteQueryNewsTag).WillReturnResult(sqlmock.NewResult(0, 5))
+	testMock.ExpectExec(`^UPDATE "tags" SET "deleted_at"`).WillReturnResult(sqlmock.NewResult(0, 2))
+	testMock.ExpectQuery(insertQueryAuditLogs).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	testMock.ExpectQuery(insertQueryAuditLogs).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+	testMock.ExpectCommit()
+	tagRepo := InitTagRepository(db)
+	tag, err := tagRepo.Merge(context.Background(), uint(1), []uint{2, 3}, getMockAuditLog(models.AuditActionMerge, models.AuditEntityTag))
+	assertion.Nil(err, "Should be no error")
+	assertion.Equal(uint(1), tag.ID)
+	assertion.Nil(testMock.ExpectationsWereMet())
+}
+
+func TestTagMergeMissingSourceReturnError(t *testing.T) {
+	t.Parallel()
+	db, testMock, assertion := setUpTag(t)
+	testMock.ExpectBegin()
+	testMock.ExpectQuery(getQueryTags).WillReturnRows(mockRowTag())
+	testMock.ExpectQuery(getQueryTags).WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow("2", "crypto"))
+	testMock.ExpectRollback()
+	tagRepo := InitTagRepository(db)
+	_, err := tagRepo.Merge(context.Background(), uint(1), []uint{2, 3}, getMockAuditLog(models.AuditActionMerge, models.AuditEntityTag))
+	assertion.NotNil(err, "Should be an error")
+	assertion.Nil(testMock.ExpectationsWereMet())
+}
+
+func TestTagMergeFailureRollsBack(t *testing.T) {
+	t.Parallel()
+	db, testMock, assertion := setUpTag(t)
+	testMock.ExpectBegin()
+	testMock.ExpectQuery(getQueryTags).WillReturnRows(mockRowTag())
+	testMock.ExpectQuery(getQueryTags).WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow("2", "crypto"))
+	testMock.ExpectExec(copyQueryNewsTag).WillReturnError(fmt.Errorf("insert error"))
+	testMock.ExpectRollback()
+	tagRepo := InitTagRepository(db)
+	_, err := tagRepo.Merge(context.Background(), uint(1), []uint{2}, getMockAuditLog(models.AuditActionMerge, models.AuditEntityTag))
+	assertion.NotNil(err, "Should be an error")
+	assertion.Nil(testMock.ExpectationsWereMet())
+}
+
 
-func setUpTag(t *testing.T) (sqlmock.Sqlmock, *assert.Assertions) {
-	mock := setUpMockTagDB()
+func setUpTag(t *testing.T) (*gorm.DB, sqlmock.Sqlmock, *assert.Assertions) {
+	db, mock := setUpMockTagDB()
 	assertions := assert.New(t)
-	return mock, assertions
+	return db, mock, assertions
 }
 
-func setUpMockTagDB() sqlmock.Sqlmock {
+func setUpMockTagDB() (*gorm.DB, sqlmock.Sqlmock) {
 	mockDB, mock, _ := sqlmock.New()
 	gormMockDB, _ := gorm.Open(postgres.New(postgres.Config{
 		Conn: mockDB,
 	}), &gorm.Config{
 		Logger: logger.Default.LogMode(logger.Info),
 	})
-	infrastructures.SetDB(gormMockDB)
-	return mock
+	return gormMockDB, mock
 }
 
 
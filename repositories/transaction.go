@@ -0,0 +1,71 @@
+package repositories
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+//IUnitOfWork gives repositories sharing one transaction
+type IUnitOfWork interface {
+	News() INewsRepository
+	Tags() ITagRepository
+	Authors() IAuthorRepository
+	Audit() IAuditRepository
+}
+
+//ITransactionManager runs several repository calls atomically
+type ITransactionManager interface {
+	WithinTransaction(ctx context.Context, fn func(uow IUnitOfWork) error) error
+}
+
+//TransactionManager ...
+type TransactionManager struct {
+	db *gorm.DB
+}
+
+//InitTransactionManager initializes a transaction manager given the database handle
+func InitTransactionManager(db *gorm.DB) ITransactionManager {
+	transactionManager := new(TransactionManager)
+	transactionManager.db = db
+	return *transactionManager
+}
+
+//WithinTransaction runs fn in a transaction committed when fn returns nil and rolled back otherwise,
+//the repositories of the unit of work join the transaction instead of opening their own.
+//The transaction is rolled back when ctx is done before it commits
+func (m TransactionManager) WithinTransaction(ctx context.Context, fn func(uow IUnitOfWork) error) error {
+	err := m.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(unitOfWork{tx: tx})
+	})
+	return translateError(err)
+}
+
+type unitOfWork struct {
+	tx *gorm.DB
+}
+
+func (u unitOfWork) News() INewsRepository {
+	return InitNewsRepository(u.tx)
+}
+
+func (u unitOfWork) Tags() ITagRepository {
+	return InitTagRepository(u.tx)
+}
+
+func (u unitOfWork) Authors() IAuthorRepository {
+	return InitAuthorRepository(u.tx)
+}
+
+func (u unitOfWork) Audit() IAuditRepository {
+	return InitAuditRepository(u.tx)
+}
+
+//inTransaction runs fn in a transaction of db, joining the transaction db is already bound to
+//when called from a unit of work, its error is translated for the callers
+func inTransaction(db *gorm.DB, fn func(tx *gorm.DB) error) error {
+	if committer, ok := db.Statement.ConnPool.(gorm.TxCommitter); ok && committer != nil {
+		return translateError(fn(db))
+	}
+	return translateError(db.Transaction(fn))
+}
@@ -0,0 +1,49 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"news-topic-api/models"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestWithinTransactionCommitsRepositoryCalls(t *testing.T) {
+	t.Parallel()
+	db, testMock, assertion := setUpTag(t)
+	testMock.ExpectBegin()
+	testMock.ExpectQuery(getQueryTags).WillReturnRows(mockRowTag())
+	testMock.ExpectQuery(insertQueryTags).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+	testMock.ExpectQuery(insertQueryAuditLogs).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	testMock.ExpectCommit()
+	transactionManager := InitTransactionManager(db)
+	err := transactionManager.WithinTransaction(context.Background(), func(uow IUnitOfWork) error {
+		if _, err := uow.Tags().FindByIDs(context.Background(), []uint{1}); err != nil {
+			return err
+		}
+		// the repository joins the transaction instead of opening a savepoint
+		_, err := uow.Tags().Create(context.Background(), getMockTag(), getMockAuditLog(models.AuditActionCreate, models.AuditEntityTag))
+		return err
+	})
+	assertion.Nil(err, "Should be no error")
+	assertion.Nil(testMock.ExpectationsWereMet())
+}
+
+func TestWithinTransactionRollsBackOnError(t *testing.T) {
+	t.Parallel()
+	db, testMock, assertion := setUpTag(t)
+	testMock.ExpectBegin()
+	testMock.ExpectQuery(insertQueryTags).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+	testMock.ExpectQuery(insertQueryAuditLogs).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	testMock.ExpectRollback()
+	transactionManager := InitTransactionManager(db)
+	err := transactionManager.WithinTransaction(context.Background(), func(uow IUnitOfWork) error {
+		if _, err := uow.Tags().Create(context.Background(), getMockTag(), getMockAuditLog(models.AuditActionCreate, models.AuditEntityTag)); err != nil {
+			return err
+		}
+		return fmt.Errorf("author 3 does not exist")
+	})
+	assertion.EqualError(err, "author 3 does not exist")
+	assertion.Nil(testMock.ExpectationsWereMet())
+}
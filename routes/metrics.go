@@ -0,0 +1,45 @@
+package routes
+
+import (
+	"context"
+	"news-topic-api/cache"
+	"news-topic-api/metrics"
+	"news-topic-api/services"
+)
+
+//registerDomainMetrics exposes gauges computed from the stored content at scrape time
+func registerDomainMetrics(registry *metrics.Registry, newsService services.INewsService) {
+	registry.RegisterGaugeFunc("news_count", "Number of news that are not deleted by status.", []string{"status"}, func(ctx context.Context) ([]metrics.Sample, error) {
+		counts, err := newsService.CountByStatus(ctx)
+		if err != nil {
+			return nil, err
+		}
+		samples := make([]metrics.Sample, 0, len(counts))
+		for status, count := range counts {
+			samples = append(samples, metrics.Sample{LabelValues: []string{status}, Value: float64(count)})
+		}
+		return samples, nil
+	})
+}
+
+//registerNewsCacheMetrics exposes the hits and misses of the news cache and the entries its backend holds
+func registerNewsCacheMetrics(registry *metrics.Registry, newsService *services.CachedNewsService, backend *cache.LRU) {
+	registry.RegisterCounterFunc("news_cache_requests_total", "Reads of the news cache by operation and result.", []string{"operation", "result"}, func(ctx context.Context) ([]metrics.Sample, error) {
+		stats := newsService.Stats()
+		return []metrics.Sample{
+			{LabelValues: []string{"detail", "hit"}, Value: float64(stats.DetailHits)},
+			{LabelValues: []string{"detail", "miss"}, Value: float64(stats.DetailMisses)},
+			{LabelValues: []string{"list", "hit"}, Value: float64(stats.ListHits)},
+			{LabelValues: []string{"list", "miss"}, Value: float64(stats.ListMisses)},
+		}, nil
+	})
+	registry.RegisterCounterFunc("news_cache_errors_total", "Failed reads and writes of the news cache backend.", nil, func(ctx context.Context) ([]metrics.Sample, error) {
+		return []metrics.Sample{{Value: float64(newsService.Stats().Errors)}}, nil
+	})
+	registry.RegisterGaugeFunc("news_cache_entries", "Entries held by the news cache, expired ones included until evicted.", nil, func(ctx context.Context) ([]metrics.Sample, error) {
+		return []metrics.Sample{{Value: float64(backend.Len())}}, nil
+	})
+	registry.RegisterCounterFunc("news_cache_evictions_total", "Entries evicted from the news cache to make room.", nil, func(ctx context.Context) ([]metrics.Sample, error) {
+		return []metrics.Sample{{Value: float64(backend.Evictions())}}, nil
+	})
+}
@@ -2,45 +2,193 @@ package routes
 
 import (
 	"github.com/gorilla/mux"
+	"news-topic-api/cache"
+	"news-topic-api/config"
 	"news-topic-api/controllers"
+	"news-topic-api/docs"
+	"news-topic-api/health"
+	"news-topic-api/helpers"
+	"news-topic-api/infrastructures"
+	"news-topic-api/metrics"
+	"news-topic-api/middlewares"
+	"news-topic-api/policies"
 	"news-topic-api/repositories"
 	"news-topic-api/services"
+
+	"gorm.io/gorm"
 )
 
 // Route ...
-type Route struct{}
+type Route struct {
+	// Probe decides readiness, main flips it once started and before shutting down
+	Probe *health.Probe
+}
 
 // Init is the initiator for the route location
-func (r *Route) Init() *mux.Router {
+func (r *Route) Init(config config.Config, db *gorm.DB) *mux.Router {
 	// init repositories
-	newsRepository := new(repositories.NewsRepository)
-	tagRepository := new(repositories.TagRepository)
+	storage := initStorage(config.Storage, db)
 
 	// init services
-	newsService := services.InitNewsService(newsRepository)
-	tagService := services.InitTagService(tagRepository)
+	var newsService services.INewsService = services.InitNewsService(storage.news, storage.transactionManager)
+	var cachedNewsService *services.CachedNewsService
+	var newsCache *cache.LRU
+	var newsCacheInvalidator services.INewsCacheInvalidator = services.NoNewsCache{}
+	if config.NewsCache.Capacity > 0 {
+		newsCache = cache.InitLRU(config.NewsCache.Capacity)
+		cachedNewsService = services.InitCachedNewsService(newsService, newsCache, config.NewsCache.TTL)
+		newsService = cachedNewsService
+		newsCacheInvalidator = cachedNewsService
+	}
+	tagService := services.InitTagService(storage.tags, newsCacheInvalidator)
+	authorService := services.InitAuthorService(storage.authors, newsCacheInvalidator)
+	apiKeyService := services.InitAPIKeyService(storage.apiKeys)
+	auditService := services.InitAuditService(storage.audit)
+
+	// init policies
+	newsPolicy := policies.InitNewsPolicy(newsService)
+	tagPolicy := policies.InitTagPolicy()
+	authorPolicy := policies.InitAuthorPolicy()
+	apiKeyPolicy := policies.InitAPIKeyPolicy()
+	auditPolicy := policies.InitAuditPolicy()
+
+	// init health checks
+	r.Probe = health.InitProbe(config.Server.ReadinessTimeout)
+	if db != nil {
+		r.Probe.AddCheck("database", infrastructures.PingDB(db))
+		r.Probe.AddCheck("migrations", infrastructures.CheckMigrations(db))
+	}
 
 	// init Controllers
-	newsController := controllers.InitNewsController(newsService)
-	tagController := controllers.InitTagController(tagService)
+	newsController := controllers.InitNewsController(newsService, newsPolicy)
+	tagController := controllers.InitTagController(tagService, tagPolicy)
+	authorController := controllers.InitAuthorController(authorService, authorPolicy)
+	apiKeyController := controllers.InitAPIKeyController(apiKeyService, apiKeyPolicy)
+	auditController := controllers.InitAuditController(auditService, auditPolicy)
+	healthController := controllers.InitHealthController(r.Probe)
+	docsController := controllers.InitDocsController(docs.OpenAPI, docs.Page)
+
+	// init metrics
+	registry := metrics.InitRegistry()
+	if db != nil {
+		infrastructures.RegisterDBMetrics(registry, db)
+	}
+	registerDomainMetrics(registry, newsService)
+	if cachedNewsService != nil {
+		registerNewsCacheMetrics(registry, cachedNewsService, newsCache)
+	}
+
+	// init middlewares
+	requestMetrics := middlewares.InitRequestMetrics(registry)
+	requestLogger := middlewares.InitRequestLogger(helpers.DefaultLogger())
+	clientIPResolver := middlewares.InitClientIPResolver(config.Server.TrustProxy)
+	jwtAuthenticator := middlewares.InitJWTAuthenticator(middlewares.JWTConfig{
+		Secret:    []byte(config.JWT.Secret),
+		Issuer:    config.JWT.Issuer,
+		Audience:  config.JWT.Audience,
+		ClockSkew: config.JWT.ClockSkew,
+		MaxTTL:    config.JWT.MaxTTL,
+	})
+	apiKeyAuthenticator := middlewares.InitAPIKeyAuthenticator(apiKeyService)
+	ipRateLimiter := middlewares.InitIPRateLimiter(middlewares.RateLimitConfig{
+		Rate:    config.RateLimit.IP.Rate,
+		Burst:   config.RateLimit.IP.Burst,
+		IdleTTL: config.RateLimit.IdleTTL,
+	})
+	groupMiddlewares := map[string][]mux.MiddlewareFunc{
+		"news":   routeGroupMiddlewares(config, "news"),
+		"tag":    routeGroupMiddlewares(config, "tag"),
+		"author": routeGroupMiddlewares(config, "author"),
+		"apikey": routeGroupMiddlewares(config, "apikey"),
+		"audit":  routeGroupMiddlewares(config, "audit"),
+	}
+	unversionedDeprecation := middlewares.InitDeprecation(config.Unversioned.DeprecatedAt, config.Unversioned.Sunset, "/v1")
 
 	// init routes
 	router := mux.NewRouter().StrictSlash(false)
-	news := router.PathPrefix("/news").Subrouter()
-	tag := router.PathPrefix("/tag").Subrouter()
-
-	//news endpoint
-	news.HandleFunc("/", newsController.Create).Methods("POST")
-	news.HandleFunc("/{id}", newsController.Update).Methods("PUT")
-	news.HandleFunc("/{id}", newsController.Delete).Methods("DELETE")
-	news.HandleFunc("/{id}", newsController.GetDetail).Methods("GET")
-	news.HandleFunc("", newsController.List).Methods("GET")
-
-	//tag endpoint
-	tag.HandleFunc("/", tagController.Create).Methods("POST")
-	tag.HandleFunc("/{id}", tagController.Update).Methods("PUT")
-	tag.HandleFunc("/{id}", tagController.Delete).Methods("DELETE")
-	tag.HandleFunc("", tagController.List).Methods("GET")
+	router.Use(middlewares.RequestID)
+	router.Use(clientIPResolver.Middleware)
+	router.Use(requestLogger.Middleware)
+	router.Use(requestMetrics.Middleware)
+	// throttled by client ip before the api key authenticator looks the key up
+	router.Use(ipRateLimiter.Middleware)
+	router.Use(jwtAuthenticator.Middleware)
+	router.Use(apiKeyAuthenticator.Middleware)
+
+	//health endpoints, probed by the orchestrator
+	router.HandleFunc("/healthz", healthController.Live).Methods("GET")
+	router.HandleFunc("/readyz", healthController.Ready).Methods("GET")
+
+	//metrics endpoint, scraped by prometheus
+	router.HandleFunc("/metrics", registry.Handler).Methods("GET")
+
+	//api description, every route below must be documented in docs/openapi.json
+	router.HandleFunc("/openapi.json", docsController.Spec).Methods("GET")
+	router.HandleFunc("/docs", docsController.Page).Methods("GET")
+
+	//current version of the api
+	v1 := apiV1{
+		newsController:   newsController,
+		tagController:    tagController,
+		authorController: authorController,
+		apiKeyController: apiKeyController,
+		auditController:  auditController,
+		groupMiddlewares: groupMiddlewares,
+	}
+	v1.register(router.PathPrefix("/v1").Subrouter())
+
+	//unversioned aliases of v1, served until their sunset. They share the rate limit buckets of v1
+	if config.Unversioned.Enabled {
+		unversioned := router.NewRoute().Subrouter()
+		unversioned.Use(unversionedDeprecation.Middleware)
+		v1.register(unversioned)
+	}
 
 	return router
 }
+
+//storage holds the repositories of the configured backend
+type storage struct {
+	news               repositories.INewsRepository
+	tags               repositories.ITagRepository
+	authors            repositories.IAuthorRepository
+	apiKeys            repositories.IAPIKeyRepository
+	audit              repositories.IAuditRepository
+	transactionManager repositories.ITransactionManager
+}
+
+//initStorage returns the repositories of the configured backend, db is not used by the memory backend and may be nil
+func initStorage(storageConfig config.StorageConfig, db *gorm.DB) storage {
+	if storageConfig.Backend == config.StorageMemory {
+		store := repositories.InitMemoryStore()
+		return storage{
+			news:               repositories.InitMemoryNewsRepository(store),
+			tags:               repositories.InitMemoryTagRepository(store),
+			authors:            repositories.InitMemoryAuthorRepository(store),
+			apiKeys:            repositories.InitMemoryAPIKeyRepository(store),
+			audit:              repositories.InitMemoryAuditRepository(store),
+			transactionManager: repositories.InitMemoryTransactionManager(store),
+		}
+	}
+	return storage{
+		news:               repositories.InitNewsRepository(db),
+		tags:               repositories.InitTagRepository(db),
+		authors:            repositories.InitAuthorRepository(db),
+		apiKeys:            repositories.InitAPIKeyRepository(db),
+		audit:              repositories.InitAuditRepository(db),
+		transactionManager: repositories.InitTransactionManager(db),
+	}
+}
+
+//routeGroupMiddlewares returns the rate limiter and the request timeout of a route group, in that order,
+//the same instances are used by every version of the api
+func routeGroupMiddlewares(config config.Config, group string) []mux.MiddlewareFunc {
+	rateLimiter := initRateLimiter(config.RateLimit, group)
+	timeout := middlewares.InitTimeout(config.RequestTimeout.Groups[group])
+	return []mux.MiddlewareFunc{rateLimiter.Middleware, timeout.Middleware}
+}
+
+func initRateLimiter(config config.RateLimitConfig, group string) *middlewares.RateLimiter {
+	limit := config.Groups[group]
+	return middlewares.InitRateLimiter(middlewares.RateLimitConfig{Rate: limit.Rate, Burst: limit.Burst, IdleTTL: config.IdleTTL})
+}
@@ -0,0 +1,179 @@
+package routes
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"news-topic-api/config"
+	"news-topic-api/docs"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func initTestRouter(unversioned config.UnversionedConfig) *mux.Router {
+	mockDB, _, _ := sqlmock.New()
+	db, _ := gorm.Open(postgres.New(postgres.Config{Conn: mockDB}), &gorm.Config{})
+	return new(Route).Init(config.Config{Unversioned: unversioned}, db)
+}
+
+//routedOperations lists the operations of the router, unversioned aliases are listed by their /v1 path
+func routedOperations(t *testing.T) map[string]bool {
+	router := initTestRouter(config.UnversionedConfig{Enabled: true})
+	operations := map[string]bool{}
+	err := router.Walk(func(route *mux.Route, parent *mux.Router, ancestors []*mux.Route) error {
+		methods, err := route.GetMethods()
+		if err != nil {
+			// subrouter prefixes do not serve requests themselves
+			return nil
+		}
+		template, err := route.GetPathTemplate()
+		if err != nil {
+			return err
+		}
+		if !strings.HasPrefix(template, "/v1/") && router.Match(httptest.NewRequest(methods[0], "/v1"+template, nil), &mux.RouteMatch{}) {
+			template = "/v1" + template
+		}
+		for _, method := range methods {
+			operations[fmt.Sprintf("%s %s", method, template)] = true
+		}
+		return nil
+	})
+	assert.Nil(t, err)
+	return operations
+}
+
+func documentedOperations(t *testing.T) map[string]bool {
+	var spec struct {
+		Paths map[string]map[string]json.RawMessage `json:"paths"`
+	}
+	assert.Nil(t, json.Unmarshal(docs.OpenAPI, &spec), "docs/openapi.json should be valid json")
+	operations := map[string]bool{}
+	for path, item := range spec.Paths {
+		for method := range item {
+			operations[fmt.Sprintf("%s %s", strings.ToUpper(method), path)] = true
+		}
+	}
+	return operations
+}
+
+func TestEveryRouteShouldBeDocumented(t *testing.T) {
+	documented := documentedOperations(t)
+	routed := routedOperations(t)
+	assert.NotEmpty(t, routed)
+	for operation := range routed {
+		assert.True(t, documented[operation], "%s is missing from docs/openapi.json", operation)
+	}
+}
+
+func TestEveryDocumentedOperationShouldBeRouted(t *testing.T) {
+	routed := routedOperations(t)
+	for operation := range documentedOperations(t) {
+		assert.True(t, routed[operation], "%s is documented in docs/openapi.json but not routed", operation)
+	}
+}
+
+func TestDocsPageShouldPinSwaggerUI(t *testing.T) {
+	assets := regexp.MustCompile(`<(?:link|script) [^>]*"https://unpkg\.com/[^"]*"[^>]*>`).FindAllString(string(docs.Page), -1)
+	assert.Len(t, assets, 2, "docs/index.html should load the Swagger UI stylesheet and bundle")
+	for _, asset := range assets {
+		assert.Regexp(t, `swagger-ui-dist@\d+\.\d+\.\d+/`, asset, "Swagger UI should be pinned to an exact release")
+		assert.Contains(t, asset, `crossorigin="anonymous"`)
+	}
+}
+
+func TestUnversionedAliasShouldAnswerDeprecation(t *testing.T) {
+	router := initTestRouter(config.UnversionedConfig{
+		Enabled:      true,
+		DeprecatedAt: time.Date(2026, time.November, 1, 0, 0, 0, 0, time.UTC),
+		Sunset:       time.Date(2027, time.May, 1, 0, 0, 0, 0, time.UTC),
+	})
+	response := httptest.NewRecorder()
+	router.ServeHTTP(response, httptest.NewRequest("GET", "/tag", nil))
+	assert.NotEqual(t, 404, response.Code, "the alias should be routed")
+	assert.Equal(t, "@1793491200", response.Header().Get("Deprecation"))
+	assert.Equal(t, "Sat, 01 May 2027 00:00:00 GMT", response.Header().Get("Sunset"))
+	assert.Equal(t, `</v1/tag>; rel="successor-version"`, response.Header().Get("Link"))
+
+	response = httptest.NewRecorder()
+	router.ServeHTTP(response, httptest.NewRequest("GET", "/v1/tag", nil))
+	assert.NotEqual(t, 404, response.Code, "the versioned route should be routed")
+	assert.Empty(t, response.Header().Get("Deprecation"))
+}
+
+func TestDisabledUnversionedAliasShouldNotBeRouted(t *testing.T) {
+	router := initTestRouter(config.UnversionedConfig{Enabled: false})
+	response := httptest.NewRecorder()
+	router.ServeHTTP(response, httptest.NewRequest("GET", "/tag", nil))
+	assert.Equal(t, 404, response.Code, "response code should be 404")
+}
+
+func TestMemoryStorageShouldServeWithoutDatabase(t *testing.T) {
+	route := new(Route)
+	router := route.Init(config.Config{
+		Storage: config.StorageConfig{Backend: config.StorageMemory},
+		Server:  config.ServerConfig{ReadinessTimeout: time.Second},
+	}, nil)
+	route.Probe.MarkReady()
+	for _, path := range []string{"/v1/tag", "/v1/author", "/v1/news"} {
+		response := httptest.NewRecorder()
+		router.ServeHTTP(response, httptest.NewRequest("GET", path, nil))
+		assert.Equal(t, 200, response.Code, "response code of %s should be 200", path)
+		assert.JSONEq(t, `{"status":200,"data":{"data":[]}}`, response.Body.String())
+	}
+	request := httptest.NewRequest("GET", "/v1/news", nil)
+	request.Header.Set("X-API-Key", "ntk_garbage_secret")
+	response := httptest.NewRecorder()
+	router.ServeHTTP(response, request)
+	assert.Equal(t, 401, response.Code, "api keys should be looked up in memory")
+	response = httptest.NewRecorder()
+	router.ServeHTTP(response, httptest.NewRequest("GET", "/readyz", nil))
+	assert.Equal(t, 200, response.Code, "readiness should not depend on a database")
+	response = httptest.NewRecorder()
+	router.ServeHTTP(response, httptest.NewRequest("GET", "/metrics", nil))
+	assert.Equal(t, 200, response.Code)
+	assert.NotContains(t, response.Body.String(), "db_")
+}
+
+func TestNewsCacheShouldCountHitsInMetrics(t *testing.T) {
+	router := new(Route).Init(config.Config{
+		Storage:   config.StorageConfig{Backend: config.StorageMemory},
+		NewsCache: config.NewsCacheConfig{Capacity: 10, TTL: time.Minute},
+	}, nil)
+	for i := 0; i < 2; i++ {
+		response := httptest.NewRecorder()
+		router.ServeHTTP(response, httptest.NewRequest("GET", "/v1/news?status=published", nil))
+		assert.Equal(t, 200, response.Code, "response code should be 200")
+	}
+	response := httptest.NewRecorder()
+	router.ServeHTTP(response, httptest.NewRequest("GET", "/metrics", nil))
+	assert.Contains(t, response.Body.String(), `news_cache_requests_total{operation="list",result="hit"} 1`)
+	assert.Contains(t, response.Body.String(), `news_cache_requests_total{operation="list",result="miss"} 1`)
+	assert.Contains(t, response.Body.String(), "news_cache_entries 1")
+}
+
+func TestIPRateLimitShouldRunBeforeAPIKeyLookup(t *testing.T) {
+	mockDB, testMock, _ := sqlmock.New()
+	db, _ := gorm.Open(postgres.New(postgres.Config{Conn: mockDB}), &gorm.Config{})
+	router := new(Route).Init(config.Config{
+		RateLimit: config.RateLimitConfig{IdleTTL: time.Minute, IP: config.RateLimitGroupConfig{Rate: 0.001, Burst: 1}},
+	}, db)
+	testMock.ExpectQuery(`SELECT (.+) FROM "api_keys"`).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	for i := 0; i < 2; i++ {
+		request := httptest.NewRequest("GET", "/v1/news", nil)
+		request.Header.Set("X-API-Key", "ntk_garbage_secret")
+		response := httptest.NewRecorder()
+		router.ServeHTTP(response, request)
+		if i == 1 {
+			assert.Equal(t, 429, response.Code, "response code should be 429")
+		}
+	}
+	assert.Nil(t, testMock.ExpectationsWereMet(), "only the first request should look the key up")
+}
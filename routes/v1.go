@@ -0,0 +1,68 @@
+package routes
+
+import (
+	"news-topic-api/controllers"
+	"news-topic-api/middlewares"
+	"news-topic-api/models"
+
+	"github.com/gorilla/mux"
+)
+
+//apiV1 registers the routes of the first version of the api. A later version gets its own type holding its
+//own controllers, and Init mounts it under its prefix next to this one
+type apiV1 struct {
+	newsController   controllers.NewsController
+	tagController    controllers.TagController
+	authorController controllers.AuthorController
+	apiKeyController controllers.APIKeyController
+	auditController  controllers.AuditController
+	groupMiddlewares map[string][]mux.MiddlewareFunc
+}
+
+//register adds the route groups of v1 to router, which is either mounted under /v1 or at the root for the
+//unversioned aliases
+func (a apiV1) register(router *mux.Router) {
+	news := a.group(router, "news", "/news")
+	tag := a.group(router, "tag", "/tag")
+	author := a.group(router, "author", "/author")
+	apiKey := a.group(router, "apikey", "/apikey")
+	audit := a.group(router, "audit", "/audit")
+
+	//news endpoint, only reads are open to anonymous callers
+	news.HandleFunc("/", middlewares.Authorized(models.ScopeNewsWrite, a.newsController.Create)).Methods("POST")
+	news.HandleFunc("/{id}", middlewares.Authorized(models.ScopeNewsWrite, a.newsController.Update)).Methods("PUT")
+	news.HandleFunc("/{id}", middlewares.Authorized(models.ScopeNewsWrite, a.newsController.Delete)).Methods("DELETE")
+	news.HandleFunc("/{id}/restore", middlewares.Authorized(models.ScopeNewsPublish, a.newsController.Restore)).Methods("POST")
+	news.HandleFunc("/{id}", middlewares.AllowAnonymous(models.ScopeNewsRead, a.newsController.GetDetail)).Methods("GET")
+	news.HandleFunc("", middlewares.AllowAnonymous(models.ScopeNewsRead, a.newsController.List)).Methods("GET")
+
+	//tag endpoint
+	tag.HandleFunc("/", middlewares.Authorized(models.ScopeTagAdmin, a.tagController.Create)).Methods("POST")
+	tag.HandleFunc("/{id}", middlewares.Authorized(models.ScopeTagAdmin, a.tagController.Update)).Methods("PUT")
+	tag.HandleFunc("/{id}", middlewares.Authorized(models.ScopeTagAdmin, a.tagController.Delete)).Methods("DELETE")
+	tag.HandleFunc("/{id}/restore", middlewares.Authorized(models.ScopeTagAdmin, a.tagController.Restore)).Methods("POST")
+	tag.HandleFunc("", middlewares.AllowAnonymous(models.ScopeNewsRead, a.tagController.List)).Methods("GET")
+
+	//author endpoint
+	author.HandleFunc("/", middlewares.Authorized(models.ScopeAuthorAdmin, a.authorController.Create)).Methods("POST")
+	author.HandleFunc("/{id}", middlewares.Authorized(models.ScopeAuthorAdmin, a.authorController.Update)).Methods("PUT")
+	author.HandleFunc("/{id}", middlewares.Authorized(models.ScopeAuthorAdmin, a.authorController.Delete)).Methods("DELETE")
+	author.HandleFunc("/{id}", middlewares.AllowAnonymous(models.ScopeNewsRead, a.authorController.GetDetail)).Methods("GET")
+	author.HandleFunc("", middlewares.AllowAnonymous(models.ScopeNewsRead, a.authorController.List)).Methods("GET")
+
+	//api key endpoint, keys can only be managed by interactive admins
+	apiKey.HandleFunc("/", middlewares.Authorized(models.ScopeAPIKeyAdmin, a.apiKeyController.Create)).Methods("POST")
+	apiKey.HandleFunc("/{id}/rotate", middlewares.Authorized(models.ScopeAPIKeyAdmin, a.apiKeyController.Rotate)).Methods("POST")
+	apiKey.HandleFunc("/{id}", middlewares.Authorized(models.ScopeAPIKeyAdmin, a.apiKeyController.Delete)).Methods("DELETE")
+	apiKey.HandleFunc("", middlewares.Authorized(models.ScopeAPIKeyAdmin, a.apiKeyController.List)).Methods("GET")
+
+	//audit endpoint
+	audit.HandleFunc("", middlewares.Authorized(models.ScopeAuditRead, a.auditController.List)).Methods("GET")
+}
+
+//group returns the subrouter of a route group guarded by its rate limiter and request timeout
+func (a apiV1) group(router *mux.Router, name string, prefix string) *mux.Router {
+	subrouter := router.PathPrefix(prefix).Subrouter()
+	subrouter.Use(a.groupMiddlewares[name]...)
+	return subrouter
+}
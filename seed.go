@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"news-topic-api/infrastructures"
+	"news-topic-api/repositories"
+	"news-topic-api/seed"
+	"os"
+)
+
+const seedUsage = `Usage: news-topic-api seed [flags]
+
+Generates topics, tags and news and inserts them in batches, the same -seed always
+generates the same data. Tags that already exist are reused.
+`
+
+//runSeed generates a dataset and inserts it through the repositories so every row is audited
+func runSeed(args []string) int {
+	defaults := seed.DefaultConfig()
+	flags := newFlagSet("seed", seedUsage)
+	randomSeed := flags.Int64("seed", defaults.Seed, "random seed")
+	topics := flags.Int("topics", defaults.Topics, "number of topics")
+	tags := flags.Int("tags", defaults.Tags, "number of tags")
+	news := flags.Int("news", defaults.News, "number of news")
+	published := flags.Float64("published", defaults.PublishedRatio, "share of published news, the rest are drafts")
+	maxTags := flags.Int("max-tags", defaults.MaxTagsPerNews, "maximum number of tags per news")
+	batchSize := flags.Int("batch", seed.DefaultBatchSize, "rows inserted per statement")
+	if exitCode, ok := parseFlags(flags, args); !ok {
+		return exitCode
+	}
+	config := seed.Config{
+		Seed:           *randomSeed,
+		Topics:         *topics,
+		Tags:           *tags,
+		News:           *news,
+		PublishedRatio: *published,
+		MaxTagsPerNews: *maxTags,
+	}
+	if err := config.Validate(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitUsage
+	}
+	if *batchSize < 1 {
+		fmt.Fprintln(os.Stderr, "-batch must be at least 1")
+		return exitUsage
+	}
+	_, db, err := openDatabase()
+	if err != nil {
+		return fail(err)
+	}
+	defer infrastructures.CloseDB(db)
+	seeder := seed.InitSeeder(repositories.InitTagRepository(db), repositories.InitNewsRepository(db), *batchSize)
+	result, err := seeder.Seed(context.Background(), seed.InitGenerator(config).Generate(), cliAuditMeta())
+	if err != nil {
+		return fail(err)
+	}
+	fmt.Printf("seeded %d topics, %d new tags (%d reused) and %d news\n", config.Topics, result.TagsCreated, result.TagsReused, result.News)
+	return exitOK
+}
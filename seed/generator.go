@@ -0,0 +1,209 @@
+package seed
+
+import (
+	"fmt"
+	"math/rand"
+	"news-topic-api/models"
+	"strings"
+)
+
+//Config tells how much data to generate and how
+type Config struct {
+	// Seed makes the generated data reproducible, the same seed always generates the same data
+	Seed           int64
+	Topics         int
+	Tags           int
+	News           int
+	PublishedRatio float64
+	MaxTagsPerNews int
+}
+
+//Dataset is generated data, the tags of each news are referenced by name until the tags are inserted
+type Dataset struct {
+	Topics []string
+	Tags   []models.Tag
+	News   []models.News
+}
+
+//DefaultConfig returns a config generating a small but varied dataset
+func DefaultConfig() Config {
+	return Config{
+		Seed:           1,
+		Topics:         6,
+		Tags:           30,
+		News:           200,
+		PublishedRatio: 0.7,
+		MaxTagsPerNews: 4,
+	}
+}
+
+//Validate checks the config can generate a dataset
+func (c Config) Validate() error {
+	if c.Topics < 1 {
+		return fmt.Errorf("topics must be at least 1")
+	}
+	if c.Tags < 0 || c.News < 0 {
+		return fmt.Errorf("tags and news cannot be negative")
+	}
+	if c.PublishedRatio < 0 || c.PublishedRatio > 1 {
+		return fmt.Errorf("published ratio must be between 0 and 1")
+	}
+	if c.MaxTagsPerNews < 0 {
+		return fmt.Errorf("max tags per news cannot be negative")
+	}
+	return nil
+}
+
+//Generator generates plausible topics, tags and news from a seeded random source
+type Generator struct {
+	config Config
+	random *rand.Rand
+}
+
+//InitGenerator initializes a generator given a valid config
+func InitGenerator(config Config) *Generator {
+	generator := new(Generator)
+	generator.config = config
+	return generator
+}
+
+//Generate returns a dataset that depends only on the config, calling it twice returns the same data
+func (g *Generator) Generate() Dataset {
+	g.random = rand.New(rand.NewSource(g.config.Seed))
+	topics := g.topics()
+	tags := g.tags()
+	return Dataset{Topics: topics, Tags: tags, News: g.news(topics, tags)}
+}
+
+//topics names the catalog topics first, then the same topics qualified by region
+func (g *Generator) topics() []string {
+	topics := make([]string, 0, g.config.Topics)
+	for i := 0; i < g.config.Topics; i++ {
+		name := catalog[i%len(catalog)].name
+		if round := i / len(catalog); round > 0 {
+			name = regions[(round-1)%len(regions)] + "-" + name
+			if round > len(regions) {
+				name = fmt.Sprintf("%s-%d", name, (round-1)/len(regions)+1)
+			}
+		}
+		topics = append(topics, name)
+	}
+	return topics
+}
+
+//tags names the topic tags first, then generic ones, then numbered variants, every name is unique
+func (g *Generator) tags() []models.Tag {
+	var pool []string
+	for i := 0; i < len(catalog[0].tags); i++ {
+		for _, vocabulary := range catalog {
+			pool = append(pool, vocabulary.tags[i])
+		}
+	}
+	pool = append(pool, genericTags...)
+	tags := make([]models.Tag, 0, g.config.Tags)
+	for i := 0; i < g.config.Tags; i++ {
+		name := pool[i%len(pool)]
+		if round := i / len(pool); round > 0 {
+			name = fmt.Sprintf("%s-%d", name, round+1)
+		}
+		tags = append(tags, models.Tag{Name: name})
+	}
+	return tags
+}
+
+func (g *Generator) news(topics []string, tags []models.Tag) []models.News {
+	newsList := make([]models.News, 0, g.config.News)
+	var popularity *rand.Zipf
+	if len(tags) > 1 {
+		// a few tags are used by most news, like real tag clouds
+		popularity = rand.NewZipf(g.random, 1.3, 2, uint64(len(tags)-1))
+	}
+	for i := 0; i < g.config.News; i++ {
+		topicIndex := g.random.Intn(len(topics))
+		vocabulary := catalog[topicIndex%len(catalog)]
+		subject := g.pick(vocabulary.subjects)
+		action := g.pick(vocabulary.actions)
+		object := g.pick(vocabulary.objects)
+		place := g.pick(places)
+		title := capitalize(fmt.Sprintf("%s %s %s", subject, action, object))
+		if g.random.Intn(3) == 0 {
+			title += " in " + place
+		}
+		status := models.StatusDraft
+		if g.random.Float64() < g.config.PublishedRatio {
+			status = models.StatusPublished
+		}
+		newsList = append(newsList, models.News{
+			Title:     title,
+			Thumbnail: fmt.Sprintf("https://picsum.photos/seed/news-%d-%d/640/360", g.config.Seed, i),
+			Summary:   g.sentence(place, subject, object),
+			Content:   g.content(place, subject, object),
+			Topic:     topics[topicIndex],
+			Status:    status,
+			Tags:      g.newsTags(tags, vocabulary, popularity),
+		})
+	}
+	return newsList
+}
+
+//newsTags prefers a tag of the news topic then draws the others by popularity
+func (g *Generator) newsTags(tags []models.Tag, vocabulary topicVocabulary, popularity *rand.Zipf) []models.Tag {
+	if len(tags) == 0 || g.config.MaxTagsPerNews == 0 {
+		return nil
+	}
+	count := 1 + g.random.Intn(g.config.MaxTagsPerNews)
+	if count > len(tags) {
+		count = len(tags)
+	}
+	picked := map[int]bool{}
+	var newsTags []models.Tag
+	add := func(index int) {
+		if !picked[index] {
+			picked[index] = true
+			newsTags = append(newsTags, models.Tag{Name: tags[index].Name})
+		}
+	}
+	topicTag := vocabulary.tags[g.random.Intn(len(vocabulary.tags))]
+	for index, tag := range tags {
+		if tag.Name == topicTag {
+			add(index)
+			break
+		}
+	}
+	for attempts := 0; len(newsTags) < count && attempts < count*10; attempts++ {
+		if popularity == nil {
+			add(0)
+			continue
+		}
+		add(int(popularity.Uint64()))
+	}
+	return newsTags
+}
+
+func (g *Generator) content(place string, subject string, object string) string {
+	paragraphs := make([]string, 3+g.random.Intn(3))
+	for i := range paragraphs {
+		lines := make([]string, 3+g.random.Intn(3))
+		for j := range lines {
+			lines[j] = g.sentence(place, subject, object)
+		}
+		paragraphs[i] = strings.Join(lines, " ")
+	}
+	return strings.Join(paragraphs, "\n\n")
+}
+
+func (g *Generator) sentence(place string, subject string, object string) string {
+	template := g.pick(sentences)
+	return capitalize(fmt.Sprintf(template, place, subject, object, g.pick(weekdays), 2+g.random.Intn(97)))
+}
+
+func (g *Generator) pick(words []string) string {
+	return words[g.random.Intn(len(words))]
+}
+
+func capitalize(text string) string {
+	if text == "" {
+		return text
+	}
+	return strings.ToUpper(text[:1]) + text[1:]
+}
@@ -0,0 +1,105 @@
+package seed
+
+import (
+	"news-topic-api/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func getTestConfig() Config {
+	config := DefaultConfig()
+	config.Seed = 42
+	return config
+}
+
+func TestGenerateIsDeterministic(t *testing.T) {
+	first := InitGenerator(getTestConfig()).Generate()
+	second := InitGenerator(getTestConfig()).Generate()
+	assert.Equal(t, first, second)
+
+	config := getTestConfig()
+	config.Seed = 43
+	other := InitGenerator(config).Generate()
+	assert.NotEqual(t, first.News, other.News)
+}
+
+func TestGenerateHonorsCounts(t *testing.T) {
+	config := Config{Seed: 7, Topics: 20, Tags: 120, News: 500, PublishedRatio: 0.5, MaxTagsPerNews: 3}
+	dataset := InitGenerator(config).Generate()
+	assert.Len(t, dataset.Topics, 20)
+	assert.Len(t, dataset.Tags, 120)
+	assert.Len(t, dataset.News, 500)
+
+	topics := map[string]bool{}
+	for _, topic := range dataset.Topics {
+		assert.False(t, topics[topic], "duplicate topic %s", topic)
+		topics[topic] = true
+	}
+	tags := map[string]bool{}
+	for _, tag := range dataset.Tags {
+		assert.False(t, tags[tag.Name], "duplicate tag %s", tag.Name)
+		tags[tag.Name] = true
+	}
+	for _, news := range dataset.News {
+		assert.True(t, topics[news.Topic])
+		assert.NotEmpty(t, news.Title)
+		assert.NotEmpty(t, news.Summary)
+		assert.NotEmpty(t, news.Content)
+		assert.NotEmpty(t, news.Thumbnail)
+		assert.True(t, len(news.Tags) >= 1 && len(news.Tags) <= 3, "news has %d tags", len(news.Tags))
+		seen := map[string]bool{}
+		for _, tag := range news.Tags {
+			assert.True(t, tags[tag.Name], "unknown tag %s", tag.Name)
+			assert.False(t, seen[tag.Name], "tag %s repeated", tag.Name)
+			seen[tag.Name] = true
+		}
+	}
+}
+
+func TestGenerateMixesStatuses(t *testing.T) {
+	config := getTestConfig()
+	config.News = 1000
+	config.PublishedRatio = 0.7
+	published := 0
+	for _, news := range InitGenerator(config).Generate().News {
+		assert.Contains(t, []string{models.StatusDraft, models.StatusPublished}, news.Status)
+		if news.Status == models.StatusPublished {
+			published++
+		}
+	}
+	assert.InDelta(t, 700, published, 60)
+}
+
+func TestGenerateSkewsTagPopularity(t *testing.T) {
+	config := getTestConfig()
+	config.News = 1000
+	usage := map[string]int{}
+	for _, news := range InitGenerator(config).Generate().News {
+		for _, tag := range news.Tags {
+			usage[tag.Name]++
+		}
+	}
+	tags := InitGenerator(config).Generate().Tags
+	assert.Greater(t, usage[tags[0].Name], 4*usage[tags[len(tags)-1].Name])
+}
+
+func TestGenerateWithoutTags(t *testing.T) {
+	config := getTestConfig()
+	config.Tags = 0
+	for _, news := range InitGenerator(config).Generate().News {
+		assert.Empty(t, news.Tags)
+	}
+}
+
+func TestConfigValidate(t *testing.T) {
+	assert.Nil(t, DefaultConfig().Validate())
+	for _, config := range []Config{
+		{Topics: 0, PublishedRatio: 0.5},
+		{Topics: 1, News: -1},
+		{Topics: 1, PublishedRatio: 1.5},
+		{Topics: 1, MaxTagsPerNews: -1},
+	} {
+		assert.NotNil(t, config.Validate(), "%+v", config)
+	}
+}
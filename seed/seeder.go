@@ -0,0 +1,92 @@
+package seed
+
+import (
+	"context"
+	"fmt"
+	"news-topic-api/models"
+	"news-topic-api/repositories"
+)
+
+//DefaultBatchSize is the number of rows inserted per statement
+const DefaultBatchSize = 100
+
+//Result counts what a seeding run inserted
+type Result struct {
+	TagsCreated int
+	TagsReused  int
+	News        int
+}
+
+//Seeder inserts generated datasets through the repositories, every inserted row is audited
+type Seeder struct {
+	tagRepository  repositories.ITagRepository
+	newsRepository repositories.INewsRepository
+	batchSize      int
+}
+
+//InitSeeder initializes a seeder given the repositories and the rows per insert
+func InitSeeder(tagRepository repositories.ITagRepository, newsRepository repositories.INewsRepository, batchSize int) Seeder {
+	seeder := new(Seeder)
+	seeder.tagRepository = tagRepository
+	seeder.newsRepository = newsRepository
+	seeder.batchSize = batchSize
+	if seeder.batchSize < 1 {
+		seeder.batchSize = DefaultBatchSize
+	}
+	return *seeder
+}
+
+//Seed inserts the tags of the dataset that do not exist yet, then the news in batches,
+//each batch is committed on its own so a failure keeps the batches already inserted
+func (s Seeder) Seed(ctx context.Context, dataset Dataset, meta models.AuditMeta) (Result, error) {
+	var result Result
+	existing, err := s.tagRepository.List(ctx)
+	if err != nil {
+		return result, err
+	}
+	tagsByName := map[string]models.Tag{}
+	for _, tag := range existing {
+		tagsByName[tag.Name] = tag
+	}
+	var missingTags []models.Tag
+	for _, tag := range dataset.Tags {
+		if _, found := tagsByName[tag.Name]; found {
+			result.TagsReused++
+			continue
+		}
+		missingTags = append(missingTags, tag)
+	}
+	tagAudit := models.NewAuditLog(meta, models.AuditActionCreate, models.AuditEntityTag)
+	createdTags, err := s.tagRepository.CreateBatch(ctx, missingTags, s.batchSize, tagAudit)
+	if err != nil {
+		return result, fmt.Errorf("inserting tags: %w", err)
+	}
+	for _, tag := range createdTags {
+		tagsByName[tag.Name] = tag
+	}
+	result.TagsCreated = len(createdTags)
+
+	newsAudit := models.NewAuditLog(meta, models.AuditActionCreate, models.AuditEntityNews)
+	for start := 0; start < len(dataset.News); start += s.batchSize {
+		end := start + s.batchSize
+		if end > len(dataset.News) {
+			end = len(dataset.News)
+		}
+		batch := make([]models.News, 0, end-start)
+		for _, news := range dataset.News[start:end] {
+			news.CreatedBy = meta.Actor
+			tags := make([]models.Tag, 0, len(news.Tags))
+			for _, tag := range news.Tags {
+				tags = append(tags, tagsByName[tag.Name])
+			}
+			news.Tags = tags
+			batch = append(batch, news)
+		}
+		inserted, err := s.newsRepository.CreateBatch(ctx, batch, s.batchSize, newsAudit)
+		if err != nil {
+			return result, fmt.Errorf("inserting news %d to %d: %w", start+1, end, err)
+		}
+		result.News += len(inserted)
+	}
+	return result, nil
+}
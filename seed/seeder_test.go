@@ -0,0 +1,81 @@
+package seed
+
+import (
+	"context"
+	"fmt"
+	mockRepositories "news-topic-api/mocks/repositories"
+	"news-topic-api/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func getMockDataset() Dataset {
+	return Dataset{
+		Topics: []string{"economy"},
+		Tags:   []models.Tag{{Name: "markets"}, {Name: "banking"}},
+		News: []models.News{
+			{Title: "one", Topic: "economy", Tags: []models.Tag{{Name: "markets"}}},
+			{Title: "two", Topic: "economy", Tags: []models.Tag{{Name: "banking"}, {Name: "markets"}}},
+			{Title: "three", Topic: "economy"},
+		},
+	}
+}
+
+func getMockAuditMeta() models.AuditMeta {
+	return models.AuditMeta{Actor: "cli"}
+}
+
+func TestSeedReusesTagsAndInsertsNewsInBatches(t *testing.T) {
+	mockedTagRepository := new(mockRepositories.ITagRepository)
+	mockedNewsRepository := new(mockRepositories.INewsRepository)
+	markets := models.Tag{Model: gorm.Model{ID: 1}, Name: "markets"}
+	banking := models.Tag{Model: gorm.Model{ID: 2}, Name: "banking"}
+	mockedTagRepository.On("List", mock.Anything).Return([]models.Tag{markets}, nil)
+	mockedTagRepository.On("CreateBatch", mock.Anything, []models.Tag{{Name: "banking"}}, 2, mock.MatchedBy(func(audit models.AuditLog) bool {
+		return audit.Action == models.AuditActionCreate && audit.EntityType == models.AuditEntityTag && audit.Actor == "cli"
+	})).Return([]models.Tag{banking}, nil)
+	firstBatch := mock.MatchedBy(func(newsList []models.News) bool {
+		return len(newsList) == 2 && newsList[0].Tags[0].ID == 1 && newsList[1].Tags[0].ID == 2 && newsList[1].CreatedBy == "cli"
+	})
+	secondBatch := mock.MatchedBy(func(newsList []models.News) bool {
+		return len(newsList) == 1 && newsList[0].Title == "three"
+	})
+	newsAudit := mock.MatchedBy(func(audit models.AuditLog) bool {
+		return audit.Action == models.AuditActionCreate && audit.EntityType == models.AuditEntityNews
+	})
+	mockedNewsRepository.On("CreateBatch", mock.Anything, firstBatch, 2, newsAudit).Return(make([]models.News, 2), nil).Once()
+	mockedNewsRepository.On("CreateBatch", mock.Anything, secondBatch, 2, newsAudit).Return(make([]models.News, 1), nil).Once()
+
+	seeder := InitSeeder(mockedTagRepository, mockedNewsRepository, 2)
+	result, err := seeder.Seed(context.Background(), getMockDataset(), getMockAuditMeta())
+	assert.Nil(t, err)
+	assert.Equal(t, Result{TagsCreated: 1, TagsReused: 1, News: 3}, result)
+	mockedTagRepository.AssertExpectations(t)
+	mockedNewsRepository.AssertExpectations(t)
+}
+
+func TestSeedStopsOnFailedBatch(t *testing.T) {
+	mockedTagRepository := new(mockRepositories.ITagRepository)
+	mockedNewsRepository := new(mockRepositories.INewsRepository)
+	mockedTagRepository.On("List", mock.Anything).Return([]models.Tag{}, nil)
+	mockedTagRepository.On("CreateBatch", mock.Anything, mock.Anything, 2, mock.Anything).Return([]models.Tag{{Name: "markets"}, {Name: "banking"}}, nil)
+	mockedNewsRepository.On("CreateBatch", mock.Anything, mock.Anything, 2, mock.Anything).Return([]models.News{}, fmt.Errorf("insert failed")).Once()
+
+	seeder := InitSeeder(mockedTagRepository, mockedNewsRepository, 2)
+	result, err := seeder.Seed(context.Background(), getMockDataset(), getMockAuditMeta())
+	assert.NotNil(t, err)
+	assert.Equal(t, 0, result.News)
+	mockedNewsRepository.AssertNumberOfCalls(t, "CreateBatch", 1)
+}
+
+func TestSeedTagListFailedReturnError(t *testing.T) {
+	mockedTagRepository := new(mockRepositories.ITagRepository)
+	mockedNewsRepository := new(mockRepositories.INewsRepository)
+	mockedTagRepository.On("List", mock.Anything).Return([]models.Tag{}, fmt.Errorf("list failed"))
+	seeder := InitSeeder(mockedTagRepository, mockedNewsRepository, 0)
+	_, err := seeder.Seed(context.Background(), getMockDataset(), getMockAuditMeta())
+	assert.NotNil(t, err)
+}
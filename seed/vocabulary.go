@@ -0,0 +1,92 @@
+package seed
+
+//topicVocabulary holds the words news of a topic are written with
+type topicVocabulary struct {
+	name     string
+	subjects []string
+	actions  []string
+	objects  []string
+	tags     []string
+}
+
+var catalog = []topicVocabulary{
+	{
+		name:     "politics",
+		subjects: []string{"parliament", "the prime minister", "the opposition", "the coalition", "the senate", "regional governors", "the election commission"},
+		actions:  []string{"approves", "rejects", "debates", "delays", "announces", "revises", "challenges"},
+		objects:  []string{"the annual budget", "electoral reform", "a new cabinet", "the anti-corruption bill", "regional autonomy plans", "the defence spending review"},
+		tags:     []string{"election", "parliament", "government", "policy", "diplomacy"},
+	},
+	{
+		name:     "economy",
+		subjects: []string{"the central bank", "exporters", "retailers", "the finance ministry", "small businesses", "investors", "the statistics bureau"},
+		actions:  []string{"raises", "cuts", "forecasts", "reports", "warns about", "welcomes", "braces for"},
+		objects:  []string{"interest rates", "quarterly growth", "inflation", "fuel subsidies", "the trade deficit", "consumer spending", "new tax incentives"},
+		tags:     []string{"inflation", "markets", "banking", "trade", "jobs"},
+	},
+	{
+		name:     "technology",
+		subjects: []string{"a local startup", "chip makers", "the telecom regulator", "researchers", "a ride-hailing app", "cloud providers", "the digital ministry"},
+		actions:  []string{"launches", "unveils", "tests", "acquires", "opens", "rolls out", "pauses"},
+		objects:  []string{"a faster mobile network", "an ai assistant", "a payments platform", "a new data centre", "an electric scooter", "stricter privacy rules"},
+		tags:     []string{"startups", "ai", "cybersecurity", "fintech", "gadgets"},
+	},
+	{
+		name:     "sports",
+		subjects: []string{"the national team", "the league leaders", "the defending champions", "a teenage sprinter", "the football federation", "the home side"},
+		actions:  []string{"wins", "loses", "secures", "clinches", "postpones", "prepares for", "celebrates"},
+		objects:  []string{"the cup final", "a place in the semi-finals", "a new coach", "a record transfer", "the season opener", "a gold medal"},
+		tags:     []string{"football", "badminton", "olympics", "transfers", "motorsport"},
+	},
+	{
+		name:     "health",
+		subjects: []string{"hospitals", "the health ministry", "doctors", "vaccine makers", "community clinics", "public health officials"},
+		actions:  []string{"expands", "extends", "warns about", "launches", "reviews", "funds", "tracks"},
+		objects:  []string{"vaccination hours", "a dengue outbreak", "free health screenings", "mental health services", "a new cancer ward", "hospital waiting times"},
+		tags:     []string{"vaccines", "hospitals", "nutrition", "mental-health", "outbreak"},
+	},
+	{
+		name:     "environment",
+		subjects: []string{"farmers", "coastal towns", "the forestry agency", "climate scientists", "the energy ministry", "volunteers"},
+		actions:  []string{"restores", "protects", "measures", "plans", "opposes", "cleans up", "invests in"},
+		objects:  []string{"mangrove forests", "solar farms", "river pollution", "seasonal floods", "plastic waste", "coral reefs", "air quality"},
+		tags:     []string{"climate", "energy", "wildlife", "pollution", "forests"},
+	},
+	{
+		name:     "culture",
+		subjects: []string{"the national museum", "local artists", "film makers", "a folk music festival", "publishers", "the culture ministry"},
+		actions:  []string{"opens", "celebrates", "revives", "premieres", "showcases", "honours", "archives"},
+		objects:  []string{"a batik exhibition", "traditional dance", "an award-winning film", "a new literary prize", "rare manuscripts", "street food heritage"},
+		tags:     []string{"film", "music", "books", "heritage", "art"},
+	},
+	{
+		name:     "education",
+		subjects: []string{"universities", "teachers", "the education ministry", "students", "rural schools", "scholarship boards"},
+		actions:  []string{"introduces", "expands", "protests", "reforms", "launches", "doubles", "trials"},
+		objects:  []string{"the national curriculum", "online classes", "entrance exams", "teacher training", "school meals", "research grants"},
+		tags:     []string{"schools", "universities", "scholarships", "curriculum", "students"},
+	},
+}
+
+//regions qualify topics once the catalog runs out, e.g. asia-economy
+var regions = []string{"national", "asia", "europe", "americas", "africa", "oceania", "middle-east"}
+
+//genericTags are used after every topic tag
+var genericTags = []string{"breaking", "analysis", "opinion", "interview", "explainer", "investigation", "local", "world", "feature", "data"}
+
+var places = []string{"Jakarta", "Surabaya", "Bandung", "Medan", "Makassar", "Yogyakarta", "Denpasar", "Semarang", "Palembang", "Balikpapan"}
+
+var sentences = []string{
+	"Officials in %[1]s said the decision about %[3]s followed months of consultation.",
+	"%[2]s told reporters on %[4]s that more details would be shared next week.",
+	"Critics in %[1]s argue the plan for %[3]s leaves too many questions open.",
+	"Around %[5]d people attended a public hearing on %[3]s in %[1]s.",
+	"Analysts expect %[3]s to remain a priority for %[2]s through the rest of the year.",
+	"The latest figures show a change of %[5]d percent compared with the same period last year.",
+	"Residents of %[1]s gave a mixed reaction when asked about %[3]s.",
+	"A spokesperson for %[2]s declined to comment on the timeline.",
+	"Observers say %[2]s will face pressure to deliver results before %[4]s.",
+	"Similar efforts in %[1]s were scaled back after %[5]d months.",
+}
+
+var weekdays = []string{"Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday", "Sunday"}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"context"
+	"github.com/gorilla/handlers"
+	"net"
+	"net/http"
+	"news-topic-api/config"
+	"news-topic-api/health"
+	"news-topic-api/helpers"
+	"news-topic-api/infrastructures"
+	"news-topic-api/routes"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const serveUsage = `Usage: news-topic-api serve [flags]
+
+Runs the http api until SIGTERM or SIGINT, then drains in-flight requests.
+`
+
+//runServe runs the api until it is asked to stop and returns the exit code
+func runServe(args []string) int {
+	flags := newFlagSet("serve", serveUsage)
+	port := flags.Int("port", 0, "port to listen on, overrides server.port")
+	if exitCode, ok := parseFlags(flags, args); !ok {
+		return exitCode
+	}
+	logger := helpers.DefaultLogger()
+	cfg, db, err := openStorage()
+	if err != nil {
+		logger.Error("unable to start service", err, nil)
+		return exitFailure
+	}
+	if *port != 0 {
+		cfg.Server.Port = *port
+	}
+	if db == nil {
+		logger.Info("serving from memory without a database, every change is lost on exit", nil)
+	} else if cfg.Database.AutoMigrate {
+		if err := migrateUp(db); err != nil {
+			logger.Error("unable to migrate the database", err, nil)
+			infrastructures.CloseDB(db)
+			return exitFailure
+		}
+	}
+
+	var rt routes.Route
+
+	r := rt.Init(cfg, db)
+
+	headersOK := handlers.AllowedHeaders([]string{"X-Requested-With", "Content-Type", "Authorization", "X-API-Key", "X-Request-ID"})
+	originsOK := handlers.AllowedOrigins(cfg.CORS.AllowedOrigins)
+	methodsOK := handlers.AllowedMethods([]string{"GET", "POST", "OPTIONS", "DELETE", "PUT"})
+	exposedOK := handlers.ExposedHeaders([]string{"X-Request-ID", "Retry-After", "RateLimit-Limit", "RateLimit-Remaining", "RateLimit-Reset"})
+
+	server := &http.Server{
+		Addr:              ":" + strconv.Itoa(cfg.Server.Port),
+		Handler:           handlers.CORS(originsOK, headersOK, methodsOK, exposedOK)(r),
+		ReadHeaderTimeout: cfg.Server.ReadHeaderTimeout,
+		ReadTimeout:       cfg.Server.ReadTimeout,
+		WriteTimeout:      cfg.Server.WriteTimeout,
+		IdleTimeout:       cfg.Server.IdleTimeout,
+	}
+	listener, err := net.Listen("tcp", server.Addr)
+	if err != nil {
+		logger.Error("unable to start service", err, nil)
+		infrastructures.CloseDB(db)
+		return exitFailure
+	}
+	// the port is bound, connections queue on the listener until Serve accepts them
+	rt.Probe.MarkReady()
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- server.Serve(listener)
+	}()
+	logger.Info("server started", helpers.LogFields{"port": cfg.Server.Port})
+
+	var gracefulStop = make(chan os.Signal, 1)
+	signal.Notify(gracefulStop, syscall.SIGTERM)
+	signal.Notify(gracefulStop, syscall.SIGINT)
+	select {
+	case sig := <-gracefulStop:
+		logger.Info("caught signal, shutting down", helpers.LogFields{"signal": sig.String()})
+		return shutdown(server, rt.Probe, db, cfg.Server)
+	case err := <-serveErr:
+		logger.Error("server stopped unexpectedly", err, nil)
+		infrastructures.CloseDB(db)
+		return exitFailure
+	}
+}
+
+//shutdown fails readiness first so the load balancer stops routing new requests,
+//then drains in-flight requests within the deadline and closes the database pool last
+func shutdown(server *http.Server, probe *health.Probe, db *gorm.DB, serverConfig config.ServerConfig) int {
+	logger := helpers.DefaultLogger()
+	exitCode := exitOK
+	probe.MarkDraining()
+	time.Sleep(serverConfig.ShutdownDrainDelay)
+
+	ctx, cancel := context.WithTimeout(context.Background(), serverConfig.ShutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(ctx); err != nil {
+		logger.Error("requests still in flight after the shutdown deadline, closing their connections", err, nil)
+		server.Close()
+		exitCode = exitFailure
+	}
+	if err := infrastructures.CloseDB(db); err != nil {
+		logger.Error("unable to close the database pool", err, nil)
+		exitCode = exitFailure
+	}
+	logger.Info("shutdown complete", nil)
+	return exitCode
+}
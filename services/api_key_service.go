@@ -0,0 +1,192 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"news-topic-api/models"
+	"news-topic-api/repositories"
+	"strings"
+	"time"
+)
+
+const (
+	apiKeyPrefix = "ntk"
+	//lastUsedResolution avoids a write on every request made with the same key
+	lastUsedResolution = time.Minute
+)
+
+//IAPIKeyService interface for api key service
+type IAPIKeyService interface {
+	Mint(ctx context.Context, owner string, apiKey models.APIKey) (models.APIKeySecret, error)
+	Rotate(ctx context.Context, apiKeyID uint) (models.APIKeySecret, error)
+	Revoke(ctx context.Context, apiKeyID uint) (error)
+	List(ctx context.Context) (models.APIKeysList, error)
+	Authenticate(ctx context.Context, plaintext string) (models.APIKey, error)
+}
+
+//APIKeyService ...
+type APIKeyService struct {
+	apiKeyRepository repositories.IAPIKeyRepository
+	now func() time.Time
+}
+
+//InitAPIKeyService initialize an api key service instance with specific api key repository
+func InitAPIKeyService(apiKeyRepository repositories.IAPIKeyRepository) IAPIKeyService {
+	apiKeyService := new(APIKeyService)
+	apiKeyService.apiKeyRepository = apiKeyRepository
+	apiKeyService.now = time.Now
+	return apiKeyService
+}
+
+//Mint creates a new api key and returns its plaintext, which is not stored anywhere
+func (a APIKeyService) Mint(ctx context.Context, owner string, apiKey models.APIKey) (models.APIKeySecret, error) {
+	if strings.TrimSpace(apiKey.Name) == "" {
+		return models.APIKeySecret{}, invalidField("name", "api key name is required")
+	}
+	scopes, err := normalizeScopes(apiKey.Scopes)
+	if err != nil {
+		return models.APIKeySecret{}, err
+	}
+	if apiKey.ExpiresAt != nil && !apiKey.ExpiresAt.After(a.now()) {
+		return models.APIKeySecret{}, invalidField("expires_at", "api key expiry must be in the future")
+	}
+	plaintext, prefix, err := generateAPIKey()
+	if err != nil {
+		return models.APIKeySecret{}, err
+	}
+	newAPIKey := models.APIKey{
+		Name: apiKey.Name,
+		Owner: owner,
+		Prefix: prefix,
+		KeyHash: hashAPIKey(plaintext),
+		Scopes: scopes,
+		ExpiresAt: apiKey.ExpiresAt,
+	}
+	instance, err := a.apiKeyRepository.Create(ctx, newAPIKey)
+	if err != nil {
+		return models.APIKeySecret{}, domainError(err, "api key", 0)
+	}
+	return models.APIKeySecret{APIKey: instance, Key: plaintext}, nil
+}
+
+//Rotate replaces the secret of an api key, invalidating the previous plaintext
+func (a APIKeyService) Rotate(ctx context.Context, apiKeyID uint) (models.APIKeySecret, error) {
+	current, err := a.apiKeyRepository.GetByID(ctx, apiKeyID)
+	if err != nil {
+		return models.APIKeySecret{}, domainError(err, "api key", apiKeyID)
+	}
+	if current.RevokedAt != nil {
+		return models.APIKeySecret{}, ConflictError{Reason: "revoked api key can not be rotated"}
+	}
+	plaintext, prefix, err := generateAPIKey()
+	if err != nil {
+		return models.APIKeySecret{}, err
+	}
+	instance, err := a.apiKeyRepository.UpdateSecret(ctx, apiKeyID, prefix, hashAPIKey(plaintext))
+	if err != nil {
+		return models.APIKeySecret{}, domainError(err, "api key", apiKeyID)
+	}
+	return models.APIKeySecret{APIKey: instance, Key: plaintext}, nil
+}
+
+//Revoke ...
+func (a APIKeyService) Revoke(ctx context.Context, apiKeyID uint) (error) {
+	err := a.apiKeyRepository.Revoke(ctx, apiKeyID, a.now())
+	return domainError(err, "api key", apiKeyID)
+}
+
+//List ...
+func (a APIKeyService) List(ctx context.Context) (models.APIKeysList, error) {
+	response, err := a.apiKeyRepository.List(ctx)
+	if err != nil {
+		return models.APIKeysList{}, domainError(err, "api key", 0)
+	}
+	return models.APIKeysList{Data: response}, nil
+}
+
+//Authenticate resolves a plaintext key into its active api key and records its usage
+func (a APIKeyService) Authenticate(ctx context.Context, plaintext string) (models.APIKey, error) {
+	parts := strings.SplitN(plaintext, "_", 3)
+	if len(parts) != 3 || parts[0] != apiKeyPrefix {
+		return models.APIKey{}, fmt.Errorf("invalid api key")
+	}
+	apiKey, err := a.apiKeyRepository.GetByPrefix(ctx, parts[1])
+	if err != nil {
+		// a lookup abandoned along with the request or failing with the database says nothing about the key
+		if ctx.Err() != nil {
+			return models.APIKey{}, ctx.Err()
+		}
+		if errors.Is(err, repositories.ErrUnavailable) {
+			return models.APIKey{}, UnavailableError{Cause: err}
+		}
+		return models.APIKey{}, fmt.Errorf("invalid api key")
+	}
+	if subtle.ConstantTimeCompare([]byte(apiKey.KeyHash), []byte(hashAPIKey(plaintext))) != 1 {
+		return models.APIKey{}, fmt.Errorf("invalid api key")
+	}
+	now := a.now()
+	if apiKey.RevokedAt != nil {
+		return models.APIKey{}, fmt.Errorf("api key has been revoked")
+	}
+	if apiKey.ExpiresAt != nil && now.After(*apiKey.ExpiresAt) {
+		return models.APIKey{}, fmt.Errorf("api key is expired")
+	}
+	if apiKey.LastUsedAt == nil || now.Sub(*apiKey.LastUsedAt) >= lastUsedResolution {
+		if err := a.apiKeyRepository.TouchLastUsed(ctx, apiKey.ID, now); err == nil {
+			apiKey.LastUsedAt = &now
+		}
+	}
+	return apiKey, nil
+}
+
+func normalizeScopes(requested []string) ([]string, error) {
+	if len(requested) == 0 {
+		return nil, invalidField("scopes", "at least one scope is required")
+	}
+	seen := make(map[string]bool)
+	scopes := []string{}
+	for _, scope := range requested {
+		if !isGrantableScope(scope) {
+			return nil, invalidField("scopes", "scope %q can not be granted", scope)
+		}
+		if !seen[scope] {
+			seen[scope] = true
+			scopes = append(scopes, scope)
+		}
+	}
+	return scopes, nil
+}
+
+func isGrantableScope(scope string) bool {
+	for _, grantable := range models.GrantableScopes {
+		if grantable == scope {
+			return true
+		}
+	}
+	return false
+}
+
+func generateAPIKey() (string, string, error) {
+	prefixBytes := make([]byte, 6)
+	secretBytes := make([]byte, 32)
+	if _, err := rand.Read(prefixBytes); err != nil {
+		return "", "", err
+	}
+	if _, err := rand.Read(secretBytes); err != nil {
+		return "", "", err
+	}
+	prefix := hex.EncodeToString(prefixBytes)
+	plaintext := fmt.Sprintf("%s_%s_%s", apiKeyPrefix, prefix, base64.RawURLEncoding.EncodeToString(secretBytes))
+	return plaintext, prefix, nil
+}
+
+func hashAPIKey(plaintext string) string {
+	sum := sha256.Sum256([]byte(plaintext))
+	return hex.EncodeToString(sum[:])
+}
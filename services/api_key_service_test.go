@@ -0,0 +1,176 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	mockRepositories "news-topic-api/mocks/repositories"
+	"news-topic-api/models"
+	"strings"
+	"testing"
+	"time"
+)
+
+var apiKeyTestNow = time.Date(2021, 5, 1, 12, 0, 0, 0, time.UTC)
+
+func getMockAPIKeyRequest() models.APIKey {
+	entity := models.APIKey{
+		Name: "ingestion job",
+		Scopes: []string{models.ScopeNewsRead, models.ScopeNewsWrite},
+	}
+	return entity
+}
+
+func getTestAPIKeyService(apiKeyRepository *mockRepositories.IAPIKeyRepository) APIKeyService {
+	apiKeyService := InitAPIKeyService(apiKeyRepository).(*APIKeyService)
+	apiKeyService.now = func() time.Time { return apiKeyTestNow }
+	return *apiKeyService
+}
+
+func mintTestAPIKey(t *testing.T) (models.APIKey, string) {
+	mockedAPIKeyRepository := new(mockRepositories.IAPIKeyRepository)
+	mockedAPIKeyRepository.On("Create", mock.Anything, mock.Anything).Return(func(ctx context.Context, apiKey models.APIKey) models.APIKey { return apiKey }, nil)
+	apiKeyService := getTestAPIKeyService(mockedAPIKeyRepository)
+	response, err := apiKeyService.Mint(context.Background(), "42", getMockAPIKeyRequest())
+	assert.Nil(t, err, "There should be no error")
+	return response.APIKey, response.Key
+}
+
+func TestMintAPIKeySuccessReturnPlaintextOnce(t *testing.T) {
+	apiKey, plaintext := mintTestAPIKey(t)
+	assert.True(t, strings.HasPrefix(plaintext, "ntk_"+apiKey.Prefix+"_"), "Plaintext should embed the prefix")
+	assert.NotEqual(t, plaintext, apiKey.KeyHash, "Only the hash should be stored")
+	assert.Equal(t, "42", apiKey.Owner)
+	assert.Equal(t, []string{models.ScopeNewsRead, models.ScopeNewsWrite}, []string(apiKey.Scopes))
+}
+
+func TestMintAPIKeyUnknownScopeReturnError(t *testing.T) {
+	mockedAPIKeyRepository := new(mockRepositories.IAPIKeyRepository)
+	apiKeyService := getTestAPIKeyService(mockedAPIKeyRepository)
+	request := getMockAPIKeyRequest()
+	request.Scopes = []string{models.ScopeAPIKeyAdmin}
+	_, err := apiKeyService.Mint(context.Background(), "42", request)
+	assert.NotNil(t, err, "There should be an error")
+	mockedAPIKeyRepository.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestMintAPIKeyWithoutNameReturnError(t *testing.T) {
+	mockedAPIKeyRepository := new(mockRepositories.IAPIKeyRepository)
+	apiKeyService := getTestAPIKeyService(mockedAPIKeyRepository)
+	request := getMockAPIKeyRequest()
+	request.Name = ""
+	_, err := apiKeyService.Mint(context.Background(), "42", request)
+	assert.NotNil(t, err, "There should be an error")
+}
+
+func TestMintAPIKeyRepositoryFailedReturnError(t *testing.T) {
+	mockedAPIKeyRepository := new(mockRepositories.IAPIKeyRepository)
+	mockedAPIKeyRepository.On("Create", mock.Anything, mock.Anything).Return(models.APIKey{}, fmt.Errorf("Insertion error"))
+	apiKeyService := getTestAPIKeyService(mockedAPIKeyRepository)
+	_, err := apiKeyService.Mint(context.Background(), "42", getMockAPIKeyRequest())
+	assert.NotNil(t, err, "There should be an error")
+}
+
+func TestAuthenticateAPIKeySuccessTouchLastUsed(t *testing.T) {
+	apiKey, plaintext := mintTestAPIKey(t)
+	apiKey.ID = 7
+	mockedAPIKeyRepository := new(mockRepositories.IAPIKeyRepository)
+	mockedAPIKeyRepository.On("GetByPrefix", mock.Anything, apiKey.Prefix).Return(apiKey, nil)
+	mockedAPIKeyRepository.On("TouchLastUsed", mock.Anything, uint(7), apiKeyTestNow).Return(nil)
+	apiKeyService := getTestAPIKeyService(mockedAPIKeyRepository)
+	response, err := apiKeyService.Authenticate(context.Background(), plaintext)
+	assert.Nil(t, err, "There should be no error")
+	assert.Equal(t, uint(7), response.ID)
+	mockedAPIKeyRepository.AssertExpectations(t)
+}
+
+func TestAuthenticateRecentlyUsedAPIKeySkipTouch(t *testing.T) {
+	apiKey, plaintext := mintTestAPIKey(t)
+	lastUsed := apiKeyTestNow.Add(-10 * time.Second)
+	apiKey.LastUsedAt = &lastUsed
+	mockedAPIKeyRepository := new(mockRepositories.IAPIKeyRepository)
+	mockedAPIKeyRepository.On("GetByPrefix", mock.Anything, apiKey.Prefix).Return(apiKey, nil)
+	apiKeyService := getTestAPIKeyService(mockedAPIKeyRepository)
+	_, err := apiKeyService.Authenticate(context.Background(), plaintext)
+	assert.Nil(t, err, "There should be no error")
+	mockedAPIKeyRepository.AssertNotCalled(t, "TouchLastUsed", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestAuthenticateWrongSecretReturnError(t *testing.T) {
+	apiKey, plaintext := mintTestAPIKey(t)
+	mockedAPIKeyRepository := new(mockRepositories.IAPIKeyRepository)
+	mockedAPIKeyRepository.On("GetByPrefix", mock.Anything, apiKey.Prefix).Return(apiKey, nil)
+	apiKeyService := getTestAPIKeyService(mockedAPIKeyRepository)
+	_, err := apiKeyService.Authenticate(context.Background(), plaintext + "x")
+	assert.NotNil(t, err, "There should be an error")
+}
+
+func TestAuthenticateAbandonedLookupReturnContextError(t *testing.T) {
+	mockedAPIKeyRepository := new(mockRepositories.IAPIKeyRepository)
+	mockedAPIKeyRepository.On("GetByPrefix", mock.Anything, "abc").Return(models.APIKey{}, fmt.Errorf("canceling statement due to user request"))
+	apiKeyService := getTestAPIKeyService(mockedAPIKeyRepository)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := apiKeyService.Authenticate(ctx, "ntk_abc_secret")
+	assert.Equal(t, context.Canceled, err)
+}
+
+func TestAuthenticateRevokedAPIKeyReturnError(t *testing.T) {
+	apiKey, plaintext := mintTestAPIKey(t)
+	revokedAt := apiKeyTestNow.Add(-time.Hour)
+	apiKey.RevokedAt = &revokedAt
+	mockedAPIKeyRepository := new(mockRepositories.IAPIKeyRepository)
+	mockedAPIKeyRepository.On("GetByPrefix", mock.Anything, apiKey.Prefix).Return(apiKey, nil)
+	apiKeyService := getTestAPIKeyService(mockedAPIKeyRepository)
+	_, err := apiKeyService.Authenticate(context.Background(), plaintext)
+	assert.NotNil(t, err, "There should be an error")
+}
+
+func TestAuthenticateExpiredAPIKeyReturnError(t *testing.T) {
+	apiKey, plaintext := mintTestAPIKey(t)
+	expiresAt := apiKeyTestNow.Add(-time.Minute)
+	apiKey.ExpiresAt = &expiresAt
+	mockedAPIKeyRepository := new(mockRepositories.IAPIKeyRepository)
+	mockedAPIKeyRepository.On("GetByPrefix", mock.Anything, apiKey.Prefix).Return(apiKey, nil)
+	apiKeyService := getTestAPIKeyService(mockedAPIKeyRepository)
+	_, err := apiKeyService.Authenticate(context.Background(), plaintext)
+	assert.NotNil(t, err, "There should be an error")
+}
+
+func TestAuthenticateMalformedAPIKeyReturnError(t *testing.T) {
+	mockedAPIKeyRepository := new(mockRepositories.IAPIKeyRepository)
+	apiKeyService := getTestAPIKeyService(mockedAPIKeyRepository)
+	_, err := apiKeyService.Authenticate(context.Background(), "not-an-api-key")
+	assert.NotNil(t, err, "There should be an error")
+}
+
+func TestRotateAPIKeyReturnNewPlaintext(t *testing.T) {
+	apiKey, plaintext := mintTestAPIKey(t)
+	mockedAPIKeyRepository := new(mockRepositories.IAPIKeyRepository)
+	mockedAPIKeyRepository.On("GetByID", mock.Anything, uint(1)).Return(apiKey, nil)
+	mockedAPIKeyRepository.On("UpdateSecret", mock.Anything, uint(1), mock.Anything, mock.Anything).Return(apiKey, nil)
+	apiKeyService := getTestAPIKeyService(mockedAPIKeyRepository)
+	response, err := apiKeyService.Rotate(context.Background(), uint(1))
+	assert.Nil(t, err, "There should be no error")
+	assert.NotEqual(t, plaintext, response.Key, "Rotation should issue a new secret")
+}
+
+func TestRotateRevokedAPIKeyReturnError(t *testing.T) {
+	apiKey, _ := mintTestAPIKey(t)
+	revokedAt := apiKeyTestNow
+	apiKey.RevokedAt = &revokedAt
+	mockedAPIKeyRepository := new(mockRepositories.IAPIKeyRepository)
+	mockedAPIKeyRepository.On("GetByID", mock.Anything, uint(1)).Return(apiKey, nil)
+	apiKeyService := getTestAPIKeyService(mockedAPIKeyRepository)
+	_, err := apiKeyService.Rotate(context.Background(), uint(1))
+	assert.NotNil(t, err, "There should be an error")
+}
+
+func TestRevokeAPIKeySuccessReturnNoError(t *testing.T) {
+	mockedAPIKeyRepository := new(mockRepositories.IAPIKeyRepository)
+	mockedAPIKeyRepository.On("Revoke", mock.Anything, uint(1), apiKeyTestNow).Return(nil)
+	apiKeyService := getTestAPIKeyService(mockedAPIKeyRepository)
+	err := apiKeyService.Revoke(context.Background(), uint(1))
+	assert.Nil(t, err, "There should be no error")
+}
@@ -0,0 +1,34 @@
+package services
+
+import (
+	"context"
+	"news-topic-api/models"
+	"news-topic-api/repositories"
+)
+
+
+//IAuditService interface for audit service
+type IAuditService interface {
+	List(ctx context.Context, queryParams map[string]string) (models.AuditLogsList, error)
+}
+
+//AuditService ...
+type AuditService struct {
+	auditRepository repositories.IAuditRepository
+}
+
+//InitAuditService initialize an audit service instance with specific audit repository
+func InitAuditService(auditRepository repositories.IAuditRepository) IAuditService {
+	auditService := new(AuditService)
+	auditService.auditRepository = auditRepository
+	return auditService
+}
+
+//List list audit entries that are matched with provided filters
+func (a AuditService) List(ctx context.Context, queryParams map[string]string) (models.AuditLogsList, error) {
+	response, err := a.auditRepository.List(ctx, queryParams)
+	if err != nil {
+		return models.AuditLogsList{}, domainError(err, "audit entry", 0)
+	}
+	return models.AuditLogsList{Data: response}, nil
+}
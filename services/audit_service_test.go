@@ -0,0 +1,56 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	mockRepositories "news-topic-api/mocks/repositories"
+	"news-topic-api/models"
+	"testing"
+)
+
+func getMockAuditMeta() models.AuditMeta {
+	return models.AuditMeta{Actor: "42", RequestID: "req-1", IP: "10.0.0.1"}
+}
+
+func TestDeleteNewsPassAuditEntryToRepository(t *testing.T) {
+	mockedNewsRepository := new(mockRepositories.INewsRepository)
+	mockedNewsRepository.On("Delete", mock.Anything, uint(1), mock.MatchedBy(func(audit models.AuditLog) bool {
+		return audit.Actor == "42" && audit.Action == models.AuditActionDelete &&
+			audit.EntityType == models.AuditEntityNews && audit.RequestID == "req-1" && audit.IP == "10.0.0.1"
+	})).Return(nil)
+	newsService := InitNewsService(mockedNewsRepository, getMockTransactionManager(mockedNewsRepository))
+	err := newsService.Delete(context.Background(), uint(1), getMockAuditMeta())
+	assert.Nil(t, err, "There should be no error")
+	mockedNewsRepository.AssertExpectations(t)
+}
+
+func TestUpdateTagPassAuditEntryToRepository(t *testing.T) {
+	mockedTagRepository := new(mockRepositories.ITagRepository)
+	mockedTagRepository.On("Update", mock.Anything, uint(1), getMockTag(), mock.MatchedBy(func(audit models.AuditLog) bool {
+		return audit.Action == models.AuditActionUpdate && audit.EntityType == models.AuditEntityTag
+	})).Return(getMockTag(), nil)
+	tagService := InitTagService(mockedTagRepository, NoNewsCache{})
+	_, err := tagService.Update(context.Background(), uint(1), getMockTag(), getMockAuditMeta())
+	assert.Nil(t, err, "There should be no error")
+	mockedTagRepository.AssertExpectations(t)
+}
+
+func TestListAuditSuccessReturnEntities(t *testing.T) {
+	mockedAuditRepository := new(mockRepositories.IAuditRepository)
+	searchParams := map[string]string{"entity_type": "news", "entity_id": "1"}
+	mockedAuditRepository.On("List", mock.Anything, searchParams).Return([]models.AuditLog{{Action: models.AuditActionDelete}}, nil)
+	auditService := InitAuditService(mockedAuditRepository)
+	response, err := auditService.List(context.Background(), searchParams)
+	assert.Nil(t, err, "There should be no error")
+	assert.Equal(t, 1, len(response.Data))
+}
+
+func TestListAuditFailedReturnError(t *testing.T) {
+	mockedAuditRepository := new(mockRepositories.IAuditRepository)
+	mockedAuditRepository.On("List", mock.Anything, map[string]string{}).Return([]models.AuditLog{}, fmt.Errorf("Records not available"))
+	auditService := InitAuditService(mockedAuditRepository)
+	_, err := auditService.List(context.Background(), map[string]string{})
+	assert.NotNil(t, err, "There should be an error")
+}
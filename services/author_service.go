@@ -0,0 +1,76 @@
+package services
+
+import (
+	"context"
+	"news-topic-api/models"
+	"news-topic-api/repositories"
+)
+
+
+//IAuthorService interface for author service
+type IAuthorService interface {
+	Create(ctx context.Context, author models.Author) (models.Author, error)
+	Update(ctx context.Context, authorID uint,  author models.Author) (models.Author, error)
+	Delete(ctx context.Context, authorID uint) (error)
+	List(ctx context.Context) (models.AuthorsList, error)
+	GetDetail(ctx context.Context, authorID uint) (models.Author, error)
+}
+
+//AuthorService ...
+type AuthorService struct {
+	authorRepository repositories.IAuthorRepository
+	newsCache        INewsCacheInvalidator
+}
+
+//InitAuthorService initialize an author service instance with specific author repository, newsCache drops
+//the cached news once an author they embed changed
+func InitAuthorService(authorRepository repositories.IAuthorRepository, newsCache INewsCacheInvalidator) IAuthorService {
+	authorService := new(AuthorService)
+	authorService.authorRepository = authorRepository
+	authorService.newsCache = newsCache
+	return authorService
+}
+
+//Create ...
+func (a AuthorService) Create(ctx context.Context, author models.Author) (models.Author, error) {
+	instance, err := a.authorRepository.Create(ctx, author)
+	if err != nil {
+		return models.Author{}, domainError(err, "author", 0)
+	}
+	return instance, nil
+}
+
+//Update ...
+func (a AuthorService) Update(ctx context.Context, authorID uint,  author models.Author) (models.Author, error) {
+	instance, err := a.authorRepository.Update(ctx, authorID, author)
+	a.newsCache.InvalidateAll(ctx)
+	if err != nil {
+		return models.Author{}, domainError(err, "author", authorID)
+	}
+	return instance, nil
+}
+
+//Delete ...
+func (a AuthorService) Delete(ctx context.Context, authorID uint) (error) {
+	err := a.authorRepository.Delete(ctx, authorID)
+	a.newsCache.InvalidateAll(ctx)
+	return domainError(err, "author", authorID)
+}
+
+//List ...
+func (a AuthorService) List(ctx context.Context) (models.AuthorsList, error) {
+	response, err := a.authorRepository.List(ctx)
+	if err != nil {
+		return models.AuthorsList{}, domainError(err, "author", 0)
+	}
+	return models.AuthorsList{Data: response}, nil
+}
+
+//GetDetail ...
+func (a AuthorService) GetDetail(ctx context.Context, authorID uint) (models.Author, error) {
+	response, err := a.authorRepository.GetByID(ctx, authorID)
+	if err != nil {
+		return models.Author{}, domainError(err, "author", authorID)
+	}
+	return response, nil
+}
@@ -0,0 +1,123 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	mockRepositories "news-topic-api/mocks/repositories"
+	mockServices "news-topic-api/mocks/services"
+	"news-topic-api/models"
+	"reflect"
+	"testing"
+)
+
+func getMockAuthor() models.Author {
+	entity := models.Author{
+		Name: "Budi Santoso",
+		Bio: "Jurnalis ekonomi dan teknologi",
+		Avatar: "google.com/avatar.png",
+	}
+	return entity
+}
+
+func getMockAuthorList() []models.Author {
+	authorList := []models.Author{getMockAuthor()}
+	return authorList
+}
+
+func TestCreateAuthorSuccessReturnCreatedEntity(t *testing.T) {
+	mockedAuthorRepository := new(mockRepositories.IAuthorRepository)
+	mockAuthorEntity := getMockAuthor()
+	mockedAuthorRepository.On("Create", mock.Anything, mockAuthorEntity).Return(mockAuthorEntity, nil)
+	authorService := InitAuthorService(mockedAuthorRepository, NoNewsCache{})
+	response, err  := authorService.Create(context.Background(), mockAuthorEntity)
+	assert.Nil(t, err, "There should be no error")
+	assert.True(t, reflect.DeepEqual(mockAuthorEntity, response) , "Response should be same as input")
+}
+
+func TestCreateAuthorFailedReturnError(t *testing.T) {
+	mockedAuthorRepository := new(mockRepositories.IAuthorRepository)
+	mockAuthorEntity := getMockAuthor()
+	mockedAuthorRepository.On("Create", mock.Anything, mockAuthorEntity).Return(models.Author{}, fmt.Errorf("Author creation failed"))
+	authorService := InitAuthorService(mockedAuthorRepository, NoNewsCache{})
+	_, err  := authorService.Create(context.Background(), mockAuthorEntity)
+	assert.NotNil(t, err, "There should be an error")
+}
+
+func TestUpdateAuthorSuccessReturnUpdatedEntity(t *testing.T) {
+	newsCache := new(mockServices.INewsCacheInvalidator)
+	newsCache.On("InvalidateAll", mock.Anything).Return()
+	mockedAuthorRepository := new(mockRepositories.IAuthorRepository)
+	mockAuthorEntity := getMockAuthor()
+	mockedAuthorRepository.On("Update", mock.Anything, uint(1), mockAuthorEntity).Return(mockAuthorEntity, nil)
+	authorService := InitAuthorService(mockedAuthorRepository, newsCache)
+	response, err  := authorService.Update(context.Background(), uint(1), mockAuthorEntity)
+	assert.Nil(t, err, "There should be no error")
+	assert.True(t, reflect.DeepEqual(mockAuthorEntity, response) , "Response should be same as input")
+	newsCache.AssertNumberOfCalls(t, "InvalidateAll", 1)
+}
+
+func TestUpdateAuthorFailedReturnError(t *testing.T) {
+	mockedAuthorRepository := new(mockRepositories.IAuthorRepository)
+	mockAuthorEntity := getMockAuthor()
+	mockedAuthorRepository.On("Update", mock.Anything, uint(1), mockAuthorEntity).Return(models.Author{}, fmt.Errorf("Author with specified id not found"))
+	authorService := InitAuthorService(mockedAuthorRepository, NoNewsCache{})
+	_, err  := authorService.Update(context.Background(), uint(1), mockAuthorEntity)
+	assert.NotNil(t, err, "There should be an error")
+}
+
+func TestDeleteAuthorSuccessReturnNoError(t *testing.T) {
+	newsCache := new(mockServices.INewsCacheInvalidator)
+	newsCache.On("InvalidateAll", mock.Anything).Return()
+	mockedAuthorRepository := new(mockRepositories.IAuthorRepository)
+	mockedAuthorRepository.On("Delete", mock.Anything, uint(1)).Return(nil)
+	authorService := InitAuthorService(mockedAuthorRepository, newsCache)
+	err  := authorService.Delete(context.Background(), uint(1))
+	assert.Nil(t, err, "There should be no error")
+	newsCache.AssertNumberOfCalls(t, "InvalidateAll", 1)
+}
+
+func TestDeleteAuthorFailedReturnError(t *testing.T) {
+	mockedAuthorRepository := new(mockRepositories.IAuthorRepository)
+	mockedAuthorRepository.On("Delete", mock.Anything, uint(1)).Return(fmt.Errorf("Author with specified id not found"))
+	authorService := InitAuthorService(mockedAuthorRepository, NoNewsCache{})
+	err  := authorService.Delete(context.Background(), uint(1))
+	assert.NotNil(t, err, "There should be an error")
+}
+
+func TestListAuthorSuccessReturnEntities(t *testing.T) {
+	mockedAuthorRepository := new(mockRepositories.IAuthorRepository)
+	mockAuthorEntities := getMockAuthorList()
+	authorService := InitAuthorService(mockedAuthorRepository, NoNewsCache{})
+	mockedAuthorRepository.On("List", mock.Anything).Return(mockAuthorEntities, nil)
+	response, err  := authorService.List(context.Background())
+	assert.Nil(t, err, "There should be no error")
+	assert.True(t, reflect.DeepEqual(mockAuthorEntities, response.Data), "List should be the same")
+}
+
+func TestListAuthorFailedReturnError(t *testing.T) {
+	mockedAuthorRepository := new(mockRepositories.IAuthorRepository)
+	authorService := InitAuthorService(mockedAuthorRepository, NoNewsCache{})
+	mockedAuthorRepository.On("List", mock.Anything).Return([]models.Author{}, fmt.Errorf("Records not available"))
+	_, err  := authorService.List(context.Background())
+	assert.NotNil(t, err, "There should be an error")
+}
+
+func TestGetDetailAuthorSuccessReturnEntity(t *testing.T) {
+	mockedAuthorRepository := new(mockRepositories.IAuthorRepository)
+	mockAuthorEntity := getMockAuthor()
+	mockedAuthorRepository.On("GetByID", mock.Anything, uint(1)).Return(mockAuthorEntity, nil)
+	authorService := InitAuthorService(mockedAuthorRepository, NoNewsCache{})
+	response, err  := authorService.GetDetail(context.Background(), uint(1))
+	assert.Nil(t, err, "There should be no error")
+	assert.True(t, reflect.DeepEqual(mockAuthorEntity, response) , "Response should be same as repository output")
+}
+
+func TestGetDetailAuthorFailedReturnError(t *testing.T) {
+	mockedAuthorRepository := new(mockRepositories.IAuthorRepository)
+	mockedAuthorRepository.On("GetByID", mock.Anything, uint(1)).Return(models.Author{}, fmt.Errorf("Author with specified id not found"))
+	authorService := InitAuthorService(mockedAuthorRepository, NoNewsCache{})
+	_, err  := authorService.GetDetail(context.Background(), uint(1))
+	assert.NotNil(t, err, "There should be an error")
+}
@@ -0,0 +1,229 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"news-topic-api/cache"
+	"news-topic-api/helpers"
+	"news-topic-api/models"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	newsDetailKeyPrefix = "news:detail:"
+	newsListKeyPrefix   = "news:list:"
+)
+
+//NewsCacheStats counts the reads answered from the cache and those passed to the news service
+type NewsCacheStats struct {
+	DetailHits   uint64
+	DetailMisses uint64
+	ListHits     uint64
+	ListMisses   uint64
+	Errors       uint64
+}
+
+//INewsCacheInvalidator drops the cached news, the tag and author services call it once they changed
+//what the news embed
+type INewsCacheInvalidator interface {
+	InvalidateAll(ctx context.Context)
+}
+
+//NoNewsCache is the news cache invalidator of a news service that caches nothing
+type NoNewsCache struct{}
+
+//InvalidateAll has nothing to drop
+func (NoNewsCache) InvalidateAll(ctx context.Context) {}
+
+//CachedNewsService answers news details and lists from a cache in front of another news service.
+//Writes go through and drop the cached detail of the news and every cached list
+type CachedNewsService struct {
+	// counters first, atomic access needs them 64-bit aligned
+	stats NewsCacheStats
+	//generation counts the invalidations, a read started before one does not fill the cache
+	generation uint64
+
+	next         INewsService
+	backend      cache.IBackend
+	ttl          time.Duration
+	invalidation sync.RWMutex
+}
+
+//InitCachedNewsService initializes a news service caching the reads of next in backend for ttl
+func InitCachedNewsService(next INewsService, backend cache.IBackend, ttl time.Duration) *CachedNewsService {
+	newsService := new(CachedNewsService)
+	newsService.next = next
+	newsService.backend = backend
+	newsService.ttl = ttl
+	return newsService
+}
+
+//Create creates the news and drops the cached lists
+func (c *CachedNewsService) Create(ctx context.Context, news models.News, meta models.AuditMeta) (models.News, error) {
+	created, err := c.next.Create(ctx, news, meta)
+	c.invalidate(ctx)
+	return created, err
+}
+
+//Update updates the news and drops its cached detail and the cached lists
+func (c *CachedNewsService) Update(ctx context.Context, newsID uint, news models.News, meta models.AuditMeta) (models.News, error) {
+	updated, err := c.next.Update(ctx, newsID, news, meta)
+	c.invalidate(ctx, newsDetailKey(newsID))
+	return updated, err
+}
+
+//Delete deletes the news and drops its cached detail and the cached lists
+func (c *CachedNewsService) Delete(ctx context.Context, newsID uint, meta models.AuditMeta) error {
+	err := c.next.Delete(ctx, newsID, meta)
+	c.invalidate(ctx, newsDetailKey(newsID))
+	return err
+}
+
+//Restore restores the news and drops its cached detail and the cached lists
+func (c *CachedNewsService) Restore(ctx context.Context, newsID uint, meta models.AuditMeta) (models.News, error) {
+	restored, err := c.next.Restore(ctx, newsID, meta)
+	c.invalidate(ctx, newsDetailKey(newsID))
+	return restored, err
+}
+
+//List answers the news matching the filters from the cache, or from the news service when they are not cached
+func (c *CachedNewsService) List(ctx context.Context, queryParams map[string]string) (models.NewsList, error) {
+	var newsList models.NewsList
+	key := newsListKey(queryParams)
+	if c.lookup(ctx, key, &newsList) {
+		atomic.AddUint64(&c.stats.ListHits, 1)
+		return newsList, nil
+	}
+	atomic.AddUint64(&c.stats.ListMisses, 1)
+	generation := c.currentGeneration()
+	newsList, err := c.next.List(ctx, queryParams)
+	if err != nil {
+		return models.NewsList{}, err
+	}
+	c.fill(ctx, generation, key, newsList)
+	return newsList, nil
+}
+
+//GetDetail answers the news from the cache, or from the news service when it is not cached
+func (c *CachedNewsService) GetDetail(ctx context.Context, newsID uint) (models.News, error) {
+	var news models.News
+	key := newsDetailKey(newsID)
+	if c.lookup(ctx, key, &news) {
+		atomic.AddUint64(&c.stats.DetailHits, 1)
+		return news, nil
+	}
+	atomic.AddUint64(&c.stats.DetailMisses, 1)
+	generation := c.currentGeneration()
+	news, err := c.next.GetDetail(ctx, newsID)
+	if err != nil {
+		return models.News{}, err
+	}
+	c.fill(ctx, generation, key, news)
+	return news, nil
+}
+
+//CountByStatus is not cached, it is only read by metric scrapes
+func (c *CachedNewsService) CountByStatus(ctx context.Context) (map[string]int64, error) {
+	return c.next.CountByStatus(ctx)
+}
+
+//Stats returns the hits and misses counted since the service was initialized
+func (c *CachedNewsService) Stats() NewsCacheStats {
+	return NewsCacheStats{
+		DetailHits:   atomic.LoadUint64(&c.stats.DetailHits),
+		DetailMisses: atomic.LoadUint64(&c.stats.DetailMisses),
+		ListHits:     atomic.LoadUint64(&c.stats.ListHits),
+		ListMisses:   atomic.LoadUint64(&c.stats.ListMisses),
+		Errors:       atomic.LoadUint64(&c.stats.Errors),
+	}
+}
+
+//lookup decodes the cached value of key into target, a backend failure counts as a miss
+func (c *CachedNewsService) lookup(ctx context.Context, key string, target interface{}) bool {
+	value, found, err := c.backend.Get(ctx, key)
+	if err == nil && found {
+		err = json.Unmarshal(value, target)
+		if err == nil {
+			return true
+		}
+	}
+	if err != nil {
+		c.backendFailed(ctx, "unable to read the news cache", err, key)
+	}
+	return false
+}
+
+//fill caches value under key unless a write invalidated the cache since generation was read
+func (c *CachedNewsService) fill(ctx context.Context, generation uint64, key string, value interface{}) {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		c.backendFailed(ctx, "unable to encode a news cache entry", err, key)
+		return
+	}
+	c.invalidation.RLock()
+	defer c.invalidation.RUnlock()
+	if atomic.LoadUint64(&c.generation) != generation {
+		return
+	}
+	if err := c.backend.Set(ctx, key, encoded, c.ttl); err != nil {
+		c.backendFailed(ctx, "unable to write the news cache", err, key)
+	}
+}
+
+//InvalidateAll drops every cached detail and list, the tags or authors embedded in any news may have changed
+func (c *CachedNewsService) InvalidateAll(ctx context.Context) {
+	c.invalidation.Lock()
+	defer c.invalidation.Unlock()
+	atomic.AddUint64(&c.generation, 1)
+	c.dropPrefixes(ctx, newsDetailKeyPrefix, newsListKeyPrefix)
+}
+
+//invalidate drops the given keys and every cached list, once the write they follow is done
+func (c *CachedNewsService) invalidate(ctx context.Context, keys ...string) {
+	c.invalidation.Lock()
+	defer c.invalidation.Unlock()
+	atomic.AddUint64(&c.generation, 1)
+	if len(keys) > 0 {
+		// the write is done, the entries must go even when the caller stopped waiting for it
+		if err := c.backend.Delete(context.Background(), keys...); err != nil {
+			c.backendFailed(ctx, "unable to invalidate the news cache", err, keys[0])
+		}
+	}
+	c.dropPrefixes(ctx, newsListKeyPrefix)
+}
+
+func (c *CachedNewsService) dropPrefixes(ctx context.Context, prefixes ...string) {
+	for _, prefix := range prefixes {
+		if err := c.backend.DeletePrefix(context.Background(), prefix); err != nil {
+			c.backendFailed(ctx, "unable to invalidate the news cache", err, prefix)
+		}
+	}
+}
+
+func (c *CachedNewsService) currentGeneration() uint64 {
+	return atomic.LoadUint64(&c.generation)
+}
+
+func (c *CachedNewsService) backendFailed(ctx context.Context, msg string, err error, key string) {
+	atomic.AddUint64(&c.stats.Errors, 1)
+	helpers.GetLogger(ctx).Error(msg, err, helpers.LogFields{"cache_key": key})
+}
+
+func newsDetailKey(newsID uint) string {
+	return fmt.Sprintf("%s%d", newsDetailKeyPrefix, newsID)
+}
+
+//newsListKey normalizes the filters so that their order and the empty ones do not matter
+func newsListKey(queryParams map[string]string) string {
+	values := url.Values{}
+	for name, value := range queryParams {
+		if value != "" {
+			values.Set(name, value)
+		}
+	}
+	return newsListKeyPrefix + values.Encode()
+}
@@ -0,0 +1,151 @@
+package services
+
+import (
+	"context"
+	"news-topic-api/cache"
+	"news-topic-api/models"
+	"news-topic-api/repositories"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+	mockServices "news-topic-api/mocks/services"
+)
+
+func getCachedMockNews() models.News {
+	news := getMockNews()
+	news.ID = 1
+	news.Tags = []models.Tag{{Model: gorm.Model{ID: 1}, Name: "crypto"}}
+	return news
+}
+
+func TestCachedNewsGetDetailShouldReadThrough(t *testing.T) {
+	ctx := context.Background()
+	mockedNewsService := new(mockServices.INewsService)
+	mockedNewsService.On("GetDetail", mock.Anything, uint(1)).Return(getCachedMockNews(), nil).Once()
+	newsService := InitCachedNewsService(mockedNewsService, cache.InitLRU(10), time.Minute)
+	first, err := newsService.GetDetail(ctx, 1)
+	assert.Nil(t, err)
+	second, err := newsService.GetDetail(ctx, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, first.Title, second.Title)
+	assert.Equal(t, "crypto", second.Tags[0].Name)
+	mockedNewsService.AssertNumberOfCalls(t, "GetDetail", 1)
+	assert.Equal(t, NewsCacheStats{DetailHits: 1, DetailMisses: 1}, newsService.Stats())
+}
+
+func TestCachedNewsGetDetailShouldNotCacheErrors(t *testing.T) {
+	ctx := context.Background()
+	mockedNewsService := new(mockServices.INewsService)
+	mockedNewsService.On("GetDetail", mock.Anything, uint(1)).Return(models.News{}, NotFoundError{Entity: "news", ID: 1})
+	newsService := InitCachedNewsService(mockedNewsService, cache.InitLRU(10), time.Minute)
+	_, err := newsService.GetDetail(ctx, 1)
+	assert.Equal(t, NotFoundError{Entity: "news", ID: 1}, err)
+	_, err = newsService.GetDetail(ctx, 1)
+	assert.Equal(t, NotFoundError{Entity: "news", ID: 1}, err)
+	mockedNewsService.AssertNumberOfCalls(t, "GetDetail", 2)
+}
+
+func TestCachedNewsListShouldNormalizeParams(t *testing.T) {
+	ctx := context.Background()
+	mockedNewsService := new(mockServices.INewsService)
+	mockedNewsService.On("List", mock.Anything, mock.Anything).Return(models.NewsList{Data: []models.News{getCachedMockNews()}}, nil)
+	newsService := InitCachedNewsService(mockedNewsService, cache.InitLRU(10), time.Minute)
+	_, err := newsService.List(ctx, map[string]string{"status": "published", "topic": "bitcoin"})
+	assert.Nil(t, err)
+	newsList, err := newsService.List(ctx, map[string]string{"topic": "bitcoin", "status": "published", "tag": ""})
+	assert.Nil(t, err)
+	assert.Len(t, newsList.Data, 1)
+	_, err = newsService.List(ctx, map[string]string{"status": "draft"})
+	assert.Nil(t, err)
+	mockedNewsService.AssertNumberOfCalls(t, "List", 2)
+	assert.Equal(t, NewsCacheStats{ListHits: 1, ListMisses: 2}, newsService.Stats())
+}
+
+func TestCachedNewsWritesShouldInvalidate(t *testing.T) {
+	ctx := context.Background()
+	mockedNewsService := new(mockServices.INewsService)
+	mockedNewsService.On("GetDetail", mock.Anything, mock.Anything).Return(getCachedMockNews(), nil)
+	mockedNewsService.On("List", mock.Anything, mock.Anything).Return(models.NewsList{Data: []models.News{getCachedMockNews()}}, nil)
+	mockedNewsService.On("Create", mock.Anything, mock.Anything, mock.Anything).Return(getCachedMockNews(), nil)
+	mockedNewsService.On("Update", mock.Anything, uint(1), mock.Anything, mock.Anything).Return(getCachedMockNews(), nil)
+	mockedNewsService.On("Delete", mock.Anything, uint(2), mock.Anything).Return(nil)
+	mockedNewsService.On("Restore", mock.Anything, uint(2), mock.Anything).Return(getCachedMockNews(), nil)
+	newsService := InitCachedNewsService(mockedNewsService, cache.InitLRU(10), time.Minute)
+	warm := func() {
+		newsService.GetDetail(ctx, 1)
+		newsService.GetDetail(ctx, 2)
+		newsService.List(ctx, map[string]string{"status": "published"})
+	}
+
+	warm()
+	newsService.Create(ctx, getMockNews(), models.AuditMeta{})
+	warm()
+	assert.Equal(t, NewsCacheStats{DetailHits: 2, DetailMisses: 2, ListMisses: 2}, newsService.Stats(), "create should drop the lists only")
+
+	newsService.Update(ctx, 1, getMockNews(), models.AuditMeta{})
+	warm()
+	assert.Equal(t, NewsCacheStats{DetailHits: 3, DetailMisses: 3, ListMisses: 3}, newsService.Stats(), "update should drop its news and the lists")
+
+	newsService.Delete(ctx, 2, models.AuditMeta{})
+	warm()
+	assert.Equal(t, NewsCacheStats{DetailHits: 4, DetailMisses: 4, ListMisses: 4}, newsService.Stats(), "delete should drop its news and the lists")
+
+	newsService.Restore(ctx, 2, models.AuditMeta{})
+	warm()
+	assert.Equal(t, NewsCacheStats{DetailHits: 5, DetailMisses: 5, ListMisses: 5}, newsService.Stats(), "restore should drop its news and the lists")
+}
+
+func TestCachedNewsReadOverlappingWriteShouldNotFill(t *testing.T) {
+	ctx := context.Background()
+	mockedNewsService := new(mockServices.INewsService)
+	newsService := InitCachedNewsService(mockedNewsService, cache.InitLRU(10), time.Minute)
+	mockedNewsService.On("Update", mock.Anything, uint(1), mock.Anything, mock.Anything).Return(getCachedMockNews(), nil)
+	// the news is updated while the stale detail is being read
+	mockedNewsService.On("GetDetail", mock.Anything, uint(1)).Return(getCachedMockNews(), nil).Run(func(args mock.Arguments) {
+		newsService.Update(ctx, 1, getMockNews(), models.AuditMeta{})
+	}).Once()
+	_, err := newsService.GetDetail(ctx, 1)
+	assert.Nil(t, err)
+	mockedNewsService.On("GetDetail", mock.Anything, uint(1)).Return(getCachedMockNews(), nil).Once()
+	_, err = newsService.GetDetail(ctx, 1)
+	assert.Nil(t, err)
+	mockedNewsService.AssertNumberOfCalls(t, "GetDetail", 2)
+}
+
+func TestCachedNewsCountByStatusShouldNotBeCached(t *testing.T) {
+	ctx := context.Background()
+	mockedNewsService := new(mockServices.INewsService)
+	mockedNewsService.On("CountByStatus", mock.Anything).Return(map[string]int64{"draft": 1}, nil)
+	newsService := InitCachedNewsService(mockedNewsService, cache.InitLRU(10), time.Minute)
+	newsService.CountByStatus(ctx)
+	newsService.CountByStatus(ctx)
+	mockedNewsService.AssertNumberOfCalls(t, "CountByStatus", 2)
+}
+
+func TestCachedNewsShouldShowRenamedTagAtOnce(t *testing.T) {
+	ctx := context.Background()
+	store := repositories.InitMemoryStore()
+	newsService := InitCachedNewsService(InitNewsService(repositories.InitMemoryNewsRepository(store), repositories.InitMemoryTransactionManager(store)), cache.InitLRU(10), time.Minute)
+	tagService := InitTagService(repositories.InitMemoryTagRepository(store), newsService)
+	tag, err := tagService.Create(ctx, models.Tag{Name: "crypto"}, models.AuditMeta{})
+	assert.Nil(t, err)
+	news := getMockNews()
+	news.Tags = []models.Tag{{Model: gorm.Model{ID: tag.ID}}}
+	created, err := newsService.Create(ctx, news, models.AuditMeta{})
+	assert.Nil(t, err)
+	newsService.GetDetail(ctx, created.ID)
+	newsService.List(ctx, map[string]string{})
+
+	_, err = tagService.Update(ctx, tag.ID, models.Tag{Name: "blockchain"}, models.AuditMeta{})
+	assert.Nil(t, err)
+	detail, err := newsService.GetDetail(ctx, created.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, "blockchain", detail.Tags[0].Name, "the cached detail should show the renamed tag")
+	list, err := newsService.List(ctx, map[string]string{})
+	assert.Nil(t, err)
+	assert.Equal(t, "blockchain", list.Data[0].Tags[0].Name, "the cached list should show the renamed tag")
+	assert.Equal(t, NewsCacheStats{DetailMisses: 2, ListMisses: 2}, newsService.Stats())
+}
@@ -0,0 +1,88 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"news-topic-api/repositories"
+)
+
+//NotFoundError is returned when the entity an operation targets does not exist
+type NotFoundError struct {
+	Entity string
+	ID     uint
+}
+
+func (n NotFoundError) Error() string {
+	if n.ID == 0 {
+		return fmt.Sprintf("%s not found", n.Entity)
+	}
+	return fmt.Sprintf("%s %d not found", n.Entity, n.ID)
+}
+
+//ConflictError is returned when an operation contradicts the stored state, such as a duplicate tag name
+type ConflictError struct {
+	Reason string
+}
+
+func (c ConflictError) Error() string {
+	return c.Reason
+}
+
+//InvalidInputError is returned when the caller sent values the operation can not accept,
+//fields lists the inputs at fault when they are known
+type InvalidInputError struct {
+	Reason string
+	Fields []FieldError
+}
+
+//FieldError describes why the value of one input was rejected, field is its json or parameter name
+type FieldError struct {
+	Field  string
+	Reason string
+}
+
+func (i InvalidInputError) Error() string {
+	return i.Reason
+}
+
+//UnavailableError is returned when a dependency such as the database can not be reached,
+//the cause is kept for the logs and never shown to callers
+type UnavailableError struct {
+	Cause error
+}
+
+func (u UnavailableError) Error() string {
+	return "the service is temporarily unavailable"
+}
+
+func (u UnavailableError) Unwrap() error {
+	return u.Cause
+}
+
+func invalidInput(format string, args ...interface{}) error {
+	return InvalidInputError{Reason: fmt.Sprintf(format, args...)}
+}
+
+func invalidField(field string, format string, args ...interface{}) error {
+	reason := fmt.Sprintf(format, args...)
+	return InvalidInputError{Reason: reason, Fields: []FieldError{{Field: field, Reason: reason}}}
+}
+
+//domainError translates the repository errors of an operation on an entity into domain errors,
+//other errors, including domain errors and context errors, are returned unchanged
+func domainError(err error, entity string, id uint) error {
+	var invalidFilter repositories.InvalidFilterError
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, repositories.ErrNotFound):
+		return NotFoundError{Entity: entity, ID: id}
+	case errors.Is(err, repositories.ErrDuplicate):
+		return ConflictError{Reason: fmt.Sprintf("%s already exists", entity)}
+	case errors.Is(err, repositories.ErrUnavailable):
+		return UnavailableError{Cause: err}
+	case errors.As(err, &invalidFilter):
+		return invalidField(invalidFilter.Field, "%s", invalidFilter.Reason)
+	}
+	return err
+}
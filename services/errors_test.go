@@ -0,0 +1,33 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"news-topic-api/repositories"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDomainErrorTranslatesRepositoryErrors(t *testing.T) {
+	assert.Equal(t, NotFoundError{Entity: "news", ID: 3}, domainError(repositories.ErrNotFound, "news", 3))
+	assert.Equal(t, ConflictError{Reason: "author already exists"}, domainError(repositories.ErrDuplicate, "author", 0))
+	assert.Equal(t, InvalidInputError{
+		Reason: "invalid format for tag",
+		Fields: []FieldError{{Field: "tag", Reason: "invalid format for tag"}},
+	}, domainError(repositories.InvalidFilterError{Field: "tag", Reason: "invalid format for tag"}, "news", 0))
+}
+
+func TestDomainErrorHidesUnavailableCause(t *testing.T) {
+	cause := fmt.Errorf("%w: dial tcp 10.0.0.5:5432: connection refused", repositories.ErrUnavailable)
+	err := domainError(cause, "news", 0)
+	assert.EqualError(t, err, "the service is temporarily unavailable")
+	assert.True(t, errors.Is(err, repositories.ErrUnavailable), "the cause should be kept for the logs")
+}
+
+func TestDomainErrorKeepsOtherErrors(t *testing.T) {
+	assert.Equal(t, context.Canceled, domainError(context.Canceled, "news", 1))
+	assert.Equal(t, InvalidInputError{Reason: "tag 2 does not exist"}, domainError(InvalidInputError{Reason: "tag 2 does not exist"}, "news", 1))
+	assert.Nil(t, domainError(nil, "news", 1))
+}
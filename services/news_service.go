@@ -1,6 +1,7 @@
 package services
 
 import (
+	"context"
 	"news-topic-api/models"
 	"news-topic-api/repositories"
 )
@@ -8,63 +9,166 @@ import (
 
 //INewsService interface for news service
 type INewsService interface {
-	Create(news models.News) (models.News, error)
-	Update(newsID uint,  news models.News) (models.News, error)
-	Delete(newsID uint) (error)
-	List(queryParams map[string]string) (models.NewsList, error)
-	GetDetail(newsID uint) (models.News, error)
+	Create(ctx context.Context, news models.News, meta models.AuditMeta) (models.News, error)
+	Update(ctx context.Context, newsID uint,  news models.News, meta models.AuditMeta) (models.News, error)
+	Delete(ctx context.Context, newsID uint, meta models.AuditMeta) (error)
+	Restore(ctx context.Context, newsID uint, meta models.AuditMeta) (models.News, error)
+	List(ctx context.Context, queryParams map[string]string) (models.NewsList, error)
+	GetDetail(ctx context.Context, newsID uint) (models.News, error)
+	CountByStatus(ctx context.Context) (map[string]int64, error)
 }
 
 //NewsService ...
 type NewsService struct {
 	newsRepository repositories.INewsRepository
+	transactionManager repositories.ITransactionManager
 }
 
-//InitNewsService initialize a news service instance with specific news repository
-func InitNewsService(newsRepository repositories.INewsRepository) INewsService {
+//InitNewsService initialize a news service instance with specific news repository,
+//writes touching tags and authors run through the transaction manager
+func InitNewsService(newsRepository repositories.INewsRepository, transactionManager repositories.ITransactionManager) INewsService {
 	newsService := new(NewsService)
 	newsService.newsRepository = newsRepository
+	newsService.transactionManager = transactionManager
 	return newsService
 }
 
-//Create ...
-func (n NewsService) Create(news models.News) (models.News, error) {
-	instance, err := n.newsRepository.Create(news)
+//Create checks the tags and authors of the news exist and inserts it in one transaction
+func (n NewsService) Create(ctx context.Context, news models.News, meta models.AuditMeta) (models.News, error) {
+	audit := models.NewAuditLog(meta, models.AuditActionCreate, models.AuditEntityNews)
+	var instance models.News
+	err := n.transactionManager.WithinTransaction(ctx, func(uow repositories.IUnitOfWork) error {
+		if err := resolveAssociations(ctx, uow, &news); err != nil {
+			return err
+		}
+		created, err := uow.News().Create(ctx, news, audit)
+		instance = created
+		return err
+	})
 	if err != nil {
-		return models.News{}, err
+		return models.News{}, domainError(err, "news", 0)
 	}
 	return instance, nil
 }
 
-//Update ...
-func (n NewsService) Update(newsID uint,  news models.News) (models.News, error) {
-	instance, err := n.newsRepository.Update(newsID, news)
+//Update checks the tags and authors of the news exist and updates it in one transaction
+func (n NewsService) Update(ctx context.Context, newsID uint,  news models.News, meta models.AuditMeta) (models.News, error) {
+	audit := models.NewAuditLog(meta, models.AuditActionUpdate, models.AuditEntityNews)
+	var instance models.News
+	err := n.transactionManager.WithinTransaction(ctx, func(uow repositories.IUnitOfWork) error {
+		if err := resolveAssociations(ctx, uow, &news); err != nil {
+			return err
+		}
+		updated, err := uow.News().Update(ctx, newsID, news, audit)
+		instance = updated
+		return err
+	})
 	if err != nil {
-		return models.News{}, err
+		return models.News{}, domainError(err, "news", newsID)
 	}
 	return instance, nil
 }
 
 //Delete ...
-func (n NewsService) Delete(newsID uint) (error) {
-	err := n.newsRepository.Delete(newsID)
-	return err
+func (n NewsService) Delete(ctx context.Context, newsID uint, meta models.AuditMeta) (error) {
+	audit := models.NewAuditLog(meta, models.AuditActionDelete, models.AuditEntityNews)
+	err := n.newsRepository.Delete(ctx, newsID, audit)
+	return domainError(err, "news", newsID)
+}
+
+//Restore brings back deleted news
+func (n NewsService) Restore(ctx context.Context, newsID uint, meta models.AuditMeta) (models.News, error) {
+	audit := models.NewAuditLog(meta, models.AuditActionRestore, models.AuditEntityNews)
+	instance, err := n.newsRepository.Restore(ctx, newsID, audit)
+	if err != nil {
+		return models.News{}, domainError(err, "deleted news", newsID)
+	}
+	return instance, nil
 }
 
 //List list all news that are matched with provided filters
-func (n NewsService) List(queryParams map[string]string) (models.NewsList, error) {
-	response, err := n.newsRepository.List(queryParams)
+func (n NewsService) List(ctx context.Context, queryParams map[string]string) (models.NewsList, error) {
+	response, err := n.newsRepository.List(ctx, queryParams)
 	if err != nil {
-		return models.NewsList{}, err
+		return models.NewsList{}, domainError(err, "news", 0)
 	}
 	return models.NewsList{Data: response}, nil
 }
 
 //GetDetail ...
-func (n NewsService) GetDetail(newsID uint) (models.News, error) {
-	response, err := n.newsRepository.GetByID(newsID)
+func (n NewsService) GetDetail(ctx context.Context, newsID uint) (models.News, error) {
+	response, err := n.newsRepository.GetByID(ctx, newsID)
 	if err != nil {
-		return models.News{}, err
+		return models.News{}, domainError(err, "news", newsID)
 	}
 	return response, nil
-}
\ No newline at end of file
+}
+
+//CountByStatus counts the news per status, every known status is reported even when it has no news
+func (n NewsService) CountByStatus(ctx context.Context) (map[string]int64, error) {
+	counts, err := n.newsRepository.CountByStatus(ctx)
+	if err != nil {
+		return map[string]int64{}, domainError(err, "news", 0)
+	}
+	for _, status := range []string{models.StatusDraft, models.StatusPublished} {
+		if _, found := counts[status]; !found {
+			counts[status] = 0
+		}
+	}
+	return counts, nil
+}
+
+//resolveAssociations replaces the tags and authors referenced by id with the stored ones,
+//failing when one of them does not exist instead of creating it along with the news
+func resolveAssociations(ctx context.Context, uow repositories.IUnitOfWork, news *models.News) error {
+	tagIDs := make([]uint, 0, len(news.Tags))
+	for _, tag := range news.Tags {
+		tagIDs = append(tagIDs, tag.ID)
+	}
+	tags, err := uow.Tags().FindByIDs(ctx, tagIDs)
+	if err != nil {
+		return err
+	}
+	if missingID, found := firstMissingID(tagIDs, tagIDsOf(tags)); found {
+		return invalidField("tags", "tag %d does not exist", missingID)
+	}
+	authorIDs := make([]uint, 0, len(news.Authors))
+	for _, author := range news.Authors {
+		authorIDs = append(authorIDs, author.ID)
+	}
+	authors, err := uow.Authors().FindByIDs(ctx, authorIDs)
+	if err != nil {
+		return err
+	}
+	if missingID, found := firstMissingID(authorIDs, authorIDsOf(authors)); found {
+		return invalidField("authors", "author %d does not exist", missingID)
+	}
+	news.Tags = tags
+	news.Authors = authors
+	return nil
+}
+
+func tagIDsOf(tags []models.Tag) map[uint]bool {
+	ids := map[uint]bool{}
+	for _, tag := range tags {
+		ids[tag.ID] = true
+	}
+	return ids
+}
+
+func authorIDsOf(authors []models.Author) map[uint]bool {
+	ids := map[uint]bool{}
+	for _, author := range authors {
+		ids[author.ID] = true
+	}
+	return ids
+}
+
+func firstMissingID(wanted []uint, existing map[uint]bool) (uint, bool) {
+	for _, id := range wanted {
+		if !existing[id] {
+			return id, true
+		}
+	}
+	return 0, false
+}
@@ -1,11 +1,14 @@
 package services
 
 import (
+	"context"
 	"fmt"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"gorm.io/gorm"
 	mockRepositories "news-topic-api/mocks/repositories"
 	"news-topic-api/models"
+	"news-topic-api/repositories"
 	"reflect"
 	"testing"
 )
@@ -46,12 +49,38 @@ func getMockSearchParams() map[string]string {
 	}
 	return params
 }
+//getMockTransactionManager runs the transaction function with a unit of work made of the news repository
+//and of tag and author repositories knowing the tags of getMockNews
+func getMockTransactionManager(newsRepository *mockRepositories.INewsRepository) *mockRepositories.ITransactionManager {
+	tagRepository := new(mockRepositories.ITagRepository)
+	tagRepository.On("FindByIDs", mock.Anything, mock.Anything).Return(func(ctx context.Context, tagIDs []uint) []models.Tag {
+		var tags []models.Tag
+		for _, tagID := range tagIDs {
+			if tagID == 1 {
+				tags = append(tags, models.Tag{Model: gorm.Model{ID: 1}})
+			}
+		}
+		return tags
+	}, nil)
+	authorRepository := new(mockRepositories.IAuthorRepository)
+	authorRepository.On("FindByIDs", mock.Anything, mock.Anything).Return([]models.Author(nil), nil)
+	unitOfWork := new(mockRepositories.IUnitOfWork)
+	unitOfWork.On("News").Return(newsRepository)
+	unitOfWork.On("Tags").Return(tagRepository)
+	unitOfWork.On("Authors").Return(authorRepository)
+	transactionManager := new(mockRepositories.ITransactionManager)
+	transactionManager.On("WithinTransaction", mock.Anything, mock.Anything).Return(func(ctx context.Context, fn func(repositories.IUnitOfWork) error) error {
+		return fn(unitOfWork)
+	})
+	return transactionManager
+}
+
 func TestCreateNewsSuccessReturnCreatedEntity(t *testing.T) {
 	mockedNewsRepository := new(mockRepositories.INewsRepository)
 	mockNewsEntity := getMockNews()
-	mockedNewsRepository.On("Create", mockNewsEntity).Return(mockNewsEntity, nil)
-	newsService := InitNewsService(mockedNewsRepository)
-	response, err  := newsService.Create(mockNewsEntity)
+	mockedNewsRepository.On("Create", mock.Anything, mockNewsEntity, mock.Anything).Return(mockNewsEntity, nil)
+	newsService := InitNewsService(mockedNewsRepository, getMockTransactionManager(mockedNewsRepository))
+	response, err  := newsService.Create(context.Background(), mockNewsEntity, getMockAuditMeta())
 	assert.Nil(t, err, "There should be no error")
 	assert.True(t, reflect.DeepEqual(mockNewsEntity, response) , "Response should be same as input")
 }
@@ -59,18 +88,18 @@ func TestCreateNewsSuccessReturnCreatedEntity(t *testing.T) {
 func TestCreateNewsFailedReturnError(t *testing.T) {
 	mockedNewsRepository := new(mockRepositories.INewsRepository)
 	mockNewsEntity := getMockNews()
-	mockedNewsRepository.On("Create", mockNewsEntity).Return(models.News{}, fmt.Errorf("News creation failed"))
-	newsService := InitNewsService(mockedNewsRepository)
-	_, err  := newsService.Create(mockNewsEntity)
+	mockedNewsRepository.On("Create", mock.Anything, mockNewsEntity, mock.Anything).Return(models.News{}, fmt.Errorf("News creation failed"))
+	newsService := InitNewsService(mockedNewsRepository, getMockTransactionManager(mockedNewsRepository))
+	_, err  := newsService.Create(context.Background(), mockNewsEntity, getMockAuditMeta())
 	assert.NotNil(t, err, "There should be an error")
 }
 
 func TestUpdateNewsSuccessReturnUpdatedEntity(t *testing.T) {
 	mockedNewsRepository := new(mockRepositories.INewsRepository)
 	mockNewsEntity := getMockNews()
-	mockedNewsRepository.On("Update", uint(1),  mockNewsEntity).Return(mockNewsEntity,nil)
-	newsService := InitNewsService(mockedNewsRepository)
-	response, err  := newsService.Update(uint(1), mockNewsEntity)
+	mockedNewsRepository.On("Update", mock.Anything, uint(1),  mockNewsEntity, mock.Anything).Return(mockNewsEntity,nil)
+	newsService := InitNewsService(mockedNewsRepository, getMockTransactionManager(mockedNewsRepository))
+	response, err  := newsService.Update(context.Background(), uint(1), mockNewsEntity, getMockAuditMeta())
 	assert.Nil(t, err, "There should be no error")
 	assert.True(t, reflect.DeepEqual(mockNewsEntity, response) , "Response should be same as input")
 }
@@ -78,44 +107,52 @@ func TestUpdateNewsSuccessReturnUpdatedEntity(t *testing.T) {
 func TestUpdateNewsFailedReturnError(t *testing.T) {
 	mockedNewsRepository := new(mockRepositories.INewsRepository)
 	mockNewsEntity := getMockNews()
-	mockedNewsRepository.On("Update", uint(1),  mockNewsEntity).Return(models.News{}, fmt.Errorf("News with specified id not found"))
-	newsService := InitNewsService(mockedNewsRepository)
-	_, err  := newsService.Update(uint(1), mockNewsEntity)
+	mockedNewsRepository.On("Update", mock.Anything, uint(1),  mockNewsEntity, mock.Anything).Return(models.News{}, fmt.Errorf("News with specified id not found"))
+	newsService := InitNewsService(mockedNewsRepository, getMockTransactionManager(mockedNewsRepository))
+	_, err  := newsService.Update(context.Background(), uint(1), mockNewsEntity, getMockAuditMeta())
 	assert.NotNil(t, err, "There should be an error")
 }
 
 func TestDeleteNewsSuccessReturnNoError(t *testing.T) {
 	mockedNewsRepository := new(mockRepositories.INewsRepository)
-	mockedNewsRepository.On("Delete", uint(1)).Return(nil)
-	newsService := InitNewsService(mockedNewsRepository)
-	err  := newsService.Delete(uint(1))
+	mockedNewsRepository.On("Delete", mock.Anything, uint(1), mock.Anything).Return(nil)
+	newsService := InitNewsService(mockedNewsRepository, getMockTransactionManager(mockedNewsRepository))
+	err  := newsService.Delete(context.Background(), uint(1), getMockAuditMeta())
 	assert.Nil(t, err, "There should be no error")
 
 }
 
 func TestDeleteNewsFailedReturnError(t *testing.T) {
 	mockedNewsRepository := new(mockRepositories.INewsRepository)
-	mockedNewsRepository.On("Delete", uint(1)).Return(fmt.Errorf("News with specified id not found"))
-	newsService := InitNewsService(mockedNewsRepository)
-	err  := newsService.Delete(uint(1))
+	mockedNewsRepository.On("Delete", mock.Anything, uint(1), mock.Anything).Return(fmt.Errorf("News with specified id not found"))
+	newsService := InitNewsService(mockedNewsRepository, getMockTransactionManager(mockedNewsRepository))
+	err  := newsService.Delete(context.Background(), uint(1), getMockAuditMeta())
 	assert.NotNil(t, err, "There should be an error")
 }
 
+func TestRestoreNewsNotDeletedReturnNotFoundError(t *testing.T) {
+	mockedNewsRepository := new(mockRepositories.INewsRepository)
+	mockedNewsRepository.On("Restore", mock.Anything, uint(1), mock.Anything).Return(models.News{}, repositories.ErrNotFound)
+	newsService := InitNewsService(mockedNewsRepository, getMockTransactionManager(mockedNewsRepository))
+	_, err := newsService.Restore(context.Background(), uint(1), getMockAuditMeta())
+	assert.Equal(t, NotFoundError{Entity: "deleted news", ID: 1}, err)
+}
+
 func TestGetNewsDetailSuccessReturnCorrespondingEntity(t *testing.T) {
 	mockedNewsRepository := new(mockRepositories.INewsRepository)
 	mockNewsEntity := getMockNews()
-	mockedNewsRepository.On("GetByID", uint(1)).Return(mockNewsEntity, nil)
-	newsService := InitNewsService(mockedNewsRepository)
-	response, err  := newsService.GetDetail(uint(1))
+	mockedNewsRepository.On("GetByID", mock.Anything, uint(1)).Return(mockNewsEntity, nil)
+	newsService := InitNewsService(mockedNewsRepository, getMockTransactionManager(mockedNewsRepository))
+	response, err  := newsService.GetDetail(context.Background(), uint(1))
 	assert.Nil(t, err, "There should be no error")
 	assert.True(t, reflect.DeepEqual(mockNewsEntity, response) , "Response should be same as input")
 }
 
 func TestGetNewsDetailFailedReturnError(t *testing.T) {
 	mockedNewsRepository := new(mockRepositories.INewsRepository)
-	mockedNewsRepository.On("GetByID", uint(1)).Return(models.News{}, fmt.Errorf("News not found"))
-	newsService := InitNewsService(mockedNewsRepository)
-	_, err  := newsService.GetDetail(uint(1))
+	mockedNewsRepository.On("GetByID", mock.Anything, uint(1)).Return(models.News{}, fmt.Errorf("News not found"))
+	newsService := InitNewsService(mockedNewsRepository, getMockTransactionManager(mockedNewsRepository))
+	_, err  := newsService.GetDetail(context.Background(), uint(1))
 	assert.NotNil(t, err, "There should be an error")
 }
 
@@ -123,11 +160,11 @@ func TestListNewsSuccessReturnEntities(t *testing.T) {
 	mockedNewsRepository := new(mockRepositories.INewsRepository)
 	mockNewsEntities := getMockNewsList()
 	expectedOutput := getExpectedNewsListOutput()
-	newsService := InitNewsService(mockedNewsRepository)
+	newsService := InitNewsService(mockedNewsRepository, getMockTransactionManager(mockedNewsRepository))
 
 	searchParams := getMockSearchParams()
-	mockedNewsRepository.On("List",searchParams).Return(mockNewsEntities, nil)
-	response, err  := newsService.List(searchParams)
+	mockedNewsRepository.On("List", mock.Anything,searchParams).Return(mockNewsEntities, nil)
+	response, err  := newsService.List(context.Background(), searchParams)
 	assert.Nil(t, err, "There should be no error")
 	assert.Equal(t, len(expectedOutput.Data), len(response.Data), "Should return correct length")
 	assert.True(t, reflect.DeepEqual(expectedOutput.Data, response.Data), "List should be the same")
@@ -135,9 +172,86 @@ func TestListNewsSuccessReturnEntities(t *testing.T) {
 
 func TestListNewsFailedReturnError(t *testing.T) {
 	mockedNewsRepository := new(mockRepositories.INewsRepository)
-	newsService := InitNewsService(mockedNewsRepository)
+	newsService := InitNewsService(mockedNewsRepository, getMockTransactionManager(mockedNewsRepository))
 	searchParams := getMockSearchParams()
-	mockedNewsRepository.On("List",searchParams).Return([]models.News{}, fmt.Errorf("Records not available"))
-	_, err  := newsService.List(searchParams)
+	mockedNewsRepository.On("List", mock.Anything,searchParams).Return([]models.News{}, fmt.Errorf("Records not available"))
+	_, err  := newsService.List(context.Background(), searchParams)
+	assert.NotNil(t, err, "There should be an error")
+}
+
+func TestCountNewsByStatusFillMissingStatuses(t *testing.T) {
+	mockedNewsRepository := new(mockRepositories.INewsRepository)
+	mockedNewsRepository.On("CountByStatus", mock.Anything).Return(map[string]int64{"draft": 2}, nil)
+	newsService := InitNewsService(mockedNewsRepository, getMockTransactionManager(mockedNewsRepository))
+	counts, err := newsService.CountByStatus(context.Background())
+	assert.Nil(t, err, "There should be no error")
+	assert.Equal(t, map[string]int64{"draft": 2, "published": 0}, counts)
+}
+
+func TestCountNewsByStatusFailedReturnError(t *testing.T) {
+	mockedNewsRepository := new(mockRepositories.INewsRepository)
+	mockedNewsRepository.On("CountByStatus", mock.Anything).Return(map[string]int64{}, fmt.Errorf("connection refused"))
+	newsService := InitNewsService(mockedNewsRepository, getMockTransactionManager(mockedNewsRepository))
+	_, err := newsService.CountByStatus(context.Background())
+	assert.NotNil(t, err, "There should be an error")
+}
+
+func TestCreateNewsWithUnknownTagReturnError(t *testing.T) {
+	mockedNewsRepository := new(mockRepositories.INewsRepository)
+	mockNewsEntity := getMockNews()
+	mockNewsEntity.Tags = append(mockNewsEntity.Tags, models.Tag{Model: gorm.Model{ID: 7}})
+	newsService := InitNewsService(mockedNewsRepository, getMockTransactionManager(mockedNewsRepository))
+	_, err := newsService.Create(context.Background(), mockNewsEntity, getMockAuditMeta())
+	assert.EqualError(t, err, "tag 7 does not exist")
+	mockedNewsRepository.AssertNotCalled(t, "Create", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestUpdateNewsWithUnknownAuthorReturnError(t *testing.T) {
+	mockedNewsRepository := new(mockRepositories.INewsRepository)
+	mockNewsEntity := getMockNews()
+	mockNewsEntity.Authors = []models.Author{{Model: gorm.Model{ID: 3}}}
+	newsService := InitNewsService(mockedNewsRepository, getMockTransactionManager(mockedNewsRepository))
+	_, err := newsService.Update(context.Background(), uint(1), mockNewsEntity, getMockAuditMeta())
+	assert.EqualError(t, err, "author 3 does not exist")
+	mockedNewsRepository.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestUpdateNewsTransactionFailedReturnError(t *testing.T) {
+	mockedNewsRepository := new(mockRepositories.INewsRepository)
+	transactionManager := new(mockRepositories.ITransactionManager)
+	transactionManager.On("WithinTransaction", mock.Anything, mock.Anything).Return(fmt.Errorf("connection reset"))
+	newsService := InitNewsService(mockedNewsRepository, transactionManager)
+	_, err := newsService.Update(context.Background(), uint(1), getMockNews(), getMockAuditMeta())
 	assert.NotNil(t, err, "There should be an error")
 }
+
+func TestNewsServiceWithMemoryRepositoriesRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	store := repositories.InitMemoryStore()
+	newsService := InitNewsService(repositories.InitMemoryNewsRepository(store), repositories.InitMemoryTransactionManager(store))
+	tag, err := InitTagService(repositories.InitMemoryTagRepository(store), NoNewsCache{}).Create(ctx, models.Tag{Name: "crypto"}, models.AuditMeta{})
+	assert.Nil(t, err)
+	author, err := InitAuthorService(repositories.InitMemoryAuthorRepository(store), NoNewsCache{}).Create(ctx, models.Author{Name: "Budi Santoso"})
+	assert.Nil(t, err)
+
+	news := getMockNews()
+	news.Tags = []models.Tag{{Model: gorm.Model{ID: tag.ID + 1}}}
+	_, err = newsService.Create(ctx, news, models.AuditMeta{})
+	assert.Equal(t, "tag 2 does not exist", fmt.Sprint(err))
+
+	news.Tags = []models.Tag{{Model: gorm.Model{ID: tag.ID}}}
+	news.Authors = []models.Author{{Model: gorm.Model{ID: author.ID + 1}}}
+	_, err = newsService.Create(ctx, news, models.AuditMeta{})
+	assert.NotNil(t, err, "a missing author should be rejected")
+
+	news.Authors = []models.Author{{Model: gorm.Model{ID: author.ID}}}
+	created, err := newsService.Create(ctx, news, models.AuditMeta{})
+	assert.Nil(t, err)
+	detail, err := newsService.GetDetail(ctx, created.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, news.Title, detail.Title)
+	assert.Equal(t, "crypto", detail.Tags[0].Name)
+	assert.Equal(t, "Budi Santoso", detail.Authors[0].Name)
+	_, err = newsService.GetDetail(ctx, created.ID+1)
+	assert.Equal(t, NotFoundError{Entity: "news", ID: created.ID + 1}, err)
+}
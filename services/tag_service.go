@@ -1,6 +1,9 @@
 package services
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"news-topic-api/models"
 	"news-topic-api/repositories"
 )
@@ -8,53 +11,107 @@ import (
 
 //ITagService interface for tag service
 type ITagService interface {
-	Create(tag models.Tag) (models.Tag, error)
-	Update(tagID uint,  tag models.Tag) (models.Tag, error)
-	Delete(tagID uint) (error)
-	List() (models.TagsList, error)
+	Create(ctx context.Context, tag models.Tag, meta models.AuditMeta) (models.Tag, error)
+	Update(ctx context.Context, tagID uint,  tag models.Tag, meta models.AuditMeta) (models.Tag, error)
+	Delete(ctx context.Context, tagID uint, meta models.AuditMeta) (error)
+	Restore(ctx context.Context, tagID uint, meta models.AuditMeta) (models.Tag, error)
+	List(ctx context.Context) (models.TagsList, error)
+	Merge(ctx context.Context, targetID uint, sourceIDs []uint, meta models.AuditMeta) (models.Tag, error)
 }
 
 //TagService ...
 type TagService struct {
 	tagRepository repositories.ITagRepository
+	newsCache     INewsCacheInvalidator
 }
 
-//InitTagService initialize a tag service instance with specific tag repository
-func InitTagService(tagRepository repositories.ITagRepository) ITagService {
+//InitTagService initialize a tag service instance with specific tag repository, newsCache drops the cached
+//news once a tag they embed changed
+func InitTagService(tagRepository repositories.ITagRepository, newsCache INewsCacheInvalidator) ITagService {
 	tagService := new(TagService)
 	tagService.tagRepository = tagRepository
+	tagService.newsCache = newsCache
 	return tagService
 }
 
 //Create ...
-func (t TagService) Create(tag models.Tag) (models.Tag, error) {
-	instance, err := t.tagRepository.Create(tag)
+func (t TagService) Create(ctx context.Context, tag models.Tag, meta models.AuditMeta) (models.Tag, error) {
+	audit := models.NewAuditLog(meta, models.AuditActionCreate, models.AuditEntityTag)
+	instance, err := t.tagRepository.Create(ctx, tag, audit)
 	if err != nil {
-		return models.Tag{}, err
+		return models.Tag{}, tagError(err, tag, 0)
 	}
 	return instance, nil
 }
 
 //Update ...
-func (t TagService) Update(tagID uint,  tag models.Tag) (models.Tag, error) {
-	instance, err := t.tagRepository.Update(tagID, tag)
+func (t TagService) Update(ctx context.Context, tagID uint,  tag models.Tag, meta models.AuditMeta) (models.Tag, error) {
+	audit := models.NewAuditLog(meta, models.AuditActionUpdate, models.AuditEntityTag)
+	instance, err := t.tagRepository.Update(ctx, tagID, tag, audit)
+	t.newsCache.InvalidateAll(ctx)
 	if err != nil {
-		return models.Tag{}, err
+		return models.Tag{}, tagError(err, tag, tagID)
 	}
 	return instance, nil
 }
 
 //Delete ...
-func (t TagService) Delete(tagID uint) (error) {
-	err := t.tagRepository.Delete(tagID)
-	return err
+func (t TagService) Delete(ctx context.Context, tagID uint, meta models.AuditMeta) (error) {
+	audit := models.NewAuditLog(meta, models.AuditActionDelete, models.AuditEntityTag)
+	err := t.tagRepository.Delete(ctx, tagID, audit)
+	t.newsCache.InvalidateAll(ctx)
+	return domainError(err, "tag", tagID)
+}
+
+//Restore brings back a deleted tag on the news it was linked to
+func (t TagService) Restore(ctx context.Context, tagID uint, meta models.AuditMeta) (models.Tag, error) {
+	audit := models.NewAuditLog(meta, models.AuditActionRestore, models.AuditEntityTag)
+	instance, err := t.tagRepository.Restore(ctx, tagID, audit)
+	t.newsCache.InvalidateAll(ctx)
+	if err != nil {
+		return models.Tag{}, domainError(err, "deleted tag", tagID)
+	}
+	return instance, nil
 }
 
 //List ...
-func (t TagService) List() (models.TagsList, error) {
-	response, err := t.tagRepository.List()
+func (t TagService) List(ctx context.Context) (models.TagsList, error) {
+	response, err := t.tagRepository.List(ctx)
 	if err != nil {
-		return models.TagsList{}, err
+		return models.TagsList{}, domainError(err, "tag", 0)
 	}
 	return models.TagsList{Data: response}, nil
-}
\ No newline at end of file
+}
+
+//Merge folds the source tags into the target tag, the news tagged with a source end up tagged with the target
+func (t TagService) Merge(ctx context.Context, targetID uint, sourceIDs []uint, meta models.AuditMeta) (models.Tag, error) {
+	seen := map[uint]bool{}
+	var uniqueIDs []uint
+	for _, sourceID := range sourceIDs {
+		if sourceID == targetID {
+			return models.Tag{}, invalidInput("cannot merge tag %d into itself", targetID)
+		}
+		if !seen[sourceID] {
+			seen[sourceID] = true
+			uniqueIDs = append(uniqueIDs, sourceID)
+		}
+	}
+	if len(uniqueIDs) == 0 {
+		return models.Tag{}, invalidInput("no tags to merge")
+	}
+	audit := models.NewAuditLog(meta, models.AuditActionMerge, models.AuditEntityTag)
+	instance, err := t.tagRepository.Merge(ctx, targetID, uniqueIDs, audit)
+	t.newsCache.InvalidateAll(ctx)
+	if err != nil {
+		return models.Tag{}, domainError(err, "tag", 0)
+	}
+	return instance, nil
+}
+
+//tagError reports a duplicate name with the name taken, tag names being unique
+func tagError(err error, tag models.Tag, tagID uint) error {
+	if errors.Is(err, repositories.ErrDuplicate) {
+		return ConflictError{Reason: fmt.Sprintf("a tag named %q already exists", tag.Name)}
+	}
+	return domainError(err, "tag", tagID)
+}
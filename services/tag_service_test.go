@@ -1,10 +1,14 @@
 package services
 
 import (
+	"context"
 	"fmt"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	mockRepositories "news-topic-api/mocks/repositories"
+	mockServices "news-topic-api/mocks/services"
 	"news-topic-api/models"
+	"news-topic-api/repositories"
 	"reflect"
 	"testing"
 )
@@ -30,55 +34,90 @@ func getExpectedTagListOutput() models.TagsList {
 func TestCreateTagSuccessReturnCreatedEntity(t *testing.T) {
 	mockedTagRepository := new(mockRepositories.ITagRepository)
 	mockTagEntity := getMockTag()
-	mockedTagRepository.On("Create", mockTagEntity).Return(mockTagEntity, nil)
-	tagService := InitTagService(mockedTagRepository)
-	response, err  := tagService.Create(mockTagEntity)
+	mockedTagRepository.On("Create", mock.Anything, mockTagEntity, mock.Anything).Return(mockTagEntity, nil)
+	tagService := InitTagService(mockedTagRepository, NoNewsCache{})
+	response, err  := tagService.Create(context.Background(), mockTagEntity, getMockAuditMeta())
 	assert.Nil(t, err, "There should be no error")
 	assert.True(t, reflect.DeepEqual(mockTagEntity, response) , "Response should be same as input")
 }
 
+func TestCreateTagDuplicateNameReturnConflict(t *testing.T) {
+	mockedTagRepository := new(mockRepositories.ITagRepository)
+	mockTagEntity := getMockTag()
+	mockedTagRepository.On("Create", mock.Anything, mockTagEntity, mock.Anything).Return(models.Tag{}, fmt.Errorf("%w: unique violation", repositories.ErrDuplicate))
+	tagService := InitTagService(mockedTagRepository, NoNewsCache{})
+	_, err  := tagService.Create(context.Background(), mockTagEntity, getMockAuditMeta())
+	assert.Equal(t, ConflictError{Reason: `a tag named "crypto" already exists`}, err)
+}
+func TestDeleteTagMissingReturnNotFound(t *testing.T) {
+	mockedTagRepository := new(mockRepositories.ITagRepository)
+	mockedTagRepository.On("Delete", mock.Anything, uint(4), mock.Anything).Return(repositories.ErrNotFound)
+	tagService := InitTagService(mockedTagRepository, NoNewsCache{})
+	err := tagService.Delete(context.Background(), uint(4), getMockAuditMeta())
+	assert.Equal(t, NotFoundError{Entity: "tag", ID: 4}, err)
+	assert.EqualError(t, err, "tag 4 not found")
+}
 func TestCreateTagFailedReturnError(t *testing.T) {
 	mockedTagRepository := new(mockRepositories.ITagRepository)
 	mockTagEntity := getMockTag()
-	mockedTagRepository.On("Create", mockTagEntity).Return(models.Tag{}, fmt.Errorf("Tag creation failed"))
-	tagService := InitTagService(mockedTagRepository)
-	_, err  := tagService.Create(mockTagEntity)
+	mockedTagRepository.On("Create", mock.Anything, mockTagEntity, mock.Anything).Return(models.Tag{}, fmt.Errorf("Tag creation failed"))
+	tagService := InitTagService(mockedTagRepository, NoNewsCache{})
+	_, err  := tagService.Create(context.Background(), mockTagEntity, getMockAuditMeta())
 	assert.NotNil(t, err, "There should be an error")
 }
 
 func TestUpdateTagSuccessReturnUpdatedEntity(t *testing.T) {
+	newsCache := new(mockServices.INewsCacheInvalidator)
+	newsCache.On("InvalidateAll", mock.Anything).Return()
 	mockedTagRepository := new(mockRepositories.ITagRepository)
 	mockTagEntity := getMockTag()
-	mockedTagRepository.On("Update", uint(1),  mockTagEntity).Return(mockTagEntity,nil)
-	tagService := InitTagService(mockedTagRepository)
-	response, err  := tagService.Update(uint(1), mockTagEntity)
+	mockedTagRepository.On("Update", mock.Anything, uint(1),  mockTagEntity, mock.Anything).Return(mockTagEntity,nil)
+	tagService := InitTagService(mockedTagRepository, newsCache)
+	response, err  := tagService.Update(context.Background(), uint(1), mockTagEntity, getMockAuditMeta())
 	assert.Nil(t, err, "There should be no error")
 	assert.True(t, reflect.DeepEqual(mockTagEntity, response) , "Response should be same as input")
+	newsCache.AssertNumberOfCalls(t, "InvalidateAll", 1)
 }
 
 func TestUpdateTagFailedReturnError(t *testing.T) {
 	mockedTagRepository := new(mockRepositories.ITagRepository)
 	mockTagEntity := getMockTag()
-	mockedTagRepository.On("Update", uint(1),  mockTagEntity).Return(models.Tag{}, fmt.Errorf("Tag with specified id not found"))
-	tagService := InitTagService(mockedTagRepository)
-	_, err  := tagService.Update(uint(1), mockTagEntity)
+	mockedTagRepository.On("Update", mock.Anything, uint(1),  mockTagEntity, mock.Anything).Return(models.Tag{}, fmt.Errorf("Tag with specified id not found"))
+	tagService := InitTagService(mockedTagRepository, NoNewsCache{})
+	_, err  := tagService.Update(context.Background(), uint(1), mockTagEntity, getMockAuditMeta())
 	assert.NotNil(t, err, "There should be an error")
 }
 
 func TestDeleteTagSuccessReturnNoError(t *testing.T) {
+	newsCache := new(mockServices.INewsCacheInvalidator)
+	newsCache.On("InvalidateAll", mock.Anything).Return()
 	mockedTagRepository := new(mockRepositories.ITagRepository)
-	mockedTagRepository.On("Delete", uint(1)).Return(nil)
-	tagService := InitTagService(mockedTagRepository)
-	err  := tagService.Delete(uint(1))
+	mockedTagRepository.On("Delete", mock.Anything, uint(1), mock.Anything).Return(nil)
+	tagService := InitTagService(mockedTagRepository, newsCache)
+	err  := tagService.Delete(context.Background(), uint(1), getMockAuditMeta())
 	assert.Nil(t, err, "There should be no error")
+	newsCache.AssertNumberOfCalls(t, "InvalidateAll", 1)
+}
 
+func TestRestoreTagSuccessShouldInvalidateNewsCache(t *testing.T) {
+	newsCache := new(mockServices.INewsCacheInvalidator)
+	newsCache.On("InvalidateAll", mock.Anything).Return()
+	mockedTagRepository := new(mockRepositories.ITagRepository)
+	mockedTagRepository.On("Restore", mock.Anything, uint(1), mock.MatchedBy(func(audit models.AuditLog) bool {
+		return audit.Action == models.AuditActionRestore
+	})).Return(getMockTag(), nil)
+	tagService := InitTagService(mockedTagRepository, newsCache)
+	restored, err := tagService.Restore(context.Background(), uint(1), getMockAuditMeta())
+	assert.Nil(t, err, "There should be no error")
+	assert.Equal(t, getMockTag().Name, restored.Name)
+	newsCache.AssertNumberOfCalls(t, "InvalidateAll", 1)
 }
 
 func TestDeleteTagFailedReturnError(t *testing.T) {
 	mockedTagRepository := new(mockRepositories.ITagRepository)
-	mockedTagRepository.On("Delete", uint(1)).Return(fmt.Errorf("Tag with specified id not found"))
-	tagService := InitTagService(mockedTagRepository)
-	err  := tagService.Delete(uint(1))
+	mockedTagRepository.On("Delete", mock.Anything, uint(1), mock.Anything).Return(fmt.Errorf("Tag with specified id not found"))
+	tagService := InitTagService(mockedTagRepository, NoNewsCache{})
+	err  := tagService.Delete(context.Background(), uint(1), getMockAuditMeta())
 	assert.NotNil(t, err, "There should be an error")
 }
 
@@ -86,9 +125,9 @@ func TestListTagSuccessReturnEntities(t *testing.T) {
 	mockedTagRepository := new(mockRepositories.ITagRepository)
 	mockTagEntities := getMockTagList()
 	expectedOutput := getExpectedTagListOutput()
-	tagService := InitTagService(mockedTagRepository)
-	mockedTagRepository.On("List").Return(mockTagEntities, nil)
-	response, err  := tagService.List()
+	tagService := InitTagService(mockedTagRepository, NoNewsCache{})
+	mockedTagRepository.On("List", mock.Anything).Return(mockTagEntities, nil)
+	response, err  := tagService.List(context.Background())
 	assert.Nil(t, err, "There should be no error")
 	assert.Equal(t, len(expectedOutput.Data), len(response.Data), "Should return correct length")
 	assert.True(t, reflect.DeepEqual(expectedOutput.Data, response.Data), "List should be the same")
@@ -96,8 +135,47 @@ func TestListTagSuccessReturnEntities(t *testing.T) {
 
 func TestListTagFailedReturnError(t *testing.T) {
 	mockedTagRepository := new(mockRepositories.ITagRepository)
-	tagService := InitTagService(mockedTagRepository)
-	mockedTagRepository.On("List").Return([]models.Tag{}, fmt.Errorf("Records not available"))
-	_, err  := tagService.List()
+	tagService := InitTagService(mockedTagRepository, NoNewsCache{})
+	mockedTagRepository.On("List", mock.Anything).Return([]models.Tag{}, fmt.Errorf("Records not available"))
+	_, err  := tagService.List(context.Background())
+	assert.NotNil(t, err, "There should be an error")
+}
+
+func TestMergeTagSuccessDeduplicatesSources(t *testing.T) {
+	newsCache := new(mockServices.INewsCacheInvalidator)
+	newsCache.On("InvalidateAll", mock.Anything).Return()
+	mockedTagRepository := new(mockRepositories.ITagRepository)
+	mockTagEntity := getMockTag()
+	mockedTagRepository.On("Merge", mock.Anything, uint(1), []uint{2, 3}, mock.MatchedBy(func(audit models.AuditLog) bool {
+		return audit.Action == models.AuditActionMerge && audit.EntityType == models.AuditEntityTag
+	})).Return(mockTagEntity, nil)
+	tagService := InitTagService(mockedTagRepository, newsCache)
+	response, err := tagService.Merge(context.Background(), uint(1), []uint{2, 3, 2}, getMockAuditMeta())
+	assert.Nil(t, err, "There should be no error")
+	assert.Equal(t, mockTagEntity, response)
+	mockedTagRepository.AssertExpectations(t)
+	newsCache.AssertNumberOfCalls(t, "InvalidateAll", 1)
+}
+
+func TestMergeTagIntoItselfReturnError(t *testing.T) {
+	mockedTagRepository := new(mockRepositories.ITagRepository)
+	tagService := InitTagService(mockedTagRepository, NoNewsCache{})
+	_, err := tagService.Merge(context.Background(), uint(1), []uint{2, 1}, getMockAuditMeta())
+	assert.NotNil(t, err, "There should be an error")
+	mockedTagRepository.AssertNotCalled(t, "Merge", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestMergeTagWithoutSourcesReturnError(t *testing.T) {
+	mockedTagRepository := new(mockRepositories.ITagRepository)
+	tagService := InitTagService(mockedTagRepository, NoNewsCache{})
+	_, err := tagService.Merge(context.Background(), uint(1), nil, getMockAuditMeta())
+	assert.NotNil(t, err, "There should be an error")
+}
+
+func TestMergeTagFailedReturnError(t *testing.T) {
+	mockedTagRepository := new(mockRepositories.ITagRepository)
+	mockedTagRepository.On("Merge", mock.Anything, uint(1), []uint{2}, mock.Anything).Return(models.Tag{}, fmt.Errorf("Tag merge failed"))
+	tagService := InitTagService(mockedTagRepository, NoNewsCache{})
+	_, err := tagService.Merge(context.Background(), uint(1), []uint{2}, getMockAuditMeta())
 	assert.NotNil(t, err, "There should be an error")
 }
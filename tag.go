@@ -0,0 +1,71 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"news-topic-api/infrastructures"
+	"news-topic-api/repositories"
+	"news-topic-api/services"
+	"os"
+	"strconv"
+)
+
+const tagUsage = `Usage: news-topic-api tag <command> [flags]
+
+Commands:
+  merge -into <id> <id...>  move the news of the given tags to the -into tag and delete them
+`
+
+//runTag runs a tag subcommand and returns the exit code
+func runTag(args []string) int {
+	if len(args) == 0 || args[0] == "-h" || args[0] == "--help" || args[0] == "help" {
+		fmt.Fprint(os.Stderr, tagUsage)
+		if len(args) == 0 {
+			return exitUsage
+		}
+		return exitOK
+	}
+	if args[0] != "merge" {
+		fmt.Fprintf(os.Stderr, "unknown tag command %q\n\n%s", args[0], tagUsage)
+		return exitUsage
+	}
+	flags := newFlagSet("tag merge", tagUsage)
+	into := flags.Uint("into", 0, "id of the tag the others are merged into")
+	if exitCode, ok := parseFlags(flags, args[1:]); !ok {
+		return exitCode
+	}
+	if *into == 0 || flags.NArg() == 0 {
+		fmt.Fprint(os.Stderr, "tag merge needs -into and at least one tag id\n\n")
+		flags.Usage()
+		return exitUsage
+	}
+	sourceIDs, err := parseIDs(flags.Args())
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitUsage
+	}
+	_, db, err := openDatabase()
+	if err != nil {
+		return fail(err)
+	}
+	defer infrastructures.CloseDB(db)
+	tagService := services.InitTagService(repositories.InitTagRepository(db), services.NoNewsCache{})
+	tag, err := tagService.Merge(context.Background(), uint(*into), sourceIDs, cliAuditMeta())
+	if err != nil {
+		return fail(err)
+	}
+	fmt.Printf("merged %d tags into %d %s\n", len(sourceIDs), tag.ID, tag.Name)
+	return exitOK
+}
+
+func parseIDs(args []string) ([]uint, error) {
+	ids := make([]uint, 0, len(args))
+	for _, arg := range args {
+		id, err := strconv.ParseUint(arg, 10, 64)
+		if err != nil || id == 0 {
+			return nil, fmt.Errorf("invalid tag id %q", arg)
+		}
+		ids = append(ids, uint(id))
+	}
+	return ids, nil
+}